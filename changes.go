@@ -0,0 +1,66 @@
+package clippy
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/neilberkman/clippy/pkg/clipboard"
+)
+
+// changeRecord notes the pasteboard change count right after a clippy write and
+// when it happened. macOS doesn't timestamp pasteboard changes, so this is the
+// only way to tell how old the clipboard content is.
+type changeRecord struct {
+	ChangeCount int       `json:"change_count"`
+	Time        time.Time `json:"time"`
+}
+
+// changeRecordPath returns where the last write is recorded; tests point it elsewhere
+var changeRecordPath = func() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "clippy", "last-change.json")
+}
+
+// recordChange remembers the current change count and time. It is best effort:
+// a failure only means the content age shows as unknown later.
+func recordChange() {
+	path := changeRecordPath()
+	if path == "" {
+		return
+	}
+	data, err := json.Marshal(changeRecord{ChangeCount: clipboard.ChangeCount(), Time: time.Now()})
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return
+	}
+	_ = os.WriteFile(path, data, 0644)
+}
+
+// LastChanged returns when the clipboard content was put there. It is only known
+// if clippy (or the MCP server) made the last change; when another app has copied
+// since, or nothing was recorded, ok is false.
+func LastChanged() (changed time.Time, ok bool) {
+	path := changeRecordPath()
+	if path == "" {
+		return time.Time{}, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return time.Time{}, false
+	}
+	var record changeRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return time.Time{}, false
+	}
+	if record.ChangeCount != clipboard.ChangeCount() {
+		return time.Time{}, false
+	}
+	return record.Time, true
+}
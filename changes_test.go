@@ -0,0 +1,34 @@
+package clippy
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLastChanged(t *testing.T) {
+	mem := useMemoryClipboard(t)
+
+	if _, ok := LastChanged(); ok {
+		t.Error("LastChanged() before any copy = ok, want unknown")
+	}
+
+	before := time.Now()
+	if err := CopyText("hello"); err != nil {
+		t.Fatalf("CopyText returned error: %v", err)
+	}
+	changed, ok := LastChanged()
+	if !ok {
+		t.Fatal("LastChanged() after copy = unknown, want the copy time")
+	}
+	if changed.Before(before) || changed.After(time.Now()) {
+		t.Errorf("LastChanged() = %v, want a time during the copy", changed)
+	}
+
+	// Another app replacing the content makes the age unknown
+	if err := mem.CopyText("from another app"); err != nil {
+		t.Fatalf("CopyText returned error: %v", err)
+	}
+	if _, ok := LastChanged(); ok {
+		t.Error("LastChanged() after an outside change = ok, want unknown")
+	}
+}
@@ -5,29 +5,38 @@ package clippy
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"image"
 	"image/gif"
 	"image/jpeg"
 	"image/png"
 	"io"
+	"mime"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/gabriel-vasile/mimetype"
+	"github.com/neilberkman/clippy/internal/fsutil"
 	"github.com/neilberkman/clippy/pkg/clipboard"
 	"github.com/neilberkman/clippy/pkg/recent"
 	_ "golang.org/x/image/tiff" // Register TIFF decoder
 )
 
+// ErrFileNotFound is returned (wrapped) when a path given to a copy function doesn't exist
+var ErrFileNotFound = errors.New("file not found")
+
 // CopyResult contains information about what was copied and how
 type CopyResult struct {
-	Method   string // "UTI", "MIME", or "content"
+	Method   string // "UTI", "MIME", "content", or "link" (a bookmark file's URL)
 	Type     string // The detected type (UTI or MIME)
 	AsText   bool   // Whether content was copied as text
 	FilePath string // The file path that was copied
+
+	// Stats counts the copied text. Only set for text copies with CopyOptions.Stats.
+	Stats *TextStats
 }
 
 // Copy intelligently copies a file to clipboard.
@@ -45,139 +54,219 @@ func CopyWithResult(path string) (*CopyResult, error) {
 
 // CopyWithResultAndMode is like CopyWithResult but allows forcing text mode
 func CopyWithResultAndMode(path string, forceTextMode bool) (*CopyResult, error) {
-	absPath, err := filepath.Abs(path)
+	return CopyWithResultAndOptions(path, forceTextMode, CopyOptions{})
+}
+
+// CopyWithResultAndOptions is like CopyWithResultAndMode but can wrap text content
+// in a template (CopyOptions.Wrap)
+func CopyWithResultAndOptions(path string, forceTextMode bool, opts CopyOptions) (*CopyResult, error) {
+	absPath, err := existingPath(path)
 	if err != nil {
-		return nil, fmt.Errorf("invalid path %s: %w", path, err)
+		return nil, err
 	}
 
-	// Check if file exists
-	if _, err := os.Stat(absPath); os.IsNotExist(err) {
-		return nil, fmt.Errorf("file not found: %s", absPath)
+	decision, err := decideCopy(absPath, forceTextMode, opts, false)
+	if err != nil {
+		return nil, err
 	}
 
-	// If forceTextMode is false (default), always copy as file reference
-	if !forceTextMode {
-		if err := clipboard.CopyFile(absPath); err != nil {
+	if !decision.AsText {
+		if err := writeClipboardFile(absPath); err != nil {
 			return nil, fmt.Errorf("could not copy file to clipboard: %w", err)
 		}
+		result := decision.CopyResult
+		return &result, nil
+	}
 
-		// Still detect the type for informational purposes
-		uti, _ := clipboard.GetUTIForFile(absPath)
-		typeStr := uti
-		method := "UTI"
-		if typeStr == "" || strings.HasPrefix(typeStr, "dyn.") {
-			mtype, _ := mimetype.DetectFile(absPath)
-			if mtype != nil {
-				typeStr = mtype.String()
-				method = "MIME"
-			}
+	content, err := os.ReadFile(absPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not read file content %s: %w", absPath, err)
+	}
+	if decision.Method == "link" {
+		link := parseLinkFile(content, absPath)
+		if link == "" {
+			return nil, fmt.Errorf("no URL found in %s", absPath)
 		}
+		content = []byte(link)
+	}
+	// Use auto-detection for proper clipboard type
+	text, err := copyFileText(string(content), absPath, opts)
+	if err != nil {
+		return nil, fmt.Errorf("could not copy text to clipboard: %w", err)
+	}
+	return textResult(decision.Method, decision.Type, absPath, text, opts), nil
+}
 
-		return &CopyResult{
-			Method:   method,
-			Type:     typeStr,
-			AsText:   false,
-			FilePath: absPath,
-		}, nil
+// CopyDecision explains how CopyWithResultAndOptions would copy a file: the
+// result plus the values it was based on and the rule that decided
+type CopyDecision struct {
+	CopyResult
+
+	UTI           string // UTI macOS reports for the file ("" if none)
+	UTIIsText     bool   // Whether the UTI conforms to a text type
+	MIME          string // MIME type sniffed from the content ("" if not detected)
+	MIMEIsText    bool   // Whether the MIME type is textual
+	TextRequested bool   // Text mode was forced or the extension is in TextExtensions
+	Rule          string // The rule that decided between text and reference
+}
+
+// ExplainCopy works out how CopyWithResultAndOptions would copy path and why,
+// without touching the clipboard. The MIME type is always detected, even when the
+// decision doesn't need it.
+func ExplainCopy(path string, forceTextMode bool, opts CopyOptions) (*CopyDecision, error) {
+	absPath, err := existingPath(path)
+	if err != nil {
+		return nil, err
 	}
+	return decideCopy(absPath, forceTextMode, opts, true)
+}
 
-	// Force text mode is enabled, check if file is actually text
-	// Try UTI detection first (more reliable for macOS)
-	if uti, ok := clipboard.GetUTIForFile(absPath); ok {
-		// For dynamic UTIs (unknown types), skip to MIME detection
-		if strings.HasPrefix(uti, "dyn.") {
-			// Fall through to MIME detection
-		} else if isTextUTI(uti) {
-			content, err := os.ReadFile(absPath)
-			if err != nil {
-				return nil, fmt.Errorf("could not read file content %s: %w", absPath, err)
-			}
-			// Use auto-detection for proper clipboard type
-			if err := CopyTextWithAutoDetection(string(content)); err != nil {
-				return nil, fmt.Errorf("could not copy text to clipboard: %w", err)
-			}
-			return &CopyResult{
-				Method:   "UTI",
-				Type:     uti,
-				AsText:   true,
-				FilePath: absPath,
-			}, nil
-		} else if !forceTextMode {
-			// Non-text UTI and text mode not forced - copy as file
-			if err := clipboard.CopyFile(absPath); err != nil {
-				return nil, fmt.Errorf("could not copy file to clipboard: %w", err)
+// existingPath returns path as an absolute path, or ErrFileNotFound
+func existingPath(path string) (string, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("invalid path %s: %w", path, err)
+	}
+	if _, err := os.Stat(absPath); os.IsNotExist(err) {
+		return "", fmt.Errorf("%w: %s", ErrFileNotFound, absPath)
+	}
+	return absPath, nil
+}
+
+// decideCopy chooses between copying a file's text and a reference to it. Files
+// copy as references unless text mode is forced (or the extension is listed in
+// TextExtensions); then the UTI is tried first as it's more reliable on macOS,
+// with the sniffed MIME type as the fallback. ReferenceExtensions always win.
+// MIME detection is skipped when the UTI settles it, unless detectAll is set.
+func decideCopy(absPath string, forceTextMode bool, opts CopyOptions, detectAll bool) (*CopyDecision, error) {
+	d := &CopyDecision{CopyResult: CopyResult{FilePath: absPath}}
+
+	// Listed extensions get the text treatment by default, but reference
+	// extensions win even over forced text mode
+	byExtension := false
+	if !forceTextMode && hasExtension(absPath, opts.TextExtensions) {
+		forceTextMode = true
+		byExtension = true
+	}
+	referenceListed := hasExtension(absPath, opts.ReferenceExtensions)
+	if referenceListed {
+		forceTextMode = false
+	}
+	d.TextRequested = forceTextMode
+
+	d.UTI, _ = clipboard.GetUTIForFile(absPath)
+	// Dynamic UTIs (unknown types) say nothing about the content
+	dynamic := d.UTI == "" || strings.HasPrefix(d.UTI, "dyn.")
+	d.UTIIsText = !dynamic && isTextUTI(d.UTI)
+
+	detectMIME := func() error {
+		mtype, err := mimetype.DetectFile(absPath)
+		if err != nil {
+			return fmt.Errorf("could not detect file type for %s: %w", absPath, err)
+		}
+		d.MIME = mtype.String()
+		d.MIMEIsText = isTextualMimeType(d.MIME)
+		return nil
+	}
+
+	// If forceTextMode is false (default), always copy as file reference, still
+	// detecting the type for informational purposes
+	if !forceTextMode {
+		d.Method, d.Type = "UTI", d.UTI
+		if dynamic || detectAll {
+			if detectMIME() == nil && dynamic {
+				d.Method, d.Type = "MIME", d.MIME
 			}
-			return &CopyResult{
-				Method:   "UTI",
-				Type:     uti,
-				AsText:   false,
-				FilePath: absPath,
-			}, nil
 		}
-		// Non-text UTI but text mode forced - fall through to MIME detection
+		if referenceListed {
+			d.Rule = "the extension is in ReferenceExtensions, which always copy as file references"
+		} else {
+			d.Rule = "text mode wasn't requested, so files copy as references"
+		}
+		return d, nil
 	}
 
-	// Fallback to MIME type detection
-	mtype, err := mimetype.DetectFile(absPath)
-	if err != nil {
-		return nil, fmt.Errorf("could not detect file type for %s: %w", absPath, err)
+	if isLinkFile(absPath) {
+		d.Method, d.Type, d.AsText = "link", "public.url", true
+		if detectAll {
+			_ = detectMIME()
+		}
+		d.Rule = "text was requested and bookmark files (.webloc, .url) copy the URL they point at"
+		return d, nil
 	}
 
-	// Text files with force text mode: copy content
-	if forceTextMode && isTextualMimeType(mtype.String()) {
-		content, err := os.ReadFile(absPath)
-		if err != nil {
-			return nil, fmt.Errorf("could not read file content %s: %w", absPath, err)
+	if d.UTIIsText {
+		d.Method, d.Type, d.AsText = "UTI", d.UTI, true
+		if detectAll {
+			_ = detectMIME()
 		}
-		// Use auto-detection for proper clipboard type
-		if err := CopyTextWithAutoDetection(string(content)); err != nil {
-			return nil, fmt.Errorf("could not copy text to clipboard: %w", err)
+		d.Rule = "text was requested and the UTI is a text type"
+		if byExtension {
+			d.Rule = "the extension is in TextExtensions and the UTI is a text type"
 		}
-		return &CopyResult{
-			Method:   "MIME",
-			Type:     mtype.String(),
-			AsText:   true,
-			FilePath: absPath,
-		}, nil
-	} else {
-		// Binary files or text mode not forced: copy file reference
-		if err := clipboard.CopyFile(absPath); err != nil {
-			return nil, fmt.Errorf("could not copy file to clipboard: %w", err)
+		return d, nil
+	}
+
+	// Non-text or unknown UTI: fall back to MIME type detection
+	if err := detectMIME(); err != nil {
+		return nil, err
+	}
+	d.Method, d.Type, d.AsText = "MIME", d.MIME, d.MIMEIsText
+	switch {
+	case d.AsText && byExtension:
+		d.Rule = "the extension is in TextExtensions and the sniffed MIME type is textual"
+	case d.AsText:
+		d.Rule = "text was requested and the sniffed MIME type is textual"
+	default:
+		d.Rule = "text was requested, but neither the UTI nor the MIME type is text, so it copies as a file reference"
+	}
+	return d, nil
+}
+
+// hasExtension reports whether path ends in one of exts, ignoring case and any
+// leading dot on the listed extensions
+func hasExtension(path string, exts []string) bool {
+	ext := strings.TrimPrefix(filepath.Ext(path), ".")
+	if ext == "" {
+		return false
+	}
+	for _, e := range exts {
+		if strings.EqualFold(strings.TrimPrefix(strings.TrimSpace(e), "."), ext) {
+			return true
 		}
-		return &CopyResult{
-			Method:   "MIME",
-			Type:     mtype.String(),
-			AsText:   false,
-			FilePath: absPath,
-		}, nil
 	}
+	return false
 }
 
 // CopyMultiple copies multiple files to clipboard as file references.
+// Paths that resolve to the same file are only copied once (see UniquePaths).
 func CopyMultiple(paths []string) error {
-	if len(paths) == 0 {
-		return fmt.Errorf("no files provided")
-	}
+	return CopyMultipleWithOptions(paths, CopyOptions{})
+}
 
-	// Convert to absolute paths and verify all files exist
-	absPaths := make([]string, 0, len(paths))
+// UniquePaths converts paths to absolute paths and removes duplicates, keeping
+// the first occurrence of each. Overlapping globs ("*.jpg photo.jpg") are the
+// usual source of duplicates. Paths are compared after resolving symlinks, so a
+// link and its target count as one file; the first path given is the one kept.
+func UniquePaths(paths []string) ([]string, error) {
+	seen := make(map[string]bool, len(paths))
+	unique := make([]string, 0, len(paths))
 	for _, path := range paths {
 		absPath, err := filepath.Abs(path)
 		if err != nil {
-			return fmt.Errorf("invalid path %s: %w", path, err)
+			return nil, fmt.Errorf("invalid path %s: %w", path, err)
 		}
-
-		if _, err := os.Stat(absPath); os.IsNotExist(err) {
-			return fmt.Errorf("file not found: %s", absPath)
+		key := absPath
+		if resolved, err := filepath.EvalSymlinks(absPath); err == nil {
+			key = resolved
 		}
-
-		absPaths = append(absPaths, absPath)
-	}
-
-	if err := clipboard.CopyFiles(absPaths); err != nil {
-		return fmt.Errorf("could not copy files to clipboard: %w", err)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, absPath)
 	}
-	return nil
+	return unique, nil
 }
 
 // CopyText copies text content to clipboard.
@@ -187,31 +276,62 @@ func CopyText(text string) error {
 
 // CopyTextWithAutoDetection copies text with auto-detected type
 func CopyTextWithAutoDetection(text string) error {
+	utiType := detectTextUTI(text)
+	if utiType == clipboard.PlainTextType {
+		return writeClipboardText(text)
+	}
+
+	// Use the detected type
+	return writeClipboardTextWithType(text, utiType)
+}
+
+// ErrNotText is returned (wrapped) by AppendText when the clipboard holds files
+// or other content that text can't be appended to
+var ErrNotText = errors.New("clipboard does not hold text")
+
+// AppendText adds text after the text already on the clipboard, joined by
+// separator. If the clipboard is empty the text is simply copied.
+func AppendText(text, separator string) error {
+	if files := GetFiles(); len(files) > 0 {
+		return fmt.Errorf("%w: it holds %d file references", ErrNotText, len(files))
+	}
+	existing, ok := clipboard.GetText()
+	if !ok {
+		if types := clipboard.GetClipboardTypes(); len(types) > 0 {
+			return fmt.Errorf("%w: it holds %s", ErrNotText, types[0])
+		}
+		return CopyText(text)
+	}
+	if existing == "" {
+		return CopyText(text)
+	}
+	return CopyText(existing + separator + text)
+}
+
+// detectTextUTI picks the clipboard type for text from its content, falling back
+// to plain text
+func detectTextUTI(text string) string {
 	// Try to detect the content type
 	mtype := mimetype.Detect([]byte(text))
 	mimeStr := mtype.String()
 
 	// Map common MIME types to UTI types for better macOS integration
-	var utiType string
 	switch {
 	case strings.HasPrefix(mimeStr, "text/html"):
-		utiType = "public.html"
+		return "public.html"
 	case mimeStr == "application/json":
-		utiType = "public.json"
+		return "public.json"
 	case strings.HasPrefix(mimeStr, "text/xml") || mimeStr == "application/xml":
-		utiType = "public.xml"
+		return "public.xml"
 	case strings.HasPrefix(mimeStr, "text/markdown"):
 		// Note: macOS doesn't have a standard markdown UTI, but some apps recognize this
-		utiType = "net.daringfireball.markdown"
+		return "net.daringfireball.markdown"
 	case strings.HasPrefix(mimeStr, "text/rtf") || mimeStr == "application/rtf":
-		utiType = "public.rtf"
+		return "public.rtf"
 	default:
 		// Fall back to plain text for other text types
-		return clipboard.CopyText(text)
+		return clipboard.PlainTextType
 	}
-
-	// Use the detected type
-	return clipboard.CopyTextWithType(text, utiType)
 }
 
 // CopyTextWithType copies text with a specific MIME type or UTI
@@ -220,19 +340,25 @@ func CopyTextWithType(text string, typeIdentifier string) error {
 	if strings.Contains(typeIdentifier, "/") {
 		typeIdentifier = mimeToUTI(typeIdentifier)
 	}
-	return clipboard.CopyTextWithType(text, typeIdentifier)
+	return writeClipboardTextWithType(text, typeIdentifier)
 }
 
 // CopyFileAsTextWithType copies a file's text content with a specific MIME type or UTI.
 // This is a core function that handles file I/O - interface layer should not read files directly.
 func CopyFileAsTextWithType(path string, typeIdentifier string) error {
+	return CopyFileAsTextWithOptions(path, CopyOptions{MimeType: typeIdentifier})
+}
+
+// CopyFileAsTextWithOptions copies a file's text content as opts.MimeType (detected
+// if empty), wrapped in opts.Wrap if set
+func CopyFileAsTextWithOptions(path string, opts CopyOptions) error {
 	absPath, err := filepath.Abs(path)
 	if err != nil {
 		return fmt.Errorf("invalid path %s: %w", path, err)
 	}
 
 	if _, err := os.Stat(absPath); os.IsNotExist(err) {
-		return fmt.Errorf("file not found: %s", absPath)
+		return fmt.Errorf("%w: %s", ErrFileNotFound, absPath)
 	}
 
 	content, err := os.ReadFile(absPath)
@@ -240,11 +366,44 @@ func CopyFileAsTextWithType(path string, typeIdentifier string) error {
 		return fmt.Errorf("could not read file %s: %w", absPath, err)
 	}
 
-	return CopyTextWithType(string(content), typeIdentifier)
+	_, err = copyFileText(string(content), absPath, opts)
+	return err
+}
+
+// copyFileText copies a text file's content, wrapped in opts.Wrap if set. The
+// result is copied as opts.MimeType when given, otherwise its type is detected.
+// Returns the text that was copied.
+func copyFileText(content string, path string, opts CopyOptions) (string, error) {
+	if opts.Wrap != "" {
+		content = ApplyTemplate(opts.Wrap, content, filepath.Base(path))
+	}
+	if opts.MimeType != "" {
+		return content, CopyTextWithType(content, opts.MimeType)
+	}
+	return content, CopyTextWithAutoDetection(content)
+}
+
+// textResult describes a file whose content was copied as text
+func textResult(method, typeStr, path, text string, opts CopyOptions) *CopyResult {
+	result := &CopyResult{
+		Method:   method,
+		Type:     typeStr,
+		AsText:   true,
+		FilePath: path,
+	}
+	if opts.Stats {
+		stats := CountText(text)
+		result.Stats = &stats
+	}
+	return result
 }
 
-// mimeToUTI converts common MIME types to macOS UTI
+// mimeToUTI converts common MIME types to macOS UTI, checking the user's
+// SetTypeOverrides table first
 func mimeToUTI(mime string) string {
+	if uti, ok := overrideUTI(mime); ok {
+		return uti
+	}
 	switch mime {
 	case "text/html":
 		return "public.html"
@@ -264,9 +423,20 @@ func mimeToUTI(mime string) string {
 	}
 }
 
+// IsTextualMimeType reports whether content of the given MIME type is treated
+// as text (and copied as text rather than as a file reference)
+func IsTextualMimeType(mimeType string) bool {
+	return isTextualMimeType(mimeType)
+}
+
 // isTextualMimeType checks if a MIME type represents textual content
 // that should be copied as text rather than binary
 func isTextualMimeType(mimeType string) bool {
+	// Types the user listed with SetTypeOverrides
+	if overrideIsText(mimeType) {
+		return true
+	}
+
 	// All text/* types are textual
 	if strings.HasPrefix(mimeType, "text/") {
 		return true
@@ -320,6 +490,17 @@ func CopyData(reader io.Reader) error {
 
 // CopyDataWithTempDir is like CopyData but allows specifying a custom temp directory.
 func CopyDataWithTempDir(reader io.Reader, tempDir string) error {
+	return CopyDataWithMimeHint(reader, tempDir, "")
+}
+
+// CopyDataWithMimeHint is like CopyDataWithTempDir but uses mimeHint (e.g. an HTTP
+// Content-Type) instead of content sniffing when the hint is a specific, known MIME type.
+func CopyDataWithMimeHint(reader io.Reader, tempDir string, mimeHint string) error {
+	return copyData(reader, tempDir, mimeHint, CopyOptions{})
+}
+
+// copyData implements CopyDataWithMimeHint and CopyDataWithOptions
+func copyData(reader io.Reader, tempDir string, mimeHint string, opts CopyOptions) error {
 	var buf bytes.Buffer
 	if _, err := io.Copy(&buf, reader); err != nil {
 		return fmt.Errorf("failed to read data: %w", err)
@@ -330,21 +511,88 @@ func CopyDataWithTempDir(reader io.Reader, tempDir string) error {
 		return fmt.Errorf("input data was empty")
 	}
 
-	// Detect MIME type from content
+	// An explicit type skips detection entirely
+	if opts.MimeType != "" {
+		return copyDataAsType(data, tempDir, opts)
+	}
+
+	// Detect MIME type from content, unless the hint tells us what it is
 	mtype := mimetype.Detect(data)
+	hinted := false
+	if hintType := lookupMimeHint(mimeHint); hintType != nil {
+		mtype = hintType
+		hinted = true
+	}
 	mimeStr := mtype.String()
 
 	// Text data: copy as text with proper type
-	if isTextualMimeType(mimeStr) {
-		// Use our auto-detection to set proper clipboard type
-		if err := CopyTextWithAutoDetection(string(data)); err != nil {
-			return fmt.Errorf("could not copy text to clipboard: %w", err)
+	if isTextualMimeType(mimeStr) && !opts.AsFile {
+		// A hinted type we know the UTI for is used as-is, otherwise auto-detect
+		text := wrapText(string(data), opts)
+		utiType := detectTextUTI(text)
+		if hinted && mimeToUTI(mimeStr) != mimeStr {
+			utiType = mimeToUTI(mimeStr)
 		}
-		return nil
+		return copyDataAsText(text, utiType, opts)
+	}
+
+	return copyDataAsFile(data, tempDir, mimeStr, tempFileExtension(mtype), opts)
+}
+
+// copyDataAsType copies data as opts.MimeType without looking at the content.
+// Textual MIME types and UTIs are copied as text of that type (unless AsFile is
+// set), anything else is saved to a temp file with the type's extension.
+func copyDataAsType(data []byte, tempDir string, opts CopyOptions) error {
+	typeIdentifier := strings.TrimSpace(opts.MimeType)
+	if !strings.Contains(typeIdentifier, "/") {
+		if !opts.AsFile {
+			return copyDataAsText(wrapText(string(data), opts), typeIdentifier, opts)
+		}
+		ext := getFileExtensionFromUTI(typeIdentifier)
+		if ext == "" {
+			ext = ".bin"
+		}
+		return copyDataAsFile(data, tempDir, typeIdentifier, ext, opts)
+	}
+
+	mimeStr := strings.ToLower(typeIdentifier)
+	if idx := strings.Index(mimeStr, ";"); idx >= 0 {
+		mimeStr = strings.TrimSpace(mimeStr[:idx])
+	}
+	if isTextualMimeType(mimeStr) && !opts.AsFile {
+		return copyDataAsText(wrapText(string(data), opts), mimeToUTI(mimeStr), opts)
+	}
+
+	ext := ".bin"
+	if mtype := mimetype.Lookup(mimeStr); mtype != nil {
+		ext = tempFileExtension(mtype)
+	} else if exts, err := mime.ExtensionsByType(mimeStr); err == nil && len(exts) > 0 {
+		ext = exts[0]
+	}
+	return copyDataAsFile(data, tempDir, mimeStr, ext, opts)
+}
+
+// copyDataAsText copies piped text to the clipboard as the given type
+func copyDataAsText(text string, utiType string, opts CopyOptions) error {
+	if opts.SkipIfSame && textOnClipboard(text, utiType, opts.Merge) {
+		return ErrAlreadyOnClipboard
+	}
+	if err := writeText(text, utiType, opts); err != nil {
+		return fmt.Errorf("could not copy text to clipboard: %w", err)
 	}
+	return nil
+}
 
-	// Binary data: save to temp file and copy reference
-	tmpFile, err := os.CreateTemp(tempDir, "clippy-*"+mtype.Extension())
+// copyDataAsFile saves piped data of type mimeStr to a temp file ending in ext
+// and copies a reference to it
+func copyDataAsFile(data []byte, tempDir string, mimeStr string, ext string, opts CopyOptions) error {
+	if opts.SkipIfSame && dataOnClipboard(data, opts.Name, ext) {
+		return ErrAlreadyOnClipboard
+	}
+	if skipForDryRun("copy %d bytes of %s as a file reference to a new temp file %s", len(data), mimeStr, describeTempFile(opts.Name, ext)) {
+		return nil
+	}
+	tmpFile, err := createTempFile(tempDir, opts.Name, ext)
 	if err != nil {
 		return fmt.Errorf("could not create temporary file: %w", err)
 	}
@@ -358,12 +606,72 @@ func CopyDataWithTempDir(reader io.Reader, tempDir string) error {
 		return fmt.Errorf("could not write to temporary file: %w", err)
 	}
 
-	if err := clipboard.CopyFile(tmpFile.Name()); err != nil {
+	if err := writeFiles([]string{tmpFile.Name()}, opts); err != nil {
 		return fmt.Errorf("could not copy file to clipboard: %w", err)
 	}
 	return nil
 }
 
+// lookupMimeHint resolves a MIME hint to a known type.
+// Returns nil for empty, unknown, or generic hints so content sniffing is used instead.
+func lookupMimeHint(mimeHint string) *mimetype.MIME {
+	mimeHint = strings.ToLower(strings.TrimSpace(mimeHint))
+	if idx := strings.Index(mimeHint, ";"); idx >= 0 {
+		mimeHint = strings.TrimSpace(mimeHint[:idx])
+	}
+	if mimeHint == "" || mimeHint == "application/octet-stream" {
+		return nil
+	}
+	return mimetype.Lookup(mimeHint)
+}
+
+// ImageToFile saves the image on the clipboard to a temp file and replaces the
+// clipboard contents with a reference to that file, for apps that accept files
+// but not pasted image data. TIFF is converted to PNG like pasty does. The file
+// is named clippy-* so CleanupTempFiles removes it once it leaves the clipboard.
+// Returns the path of the saved file.
+func ImageToFile(tempDir string) (string, error) {
+	content, err := clipboard.GetClipboardContent()
+	if err != nil || content.IsText || content.IsFile || len(content.Data) == 0 ||
+		!clipboard.UTIConformsTo(content.Type, "public.image") {
+		return "", fmt.Errorf("no image found on clipboard")
+	}
+
+	data := content.Data
+	ext := getFileExtensionFromUTI(content.Type)
+	if ext == "" {
+		ext = ".dat"
+	}
+	if ext == ".tiff" || ext == ".tif" {
+		if pngData, err := convertImageFormat(content.Data, ".png"); err == nil {
+			data = pngData
+			ext = ".png"
+		}
+	}
+
+	if skipForDryRun("save %d bytes of %s to a new temp file clippy-*%s and copy it as a file reference", len(data), content.Type, ext) {
+		return "", nil
+	}
+	tmpFile, err := createTempFile(tempDir, "", ext)
+	if err != nil {
+		return "", fmt.Errorf("could not create temporary file: %w", err)
+	}
+	defer func() {
+		if err := tmpFile.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to close temporary file: %v\n", err)
+		}
+	}()
+
+	if _, err := tmpFile.Write(data); err != nil {
+		return "", fmt.Errorf("could not write to temporary file: %w", err)
+	}
+
+	if err := writeClipboardFile(tmpFile.Name()); err != nil {
+		return "", fmt.Errorf("could not copy file to clipboard: %w", err)
+	}
+	return tmpFile.Name(), nil
+}
+
 // GetText returns text content from clipboard.
 // Uses hybrid detection for better reliability.
 func GetText() (string, bool) {
@@ -402,56 +710,80 @@ func isTextUTI(uti string) bool {
 
 // ClearClipboard clears the clipboard
 func ClearClipboard() error {
-	return clipboard.Clear()
+	return clearClipboard()
 }
 
 // CleanupTempFiles removes old temporary files that are no longer in clipboard
 func CleanupTempFiles(tempDir string, verbose bool) {
-	// Get current clipboard files
-	files := GetFiles()
+	// Hold the lock so no other process can copy a new temp file between the
+	// clipboard check and the removal; without it, cleanup is skipped
+	unlock, err := LockClipboard()
+	if err != nil {
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Warning: Skipping temp file cleanup: %v\n", err)
+		}
+		return
+	}
+	defer unlock()
 
-	// Build a map of clipboard files for quick lookup
+	for _, fullPath := range StaleTempFiles(tempDir) {
+		if skipForDryRun("remove old temp file %s", fullPath) {
+			continue
+		}
+		if verbose {
+			if info, err := os.Stat(fullPath); err == nil {
+				fmt.Fprintf(os.Stderr, "Cleaning up old temp file: %s (created %v ago)\n",
+					filepath.Base(fullPath), time.Since(info.ModTime()).Round(time.Minute))
+			}
+		}
+		if err := removeTempFile(fullPath); err != nil {
+			if verbose {
+				fmt.Fprintf(os.Stderr, "Warning: Failed to remove temp file %s: %v\n", filepath.Base(fullPath), err)
+			}
+		}
+	}
+}
+
+// staleTempFileAge is how old a clippy temp file must be before it counts as stale.
+// Younger files are kept to avoid racing parallel clippy/pasty operations.
+const staleTempFileAge = 5 * time.Minute
+
+// StaleTempFiles returns the clippy-* temp files in tempDir (os.TempDir() if empty)
+// that are no longer on the clipboard and older than five minutes. These are the
+// files CleanupTempFiles removes. A clippy-named-* directory holding a named file
+// (CopyOptions.Name) counts as on the clipboard while that file is; other
+// directories are skipped because clippy never creates them.
+func StaleTempFiles(tempDir string) []string {
+	// Build a map of clipboard files (and the directories of named ones) for quick lookup
 	clipboardMap := make(map[string]bool)
-	for _, file := range files {
+	for _, file := range GetFiles() {
 		clipboardMap[file] = true
+		clipboardMap[filepath.Dir(file)] = true
 	}
 
 	// Find only clippy temp files using glob
 	if tempDir == "" {
 		tempDir = os.TempDir()
 	}
-	pattern := filepath.Join(tempDir, "clippy-*")
-	matches, err := filepath.Glob(pattern)
+	matches, err := filepath.Glob(filepath.Join(tempDir, tempFilePattern))
 	if err != nil {
-		return
+		return nil
 	}
 
+	var stale []string
 	for _, fullPath := range matches {
+		if clipboardMap[fullPath] {
+			continue
+		}
 		info, err := os.Stat(fullPath)
-		if err != nil {
+		if err != nil || (info.IsDir() && !isNamedDir(fullPath)) {
 			continue
 		}
-
-		age := time.Since(info.ModTime())
-
-		// Check if this file is in the clipboard
-		if !clipboardMap[fullPath] {
-			// Only delete files older than 5 minutes to avoid race conditions
-			// with parallel clippy/pasty operations
-			if age >= 5*time.Minute {
-				if verbose {
-					name := filepath.Base(fullPath)
-					fmt.Fprintf(os.Stderr, "Cleaning up old temp file: %s (created %v ago)\n",
-						name, age.Round(time.Minute))
-				}
-				if err := os.Remove(fullPath); err != nil {
-					if verbose {
-						fmt.Fprintf(os.Stderr, "Warning: Failed to remove temp file %s: %v\n", filepath.Base(fullPath), err)
-					}
-				}
-			}
+		if time.Since(info.ModTime()) >= staleTempFileAge {
+			stale = append(stale, fullPath)
 		}
 	}
+	return stale
 }
 
 // PasteResult contains information about what was pasted
@@ -459,7 +791,10 @@ type PasteResult struct {
 	Type      string   // "text" or "files"
 	Content   string   // Text content if Type is "text"
 	Files     []string // File paths if Type is "files"
+	Copies    []string // Paths written at the destination when files were pasted into it
 	FilesRead int      // Number of files successfully read/copied
+	Moved     bool     // True if the source files were deleted after copying
+	Verified  bool     // True if every copy was checked against its source hash
 }
 
 // PasteToStdout pastes clipboard content to stdout
@@ -493,6 +828,35 @@ type PasteOptions struct {
 	PreserveFormat bool // If true, skip image format conversions (e.g., TIFF to PNG)
 	PlainTextOnly  bool // If true, force plain text extraction (strip all formatting)
 	Force          bool // If true, overwrite existing files instead of using Finder-style duplicate naming
+
+	// PreserveStructure recreates the files' directory layout under the destination,
+	// relative to their deepest common directory, instead of flattening them
+	PreserveStructure bool
+
+	// Move deletes pasted file references from their original location once every
+	// copy has been verified, like Finder's cut and paste. Sources in system
+	// locations (see IsProtectedPath) are refused unless AllowProtected is set.
+	Move           bool
+	AllowProtected bool
+
+	// Verify checks each pasted file is byte-identical to its source (SHA-256)
+	// and fails the paste if not. Off by default because it re-reads every copy.
+	Verify bool
+
+	// PreserveTimes gives pasted files their source's access and modification
+	// times, so downloads keep sorting by when they were fetched. PreserveXattrs
+	// also copies extended attributes (Finder tags, "Where from"); macOS only.
+	PreserveTimes  bool
+	PreserveXattrs bool
+
+	// MaxWidth downscales pasted image data wider than this many pixels to fit,
+	// keeping the aspect ratio (0 = no limit). Narrower images are untouched.
+	MaxWidth int
+
+	// StripMetadata removes EXIF, text and similar metadata (camera, location,
+	// timestamps) from pasted image data. PNG and JPEG are stripped without
+	// re-encoding; other formats (except GIF, which has none) fail the paste.
+	StripMetadata bool
 }
 
 // PasteToFile pastes clipboard content to a file or directory
@@ -524,14 +888,32 @@ func PasteToFileWithOptions(destination string, opts PasteOptions) (*PasteResult
 
 // pasteFileReferences copies file references from clipboard to destination
 func pasteFileReferences(files []string, destination string, opts PasteOptions) (*PasteResult, error) {
-	filesRead, err := copyFilesToDestination(files, destination, opts.Force)
+	if opts.Move && !opts.AllowProtected {
+		for _, file := range files {
+			if IsProtectedPath(file) {
+				return nil, fmt.Errorf("refusing to move %s out of a system location", file)
+			}
+		}
+	}
+
+	copies, err := copyFilesToDestination(files, destination, opts)
 	if err != nil {
 		return nil, err
 	}
+
+	if opts.Move {
+		if err := removeMovedFiles(files, copies); err != nil {
+			return nil, err
+		}
+	}
+
 	return &PasteResult{
 		Type:      "files",
 		Files:     files,
-		FilesRead: filesRead,
+		Copies:    copies,
+		FilesRead: len(copies),
+		Moved:     opts.Move,
+		Verified:  opts.Verify,
 	}, nil
 }
 
@@ -568,11 +950,27 @@ func pasteImageData(content *clipboard.ClipboardContent, destination string, opt
 		// If conversion fails, fall back to original TIFF data
 	}
 
+	if opts.MaxWidth > 0 {
+		resized, err := resizeImageData(data, ext, opts.MaxWidth)
+		if err != nil {
+			return nil, err
+		}
+		data = resized
+	}
+
+	if opts.StripMetadata {
+		stripped, err := stripImageMetadata(data, ext)
+		if err != nil {
+			return nil, err
+		}
+		data = stripped
+	}
+
 	defaultFilename := fmt.Sprintf("clipboard-%s%s", time.Now().Format("2006-01-02-150405"), ext)
 
 	destPath := resolveDestinationPath(destination, defaultFilename, true, opts.Force)
 
-	if err := os.WriteFile(destPath, data, 0644); err != nil {
+	if err := fsutil.WriteFileAtomic(destPath, data, 0644); err != nil {
 		return nil, fmt.Errorf("could not write to file %s: %w", destPath, err)
 	}
 
@@ -608,7 +1006,7 @@ func pasteTextContent(text string, destination string, opts PasteOptions) (*Past
 	defaultFilename := fmt.Sprintf("clipboard-%s.txt", time.Now().Format("2006-01-02-150405"))
 	destPath := resolveDestinationPath(destination, defaultFilename, false, opts.Force)
 
-	if err := os.WriteFile(destPath, []byte(text), 0644); err != nil {
+	if err := fsutil.WriteFileAtomic(destPath, []byte(text), 0644); err != nil {
 		return nil, fmt.Errorf("could not write to file %s: %w", destPath, err)
 	}
 
@@ -700,10 +1098,10 @@ func resolveDestinationPath(destination string, defaultFilename string, allowNoE
 	return findAvailableFilename(destination, force)
 }
 
-// copyFilesToDestination copies files from clipboard to destination
-func copyFilesToDestination(files []string, destination string, force bool) (int, error) {
+// copyFilesToDestination copies files from clipboard to destination and returns the paths written
+func copyFilesToDestination(files []string, destination string, opts PasteOptions) ([]string, error) {
 	if len(files) == 0 {
-		return 0, fmt.Errorf("no files to copy")
+		return nil, fmt.Errorf("no files to copy")
 	}
 
 	// Determine if destination should be a directory
@@ -719,32 +1117,94 @@ func copyFilesToDestination(files []string, destination string, force bool) (int
 	if destIsDir {
 		// Ensure destination directory exists
 		if err := os.MkdirAll(destination, 0755); err != nil {
-			return 0, fmt.Errorf("could not create directory %s: %w", destination, err)
+			return nil, fmt.Errorf("could not create directory %s: %w", destination, err)
 		}
 	}
 
-	// Copy each file
-	filesRead := 0
-	for _, srcFile := range files {
+	root := ""
+	if opts.PreserveStructure && destIsDir {
+		root = commonDir(files)
+	}
+
+	// Work out every target first, so a move onto the source itself is refused
+	// before anything is copied
+	targets := make([]string, len(files))
+	for i, srcFile := range files {
 		var destFile string
-		if destIsDir {
+		if root != "" {
+			rel, err := filepath.Rel(root, srcFile)
+			if err != nil {
+				return nil, fmt.Errorf("could not resolve %s relative to %s: %w", srcFile, root, err)
+			}
+			destFile = filepath.Join(destination, rel)
+		} else if destIsDir {
 			destFile = filepath.Join(destination, filepath.Base(srcFile))
 		} else {
 			destFile = destination
 		}
 
-		destFile = findAvailableFilename(destFile, force)
+		destFile = findAvailableFilename(destFile, opts.Force)
+		if opts.Move && sameFile(srcFile, destFile) {
+			return nil, fmt.Errorf("refusing to move %s onto itself", srcFile)
+		}
+		targets[i] = destFile
+	}
+
+	// Copy each file
+	var copies []string
+	for i, srcFile := range files {
+		destFile := targets[i]
+		if root != "" {
+			if err := os.MkdirAll(filepath.Dir(destFile), 0755); err != nil {
+				return copies, fmt.Errorf("could not create directory %s: %w", filepath.Dir(destFile), err)
+			}
+		}
 
 		// Clipboard file references can include directories; CopyFileToDestination
 		// handles both files and folders (recursive copy).
-		if err := recent.CopyFileToDestination(srcFile, destFile); err != nil {
-			return filesRead, fmt.Errorf("could not copy %s to %s: %w", srcFile, destFile, err)
+		if opts.Verify {
+			if err := copyAndVerify(srcFile, destFile); err != nil {
+				return copies, fmt.Errorf("could not copy %s to %s: %w", srcFile, destFile, err)
+			}
+		} else if err := recent.CopyFileToDestination(srcFile, destFile); err != nil {
+			return copies, fmt.Errorf("could not copy %s to %s: %w", srcFile, destFile, err)
+		}
+		if opts.PreserveTimes || opts.PreserveXattrs {
+			if err := preserveMetadata(srcFile, destFile, opts); err != nil {
+				return copies, err
+			}
 		}
 
-		filesRead++
+		copies = append(copies, destFile)
 	}
 
-	return filesRead, nil
+	return copies, nil
+}
+
+// commonDir returns the deepest directory containing all of the given paths.
+// For a single path it is the path's parent, so relative names are at least the base name.
+func commonDir(paths []string) string {
+	if len(paths) == 0 {
+		return ""
+	}
+	common := filepath.Dir(filepath.Clean(paths[0]))
+	for _, path := range paths[1:] {
+		dir := filepath.Dir(filepath.Clean(path))
+		for !isWithinDir(dir, common) {
+			parent := filepath.Dir(common)
+			if parent == common {
+				break
+			}
+			common = parent
+		}
+	}
+	return common
+}
+
+// isWithinDir reports whether path is dir or inside it
+func isWithinDir(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
 }
 
 // getFileExtensionFromUTI returns the file extension for a UTI
@@ -771,9 +1231,13 @@ func convertImageFormat(imageData []byte, targetExt string) ([]byte, error) {
 		return nil, fmt.Errorf("failed to decode image: %w", err)
 	}
 
-	// Encode to target format
+	return encodeImage(img, targetExt)
+}
+
+// encodeImage encodes img in the format named by ext (.png, .jpg/.jpeg or .gif)
+func encodeImage(img image.Image, ext string) ([]byte, error) {
 	var buf bytes.Buffer
-	targetExt = strings.ToLower(targetExt)
+	targetExt := strings.ToLower(ext)
 
 	switch targetExt {
 	case ".png":
@@ -1,11 +1,16 @@
 package clippy
 
 import (
+	"errors"
 	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gabriel-vasile/mimetype"
+	"github.com/neilberkman/clippy/pkg/clipboard"
 )
 
 func TestIsTextualMimeType(t *testing.T) {
@@ -299,7 +304,7 @@ func TestCopyFilesToDestination_Directory(t *testing.T) {
 	destRoot := t.TempDir()
 
 	// Destination is an existing directory: should copy folder into it.
-	if _, err := copyFilesToDestination([]string{srcDir}, destRoot, false); err != nil {
+	if _, err := copyFilesToDestination([]string{srcDir}, destRoot, PasteOptions{}); err != nil {
 		t.Fatalf("copyFilesToDestination returned error: %v", err)
 	}
 
@@ -311,3 +316,479 @@ func TestCopyFilesToDestination_Directory(t *testing.T) {
 		t.Fatalf("Copied file content mismatch: got %q want %q", string(got), "hello")
 	}
 }
+
+// useMemoryClipboard swaps in an empty in-memory clipboard, and a private change
+// record, clipboard stack and slots, for the duration of the test
+func useMemoryClipboard(t *testing.T) *clipboard.MemoryManager {
+	t.Helper()
+	mem := clipboard.NewMemoryManager()
+	previous := clipboard.SetManager(mem)
+	state := t.TempDir()
+	previousPath, previousStack, previousSlots := changeRecordPath, stackDir, slotsDir
+	changeRecordPath = func() string { return filepath.Join(state, "last-change.json") }
+	stackDir = func() string { return filepath.Join(state, "stack") }
+	slotsDir = func() string { return filepath.Join(state, "slots") }
+	t.Cleanup(func() {
+		clipboard.SetManager(previous)
+		changeRecordPath, stackDir, slotsDir = previousPath, previousStack, previousSlots
+	})
+	return mem
+}
+
+func TestCopyDataWithMemoryClipboard(t *testing.T) {
+	png, err := os.ReadFile("test-files/minimal.png")
+	if err != nil {
+		t.Fatalf("Failed to read test image: %v", err)
+	}
+
+	t.Run("text", func(t *testing.T) {
+		mem := useMemoryClipboard(t)
+		if err := CopyData(strings.NewReader("hello world")); err != nil {
+			t.Fatalf("CopyData returned error: %v", err)
+		}
+		if got, ok := mem.GetText(); !ok || got != "hello world" {
+			t.Errorf("clipboard text = %q, %v, want %q", got, ok, "hello world")
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		mem := useMemoryClipboard(t)
+		if err := CopyData(strings.NewReader(`{"key": "value"}`)); err != nil {
+			t.Fatalf("CopyData returned error: %v", err)
+		}
+		if !mem.ContainsType("public.json") {
+			t.Errorf("clipboard types = %v, want public.json", mem.GetClipboardTypes())
+		}
+	})
+
+	t.Run("binary", func(t *testing.T) {
+		mem := useMemoryClipboard(t)
+		tmpDir := t.TempDir()
+		if err := CopyDataWithTempDir(strings.NewReader(string(png)), tmpDir); err != nil {
+			t.Fatalf("CopyDataWithTempDir returned error: %v", err)
+		}
+		files := mem.GetFiles()
+		if len(files) != 1 || filepath.Dir(files[0]) != tmpDir || filepath.Ext(files[0]) != ".png" {
+			t.Errorf("clipboard files = %v, want one .png in %s", files, tmpDir)
+		}
+	})
+}
+
+func TestImageToFileWithMemoryClipboard(t *testing.T) {
+	png, err := os.ReadFile("test-files/minimal.png")
+	if err != nil {
+		t.Fatalf("Failed to read test image: %v", err)
+	}
+
+	mem := useMemoryClipboard(t)
+	mem.SetData("public.png", png)
+
+	tmpDir := t.TempDir()
+	path, err := ImageToFile(tmpDir)
+	if err != nil {
+		t.Fatalf("ImageToFile returned error: %v", err)
+	}
+	if filepath.Dir(path) != tmpDir || filepath.Ext(path) != ".png" {
+		t.Errorf("ImageToFile path = %q, want a .png in %s", path, tmpDir)
+	}
+	if files := mem.GetFiles(); len(files) != 1 || files[0] != path {
+		t.Errorf("clipboard files = %v, want [%s]", files, path)
+	}
+
+	mem = useMemoryClipboard(t)
+	_ = mem.CopyText("not an image")
+	if _, err := ImageToFile(tmpDir); err == nil {
+		t.Error("ImageToFile succeeded with text on the clipboard, want error")
+	}
+}
+
+func TestPasteToFileWithMemoryClipboard(t *testing.T) {
+	png, err := os.ReadFile("test-files/minimal.png")
+	if err != nil {
+		t.Fatalf("Failed to read test image: %v", err)
+	}
+
+	t.Run("text", func(t *testing.T) {
+		mem := useMemoryClipboard(t)
+		_ = mem.CopyText("pasted text")
+
+		dest := filepath.Join(t.TempDir(), "out.txt")
+		result, err := PasteToFile(dest)
+		if err != nil {
+			t.Fatalf("PasteToFile returned error: %v", err)
+		}
+		if result.Type != "text" {
+			t.Errorf("PasteToFile type = %q, want %q", result.Type, "text")
+		}
+		if got, _ := os.ReadFile(dest); string(got) != "pasted text" {
+			t.Errorf("pasted content = %q, want %q", got, "pasted text")
+		}
+	})
+
+	t.Run("image", func(t *testing.T) {
+		mem := useMemoryClipboard(t)
+		mem.SetData("public.png", png)
+
+		dest := filepath.Join(t.TempDir(), "shot.png")
+		result, err := PasteToFile(dest)
+		if err != nil {
+			t.Fatalf("PasteToFile returned error: %v", err)
+		}
+		if result.Type != "image" || len(result.Files) != 1 || result.Files[0] != dest {
+			t.Errorf("PasteToFile result = %+v, want image at %s", result, dest)
+		}
+	})
+
+	t.Run("file references", func(t *testing.T) {
+		mem := useMemoryClipboard(t)
+		src := filepath.Join(t.TempDir(), "report.txt")
+		if err := os.WriteFile(src, []byte("report"), 0644); err != nil {
+			t.Fatalf("Failed to create source file: %v", err)
+		}
+		_ = mem.CopyFile(src)
+
+		destDir := t.TempDir()
+		result, err := PasteToFile(destDir)
+		if err != nil {
+			t.Fatalf("PasteToFile returned error: %v", err)
+		}
+		if result.FilesRead != 1 {
+			t.Errorf("PasteToFile FilesRead = %d, want 1", result.FilesRead)
+		}
+		if got, _ := os.ReadFile(filepath.Join(destDir, "report.txt")); string(got) != "report" {
+			t.Errorf("pasted file content = %q, want %q", got, "report")
+		}
+	})
+}
+
+func TestUniquePaths(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.jpg")
+	b := filepath.Join(dir, "b.jpg")
+
+	got, err := UniquePaths([]string{a, b, filepath.Join(dir, ".", "a.jpg"), b})
+	if err != nil {
+		t.Fatalf("UniquePaths returned error: %v", err)
+	}
+	want := []string{a, b}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("UniquePaths = %v, want %v", got, want)
+	}
+}
+
+func TestUniquePathsResolvesSymlinks(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "a.jpg")
+	if err := os.WriteFile(target, []byte("x"), 0644); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+	link := filepath.Join(dir, "link.jpg")
+	if err := os.Symlink(target, link); err != nil {
+		t.Skipf("symlinks unavailable: %v", err)
+	}
+
+	got, err := UniquePaths([]string{link, target})
+	if err != nil {
+		t.Fatalf("UniquePaths returned error: %v", err)
+	}
+	if want := []string{link}; !reflect.DeepEqual(got, want) {
+		t.Errorf("UniquePaths = %v, want %v", got, want)
+	}
+}
+
+func TestCopyMultipleDeduplicates(t *testing.T) {
+	mem := useMemoryClipboard(t)
+	dir := t.TempDir()
+	var paths []string
+	for _, name := range []string{"a.jpg", "b.jpg"} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+			t.Fatalf("Failed to create file: %v", err)
+		}
+		paths = append(paths, path)
+	}
+
+	if err := CopyMultiple(append(paths, paths[0])); err != nil {
+		t.Fatalf("CopyMultiple returned error: %v", err)
+	}
+	if got := mem.GetFiles(); !reflect.DeepEqual(got, paths) {
+		t.Errorf("clipboard files = %v, want %v", got, paths)
+	}
+}
+
+func TestCommonDir(t *testing.T) {
+	tests := []struct {
+		paths []string
+		want  string
+	}{
+		{[]string{"/a/b/c.txt"}, "/a/b"},
+		{[]string{"/a/b/c.txt", "/a/b/d/e.txt"}, "/a/b"},
+		{[]string{"/a/b/c.txt", "/a/bc/d.txt"}, "/a"},
+		{[]string{"/a/c.txt", "/x/y.txt"}, "/"},
+	}
+
+	for _, tt := range tests {
+		if got := commonDir(tt.paths); got != tt.want {
+			t.Errorf("commonDir(%v) = %q, want %q", tt.paths, got, tt.want)
+		}
+	}
+}
+
+func TestCopyFilesToDestinationPreserveStructure(t *testing.T) {
+	srcRoot := t.TempDir()
+	var files []string
+	for _, rel := range []string{"readme.txt", "docs/guide.txt", "docs/img/logo.txt"} {
+		path := filepath.Join(srcRoot, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(rel), 0644); err != nil {
+			t.Fatalf("Failed to create file: %v", err)
+		}
+		files = append(files, path)
+	}
+
+	destRoot := t.TempDir()
+	if _, err := copyFilesToDestination(files, destRoot, PasteOptions{PreserveStructure: true}); err != nil {
+		t.Fatalf("copyFilesToDestination returned error: %v", err)
+	}
+	for _, rel := range []string{"readme.txt", "docs/guide.txt", "docs/img/logo.txt"} {
+		if got, err := os.ReadFile(filepath.Join(destRoot, rel)); err != nil || string(got) != rel {
+			t.Errorf("%s = %q, %v, want %q", rel, got, err, rel)
+		}
+	}
+}
+
+func TestCopyFilesToDestinationPreserveTimes(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "download.txt")
+	if err := os.WriteFile(src, []byte("data"), 0644); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+	old := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := os.Chtimes(src, old, old); err != nil {
+		t.Fatalf("Failed to set file time: %v", err)
+	}
+
+	for _, preserve := range []bool{false, true} {
+		copies, err := copyFilesToDestination([]string{src}, t.TempDir(), PasteOptions{PreserveTimes: preserve})
+		if err != nil {
+			t.Fatalf("copyFilesToDestination returned error: %v", err)
+		}
+		info, err := os.Stat(copies[0])
+		if err != nil {
+			t.Fatalf("Failed to stat copy: %v", err)
+		}
+		if got := info.ModTime().Equal(old); got != preserve {
+			t.Errorf("PreserveTimes=%v: copy mtime = %v, source mtime %v", preserve, info.ModTime(), old)
+		}
+	}
+}
+
+func TestCopyDataWithName(t *testing.T) {
+	png, err := os.ReadFile("test-files/minimal.png")
+	if err != nil {
+		t.Fatalf("Failed to read test image: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"report.png", "report.png"},
+		{"screenshot", "screenshot.png"},
+		{"../../etc/passwd.png", "passwd.png"},
+		{".hidden.png", "hidden.png"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mem := useMemoryClipboard(t)
+			tmpDir := t.TempDir()
+			if err := CopyDataWithOptions(strings.NewReader(string(png)), tmpDir, CopyOptions{Name: tt.name}); err != nil {
+				t.Fatalf("CopyDataWithOptions returned error: %v", err)
+			}
+			files := mem.GetFiles()
+			if len(files) != 1 || filepath.Base(files[0]) != tt.want {
+				t.Fatalf("clipboard files = %v, want one named %s", files, tt.want)
+			}
+			dir := filepath.Dir(files[0])
+			if filepath.Dir(dir) != tmpDir || !strings.HasPrefix(filepath.Base(dir), "clippy-named-") {
+				t.Errorf("named file is in %s, want a clippy-named-* directory in %s", dir, tmpDir)
+			}
+		})
+	}
+}
+
+func TestCopyDataWithMimeType(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		mimeType string
+		wantType string // clipboard text type, or "" for a file reference
+		wantExt  string
+	}{
+		{"binary type names the file", "not really a webp", "image/webp", "", ".webp"},
+		{"text type is copied as text", "<b>hi</b>", "text/html", "public.html", ""},
+		{"parameters are ignored", "plain", "text/plain; charset=utf-8", "public.plain-text", ""},
+		{"UTI is copied as text", `{"a": 1}`, "public.json", "public.json", ""},
+		{"textual content as binary type", "id,name\n1,a\n", "application/octet-stream", "", ".bin"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mem := useMemoryClipboard(t)
+			tmpDir := t.TempDir()
+			if err := CopyDataWithOptions(strings.NewReader(tt.data), tmpDir, CopyOptions{MimeType: tt.mimeType}); err != nil {
+				t.Fatalf("CopyDataWithOptions returned error: %v", err)
+			}
+			if tt.wantType != "" {
+				if got, ok := mem.GetClipboardDataForType(tt.wantType); !ok || string(got) != tt.data {
+					t.Errorf("clipboard %s = %q, %v, want %q (types %v)", tt.wantType, got, ok, tt.data, mem.GetClipboardTypes())
+				}
+				return
+			}
+			files := mem.GetFiles()
+			if len(files) != 1 || filepath.Ext(files[0]) != tt.wantExt {
+				t.Errorf("clipboard files = %v, want one %s file", files, tt.wantExt)
+			}
+		})
+	}
+}
+
+func TestCopyDataAsFile(t *testing.T) {
+	csv := "id,name\n1,alice\n2,bob\n"
+
+	t.Run("without AsFile", func(t *testing.T) {
+		mem := useMemoryClipboard(t)
+		if err := CopyDataWithOptions(strings.NewReader(csv), t.TempDir(), CopyOptions{}); err != nil {
+			t.Fatalf("CopyDataWithOptions returned error: %v", err)
+		}
+		if got, ok := mem.GetText(); !ok || got != csv {
+			t.Errorf("clipboard text = %q, %v, want the CSV", got, ok)
+		}
+		if files := mem.GetFiles(); len(files) != 0 {
+			t.Errorf("clipboard files = %v, want none", files)
+		}
+	})
+
+	t.Run("with AsFile", func(t *testing.T) {
+		mem := useMemoryClipboard(t)
+		tmpDir := t.TempDir()
+		if err := CopyDataWithOptions(strings.NewReader(csv), tmpDir, CopyOptions{AsFile: true, Name: "people"}); err != nil {
+			t.Fatalf("CopyDataWithOptions returned error: %v", err)
+		}
+		files := mem.GetFiles()
+		if len(files) != 1 || filepath.Base(files[0]) != "people.csv" {
+			t.Fatalf("clipboard files = %v, want people.csv", files)
+		}
+		if data, err := os.ReadFile(files[0]); err != nil || string(data) != csv {
+			t.Errorf("saved file = %q, %v, want the CSV", data, err)
+		}
+	})
+}
+
+func TestStaleTempFiles(t *testing.T) {
+	mem := useMemoryClipboard(t)
+	dir := t.TempDir()
+	old := time.Now().Add(-time.Hour)
+	for _, name := range []string{"clippy-old.png", "clippy-onclipboard.png", "clippy-new.png", "other.png"} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+			t.Fatalf("Failed to create file: %v", err)
+		}
+		if name != "clippy-new.png" {
+			if err := os.Chtimes(path, old, old); err != nil {
+				t.Fatalf("Failed to set file time: %v", err)
+			}
+		}
+	}
+	for _, name := range []string{"clippy-named-old", "clippy-named-onclipboard", "clippy-checkout"} {
+		path := filepath.Join(dir, name)
+		if err := os.Mkdir(path, 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(path, "report.pdf"), []byte("x"), 0644); err != nil {
+			t.Fatalf("Failed to create file: %v", err)
+		}
+		if err := os.Chtimes(path, old, old); err != nil {
+			t.Fatalf("Failed to set directory time: %v", err)
+		}
+	}
+	_ = mem.CopyFiles([]string{
+		filepath.Join(dir, "clippy-onclipboard.png"),
+		filepath.Join(dir, "clippy-named-onclipboard", "report.pdf"),
+	})
+
+	got := StaleTempFiles(dir)
+	want := []string{filepath.Join(dir, "clippy-named-old"), filepath.Join(dir, "clippy-old.png")}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("StaleTempFiles = %v, want %v", got, want)
+	}
+}
+
+func TestCleanupTempFilesKeepsOtherDirectories(t *testing.T) {
+	useMemoryClipboard(t)
+	dir := t.TempDir()
+	old := time.Now().Add(-time.Hour)
+	for _, name := range []string{"clippy-named-old", "clippy-checkout"} {
+		path := filepath.Join(dir, name)
+		if err := os.Mkdir(path, 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(path, "notes.txt"), []byte("x"), 0644); err != nil {
+			t.Fatalf("Failed to create file: %v", err)
+		}
+		if err := os.Chtimes(path, old, old); err != nil {
+			t.Fatalf("Failed to set directory time: %v", err)
+		}
+	}
+
+	CleanupTempFiles(dir, false)
+
+	if _, err := os.Stat(filepath.Join(dir, "clippy-named-old")); !os.IsNotExist(err) {
+		t.Errorf("stale named directory was not removed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "clippy-checkout", "notes.txt")); err != nil {
+		t.Errorf("unrelated clippy-* directory was touched: %v", err)
+	}
+}
+
+func TestAppendText(t *testing.T) {
+	mem := useMemoryClipboard(t)
+
+	if err := AppendText("first", "\n"); err != nil {
+		t.Fatalf("AppendText on empty clipboard returned error: %v", err)
+	}
+	if err := AppendText("second", "\n"); err != nil {
+		t.Fatalf("AppendText returned error: %v", err)
+	}
+	if got, _ := mem.GetText(); got != "first\nsecond" {
+		t.Errorf("clipboard text = %q, want %q", got, "first\nsecond")
+	}
+
+	if err := mem.CopyFiles([]string{"/tmp/a.pdf"}); err != nil {
+		t.Fatalf("CopyFiles returned error: %v", err)
+	}
+	if err := AppendText("third", "\n"); !errors.Is(err, ErrNotText) {
+		t.Errorf("AppendText onto files = %v, want ErrNotText", err)
+	}
+}
+
+func TestHasExtension(t *testing.T) {
+	exts := []string{"md", ".TXT", " json"}
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/tmp/notes.md", true},
+		{"/tmp/README.MD", true},
+		{"/tmp/a.txt", true},
+		{"/tmp/data.json", true},
+		{"/tmp/report.pdf", false},
+		{"/tmp/md", false},
+		{"/tmp/archive.md.gz", false},
+	}
+	for _, tt := range tests {
+		if got := hasExtension(tt.path, exts); got != tt.want {
+			t.Errorf("hasExtension(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
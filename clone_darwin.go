@@ -0,0 +1,13 @@
+//go:build darwin
+
+package clippy
+
+import "golang.org/x/sys/unix"
+
+// cloneFile makes dst a copy-on-write clone of the file or folder at src. The
+// clone shares storage with src until either is changed, so it is instant and
+// takes no extra space, but editing it never touches src. Fails on volumes
+// without clone support (anything but APFS) and across volumes.
+func cloneFile(src, dst string) error {
+	return unix.Clonefile(src, dst, unix.CLONE_NOFOLLOW)
+}
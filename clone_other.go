@@ -0,0 +1,10 @@
+//go:build !darwin
+
+package clippy
+
+import "errors"
+
+// cloneFile is unsupported outside macOS, so callers fall back to copying
+func cloneFile(src, dst string) error {
+	return errors.ErrUnsupported
+}
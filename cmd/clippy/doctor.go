@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/neilberkman/clippy"
+	"github.com/neilberkman/clippy/cmd/internal/common"
+	"github.com/neilberkman/clippy/pkg/clipboard"
+	"github.com/neilberkman/clippy/pkg/recent"
+	"github.com/neilberkman/clippy/pkg/spotlight"
+)
+
+// checkStatus is the outcome of a single doctor check
+type checkStatus int
+
+const (
+	statusPass checkStatus = iota
+	statusWarn
+	statusFail
+)
+
+func (s checkStatus) String() string {
+	if !common.StyleEnabled(os.Stdout) {
+		return s.label()
+	}
+	switch s {
+	case statusPass:
+		return "✅ PASS"
+	case statusWarn:
+		return "⚠️  WARN"
+	default:
+		return "❌ FAIL"
+	}
+}
+
+// label is the status without its emoji, for output that isn't a styled terminal
+func (s checkStatus) label() string {
+	switch s {
+	case statusPass:
+		return "PASS"
+	case statusWarn:
+		return "WARN"
+	default:
+		return "FAIL"
+	}
+}
+
+// checkResult is one line of the doctor report
+type checkResult struct {
+	Name    string
+	Status  checkStatus
+	Message string
+}
+
+// runDoctor runs every check, prints the report and returns false if any check failed
+func runDoctor() bool {
+	results := []checkResult{checkHomeDir(), checkClipboardBackend()}
+	for _, dir := range recent.GetDefaultDownloadDirs() {
+		results = append(results, checkSearchDir(dir))
+	}
+	results = append(results,
+		checkSpotlight(),
+		checkTempDirWritable(tempDir),
+		checkStaleTempFiles(tempDir),
+	)
+
+	ok := true
+	for _, r := range results {
+		fmt.Printf("%s  %s: %s\n", r.Status, r.Name, r.Message)
+		if r.Status == statusFail {
+			ok = false
+		}
+	}
+	return ok
+}
+
+func checkHomeDir() checkResult {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return checkResult{"Home directory", statusFail, fmt.Sprintf("could not determine home directory: %v", err)}
+	}
+	return checkResult{"Home directory", statusPass, home}
+}
+
+func checkClipboardBackend() checkResult {
+	name := clipboard.BackendName()
+	if name == "memory" {
+		return checkResult{"Clipboard backend", statusWarn, fmt.Sprintf("memory (%s=memory); copies are not shared with the system clipboard", clipboard.BackendEnvVar)}
+	}
+	types := clipboard.GetClipboardTypes()
+	return checkResult{"Clipboard backend", statusPass, fmt.Sprintf("%s (%d types on clipboard)", name, len(types))}
+}
+
+// checkSearchDir checks a folder used by -r/-i exists and can be listed.
+// macOS privacy settings can block terminals from reading Desktop and Documents.
+func checkSearchDir(dir string) checkResult {
+	name := "Folder " + filepath.Base(dir)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return checkResult{name, statusWarn, dir + " does not exist"}
+	}
+	if _, err := os.ReadDir(dir); err != nil {
+		return checkResult{name, statusFail, fmt.Sprintf("%s is not readable (%v); allow your terminal access in System Settings > Privacy & Security > Files and Folders", dir, err)}
+	}
+	return checkResult{name, statusPass, dir}
+}
+
+func checkSpotlight() checkResult {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = "/"
+	}
+	enabled, err := spotlight.IndexingEnabled(home)
+	if err != nil {
+		return checkResult{"Spotlight", statusWarn, fmt.Sprintf("could not check indexing status: %v", err)}
+	}
+	if !enabled {
+		return checkResult{"Spotlight", statusWarn, "indexing is disabled; -f falls back to walking folders. Enable it with 'sudo mdutil -i on /'"}
+	}
+	return checkResult{"Spotlight", statusPass, "indexing enabled"}
+}
+
+func checkTempDirWritable(dir string) checkResult {
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	f, err := os.CreateTemp(dir, "clippy-doctor-*")
+	if err != nil {
+		return checkResult{"Temp directory", statusFail, fmt.Sprintf("%s is not writable: %v", dir, err)}
+	}
+	_ = f.Close()
+	_ = os.Remove(f.Name())
+	return checkResult{"Temp directory", statusPass, dir + " is writable"}
+}
+
+func checkStaleTempFiles(dir string) checkResult {
+	stale := clippy.StaleTempFiles(dir)
+	if len(stale) == 0 {
+		return checkResult{"Stale temp files", statusPass, "none"}
+	}
+	return checkResult{"Stale temp files", statusWarn, fmt.Sprintf("%d old clippy-* files (%s); they are removed on the next copy unless --cleanup=false", len(stale), formatSize(common.TotalSize(stale)))}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckSearchDir(t *testing.T) {
+	dir := t.TempDir()
+	if got := checkSearchDir(dir); got.Status != statusPass {
+		t.Errorf("checkSearchDir(existing) = %v, want %v", got.Status, statusPass)
+	}
+	if got := checkSearchDir(filepath.Join(dir, "missing")); got.Status != statusWarn {
+		t.Errorf("checkSearchDir(missing) = %v, want %v", got.Status, statusWarn)
+	}
+}
+
+func TestCheckTempDirWritable(t *testing.T) {
+	dir := t.TempDir()
+	if got := checkTempDirWritable(dir); got.Status != statusPass {
+		t.Errorf("checkTempDirWritable(writable) = %v: %s", got.Status, got.Message)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("checkTempDirWritable left %d files behind", len(entries))
+	}
+	if got := checkTempDirWritable(filepath.Join(dir, "missing")); got.Status != statusFail {
+		t.Errorf("checkTempDirWritable(missing) = %v, want %v", got.Status, statusFail)
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/neilberkman/clippy"
+)
+
+// explainFiles prints how each file would be copied and why, without copying.
+// Files that can't be explained are reported and the first such error returned.
+func explainFiles(paths []string) error {
+	var firstErr error
+	for i, path := range paths {
+		if i > 0 {
+			fmt.Println()
+		}
+		if err := explainFile(path); err != nil {
+			logger.Error("Could not explain %s: %v", path, err)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
+
+// explainFile prints the detection steps and decision for one file
+func explainFile(path string) error {
+	d, err := clippy.ExplainCopy(path, textMode, clippy.CopyOptions{TextExtensions: textExtensions, ReferenceExtensions: refExtensions})
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(d.FilePath)
+	fmt.Printf("  UTI:       %s\n", describeDetected(d.UTI, d.UTIIsText))
+	fmt.Printf("  MIME:      %s\n", describeDetected(d.MIME, d.MIMEIsText))
+	switch {
+	case textMode:
+		fmt.Println("  Text mode: requested with -t")
+	case d.TextRequested:
+		fmt.Println("  Text mode: requested by text_extensions")
+	default:
+		fmt.Println("  Text mode: not requested")
+	}
+
+	// -t with --mime skips detection entirely (see handleFileMode)
+	if textMode && mimeType != "" {
+		fmt.Printf("  Decision:  text content as %s\n", mimeType)
+		fmt.Println("  Rule:      --mime with -t copies the content as that type without detection")
+		return nil
+	}
+
+	decision := "file reference"
+	if d.AsText {
+		decision = "text content"
+	}
+	fmt.Printf("  Decision:  %s (via %s %s)\n", decision, d.Method, d.Type)
+	fmt.Printf("  Rule:      %s\n", d.Rule)
+	return nil
+}
+
+// describeDetected formats a detected UTI or MIME type and whether it's text
+func describeDetected(typ string, isText bool) string {
+	switch {
+	case typ == "":
+		return "(none)"
+	case isText:
+		return typ + " (text)"
+	default:
+		return typ + " (not text)"
+	}
+}
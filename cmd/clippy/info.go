@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+
+	"github.com/neilberkman/clippy/cmd/internal/common"
+	"github.com/neilberkman/clippy/pkg/clipboard"
+	"github.com/neilberkman/clippy/pkg/recent"
+)
+
+// infoReport is the environment summary printed by `clippy info`
+type infoReport struct {
+	Version     string            `json:"version"`
+	Commit      string            `json:"commit"`
+	Date        string            `json:"date"`
+	GoVersion   string            `json:"go_version"`
+	Platform    string            `json:"platform"`
+	ConfigPath  string            `json:"config_path"`
+	ConfigFound bool              `json:"config_found"`
+	Config      map[string]string `json:"config"`
+	Backend     string            `json:"clipboard_backend"`
+	SearchDirs  []string          `json:"search_dirs"`
+	TempDir     string            `json:"temp_dir"`
+}
+
+// buildInfoReport collects build details and the resolved configuration.
+// loadConfig must have run first.
+func buildInfoReport() infoReport {
+	configPath := common.ConfigFilePath()
+	_, err := os.Stat(configPath)
+
+	searchDirs := selectedSearchDirs()
+	if len(searchDirs) == 0 {
+		searchDirs = recent.GetDefaultDownloadDirs()
+	}
+
+	dir := tempDir
+	if dir == "" {
+		dir = os.TempDir()
+	}
+
+	return infoReport{
+		Version:     common.Version,
+		Commit:      common.Commit,
+		Date:        common.Date,
+		GoVersion:   runtime.Version(),
+		Platform:    runtime.GOOS + "/" + runtime.GOARCH,
+		ConfigPath:  configPath,
+		ConfigFound: configPath != "" && err == nil,
+		Config:      configSettings,
+		Backend:     clipboard.BackendName(),
+		SearchDirs:  searchDirs,
+		TempDir:     dir,
+	}
+}
+
+// printInfo writes the report as text, or as indented JSON if asJSON is set
+func printInfo(report infoReport, asJSON bool) error {
+	if asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+
+	fmt.Printf("clippy %s (%s) built on %s\n", report.Version, report.Commit, report.Date)
+	fmt.Printf("Go:                %s %s\n", report.GoVersion, report.Platform)
+	if report.ConfigFound {
+		fmt.Printf("Config:            %s\n", report.ConfigPath)
+	} else {
+		fmt.Printf("Config:            %s (not found, using defaults)\n", report.ConfigPath)
+	}
+	keys := make([]string, 0, len(report.Config))
+	for key := range report.Config {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Printf("  %s = %s\n", key, report.Config[key])
+	}
+	fmt.Printf("Clipboard backend: %s\n", report.Backend)
+	fmt.Printf("Temp directory:    %s\n", report.TempDir)
+	fmt.Println("Search folders:")
+	for _, dir := range report.SearchDirs {
+		fmt.Printf("  %s\n", dir)
+	}
+	return nil
+}
@@ -1,50 +1,111 @@
 package main
 
 import (
-	"bufio"
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
-	"runtime"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/neilberkman/clippy"
 	"github.com/neilberkman/clippy/cmd/clippy/mcp"
 	"github.com/neilberkman/clippy/cmd/internal/common"
 	"github.com/neilberkman/clippy/internal/log"
+	"github.com/neilberkman/clippy/pkg/notify"
 	"github.com/neilberkman/clippy/pkg/recent"
 	"github.com/neilberkman/clippy/pkg/spotlight"
+	"github.com/neilberkman/clippy/pkg/workspace"
 	"github.com/spf13/cobra"
 )
 
 var (
 	verbose         bool
 	debug           bool
+	quiet           bool
 	cleanup         = true
 	tempDir         = ""
 	recentFlag      string
 	interactiveFlag string
 	findFlag        string
 	paste           bool
+	reveal          bool
+	openFlag        bool
 	absoluteTime    bool
 	textMode        bool
+	textExtensions  []string
+	refExtensions   []string
 	clearFlag       bool
+	imageToFile     bool
+	saveLink        string
+	withThumbnail   bool
+	quoteFlag       bool
+	quoteSource     string
 	foldersFlag     []string
 	defaultFolders  []string
 	mimeType        string
+	urlFlag         string
+	urlTimeout      time.Duration
+	dryRun          bool
+	explain         bool
+	includeHidden   bool
+	includeTemp     bool
+	screenshotFlag  string
+	attrFlags       []string
+	sinceFlag       string
+	noSpotlight     bool
+	caseSensitive   bool
+	assumeYes       bool
+	waitStable      time.Duration
+	pasteboardName  string
+	noClear         bool
+	skipIfSame      bool
+	postCopyHook    string
+	postPasteHook   string
+	notifyFlag      bool
+	bellFlag        bool
+	nameFlag        string
+	binaryFlag      bool
+	asName          string
+	wrapTemplate    string
+	stdinImage      string
+	htmlFile        string
+	rtfFile         string
+	plainFile       string
+	flavorFiles     []string
+	confirmLimit    = defaultConfirmThreshold
+	maxFilesLimit   = defaultMaxFiles
+	maxFilesFlag    int
+	pasteSizeLimit  = defaultPasteConfirmSize
+	pickerKeys      pickerKeyMap
+	pickerKeysErr   error
+	pickerPathMode  string
+	sortFlag        string
+	sortOrder       = recent.DefaultSortOrder
+	pickFlag        string
+	pickPositions   []int
+	manifestPath    string
+	smartFlavor     bool
+	gitPath         bool
+	refFlag         string
+	refStart        int
+	refEnd          int
+	dirFlag         string
+	typeFlags       []string
+	recursive       bool
 	logger          *log.Logger
 )
 
 func main() {
-	// Clippy only works on macOS
-	if runtime.GOOS != "darwin" {
-		fmt.Fprintf(os.Stderr, "Error: Clippy only works on macOS (detected: %s)\n", runtime.GOOS)
-		fmt.Fprintln(os.Stderr, "Clippy uses macOS-specific clipboard APIs and frameworks.")
-		os.Exit(1)
-	}
+	// Clippy only works on macOS, or anywhere with the memory backend
+	common.RequireMacOS("Clippy")
 
 	// Preprocess args to convert "-r 3" to "-r=3" for Cobra compatibility
 	os.Args = preprocessArgs(os.Args)
@@ -76,9 +137,19 @@ Examples:
   clippy *.jpg
   clippy file1.pdf file2.doc file3.png
 
+  # Recursive glob (quote it so clippy expands it, not the shell)
+  clippy '**/*.png'
+
   # Copy from curl
   curl -s https://example.com/image.jpg | clippy
 
+  # Use a private named pasteboard so automation doesn't clobber your clipboard
+  clippy --pasteboard com.example.build report.pdf
+
+  # Fetch a URL directly (binary becomes a file, text becomes text)
+  clippy --url https://example.com/image.jpg
+  clippy --url https://example.com/data.json --url-timeout 10s
+
   # Copy most recent file(s) from Downloads/Desktop/Documents
   clippy -r            # copy the most recent file
   clippy -r 3          # copy the 3 most recent files
@@ -98,20 +169,37 @@ Examples:
   # - Enter to copy (selected items or current item)
   # - p to copy & paste (selected items or current item)
 
+  # Copy the most recent screenshot (found via Spotlight)
+  clippy --screenshot          # copy the newest screenshot
+  clippy --screenshot 5        # pick from the 5 newest screenshots
+  clippy --screenshot 1h --paste # pick from the last hour's screenshots and paste here
+
   # Search for files using Spotlight
   clippy -f invoice            # search for files matching "invoice"
   clippy -f screenshot         # search for screenshots
   clippy -f .pdf               # search for all PDF files (by extension)
   clippy -f report.xlsx        # search for "report.xlsx" (specific file)
+  clippy -f taxes --since all  # include files older than the default 90 days
+  clippy -f README --case-sensitive # exact-case match (accents are always ignored)
+  clippy -f .pdf --no-spotlight --folders downloads # walk folders instead of Spotlight
   # Shows interactive picker with results
 
+  # Filter Spotlight search by metadata attributes
+  clippy -f .pdf --attr kMDItemAuthors='Jane*'
+  clippy --attr kMDItemIsScreenCapture=1
+
   # Copy and paste in one step
   clippy file.txt --paste      # copy to clipboard AND paste to current dir
   clippy -r --paste            # copy most recent file and paste here
   clippy -i --paste            # pick recent file interactively and paste here
 
+  # Preview what would happen without touching the clipboard
+  clippy -v --dry-run report.pdf
+  clippy -v --dry-run -r 3 --paste
+
   # Clear clipboard
   clippy --clear               # empty the clipboard
+  clippy --image-to-file       # turn a clipboard image into a file reference
   echo -n | clippy             # also clears the clipboard
 
   # Content type detection (auto-detects JSON, HTML, XML)
@@ -126,6 +214,20 @@ Configuration:
     temp_dir = /path      # Custom directory for temporary files
     absolute_time = true  # Show absolute timestamps in picker (default: relative)
     default_folders = downloads,desktop,documents  # Default folders to search (defaults to all three)
+    confirm_threshold = 10  # Ask before -r copies more files than this (0 = never ask)
+    max_files = 100         # Never copy more than this many files from -r or --dir (0 = no limit)
+    paste_confirm_size = 1GB  # Ask before --paste copies more than this (0 = never ask)
+    lock = true           # Take turns with other clippy/pasty runs (flock on clippy.lock in the temp dir)
+    lock_timeout = 10s    # How long to wait for the lock before giving up
+    url_timeout = 1m      # Timeout for --url requests (default 30s)
+    picker_keys.select = x  # Rebind picker keys: up, down, select, copy, paste, quit, paths, sort (comma-separate several)
+    picker_paths = folder  # Show name, folder/name or full path in the picker (f toggles)
+    mime_uti.application/x-myformat = com.me.myformat  # Copy text of this MIME type as this UTI
+    text_types = application/x-myformat  # Extra MIME types to treat as text (comma-separate several)
+
+Troubleshooting:
+  clippy doctor        # Check clipboard, folders, Spotlight and temp files
+  clippy info          # Build and config details for bug reports (--json available)
 
 MCP Server:
   Install clippy as an MCP server for Claude Code:
@@ -135,13 +237,135 @@ MCP Server:
 		Version: fmt.Sprintf("%s (%s) built on %s", common.Version, common.Commit, common.Date),
 		Run: func(cmd *cobra.Command, args []string) {
 			// Load config file
-			loadConfig()
+			loadConfig(cmd)
 
-			// Initialize logger
-			logger = common.SetupLogger(verbose, debug)
+			// Initialize logger; --quiet wins over -v and --debug
+			if quiet {
+				verbose, debug = false, false
+			}
+			loggerOpts := common.LoggerOptions{Quiet: quiet}
+			if bellFlag {
+				loggerOpts.OnError = ringBell(false)
+			}
+			logger = common.SetupLoggerWithOptions(verbose, debug, loggerOpts)
+
+			if err := common.UsePasteboard(pasteboardName); err != nil {
+				logger.Error("%v", err)
+				os.Exit(common.ExitUsage)
+			}
+
+			if noClear && (textMode || mimeType != "") {
+				logger.Error("--no-clear adds file references or piped input; it can't be combined with --text or --mime")
+				os.Exit(common.ExitUsage)
+			}
+			if skipIfSame && (textMode || mimeType != "") {
+				logger.Error("--skip-if-same works with file references or piped input; it can't be combined with --text or --mime")
+				os.Exit(common.ExitUsage)
+			}
+			if asName != "" && textMode {
+				logger.Error("--as renames a file reference; it can't be combined with --text")
+				os.Exit(common.ExitUsage)
+			}
+			if withThumbnail && (textMode || noClear || skipIfSame || asName != "") {
+				logger.Error("--with-thumbnail copies an image file reference; it can't be combined with --text, --no-clear, --skip-if-same or --as")
+				os.Exit(common.ExitUsage)
+			}
+
+			if sortFlag != "" {
+				order, err := recent.ParseSortOrder(sortFlag)
+				if err != nil {
+					logger.Error("%v", err)
+					os.Exit(common.ExitUsage)
+				}
+				sortOrder = order
+			}
+			if spec := pickSpec(); spec != "" {
+				positions, err := parsePick(spec)
+				if err != nil {
+					logger.Error("%v", err)
+					os.Exit(common.ExitUsage)
+				}
+				pickPositions = positions
+			}
+
+			if dryRun {
+				clippy.SetDryRun(true, func(action string) {
+					logger.Verbose("[dry-run] would %s", action)
+				})
+			}
+			if smartFlavor && mimeType == "" {
+				applySmartFlavor()
+			}
+
+			// Handle --quote flag (arguments or stdin are the quoted text, not files)
+			if quoteFlag {
+				handleQuote(args)
+				return
+			}
+			if quoteSource != "" {
+				logger.Error("--source attributes a --quote; use them together")
+				os.Exit(common.ExitUsage)
+			}
+			if cmd.Flags().Changed("max-files") {
+				if maxFilesFlag < 0 {
+					logger.Error("--max-files must be 0 (no limit) or more")
+					os.Exit(common.ExitUsage)
+				}
+				maxFilesLimit = maxFilesFlag
+			}
+			if (len(typeFlags) > 0 || recursive) && !cmd.Flags().Changed("dir") {
+				logger.Error("--type and --recursive filter the files --dir copies; use them with --dir")
+				os.Exit(common.ExitUsage)
+			}
+			if gitPath && len(args) == 0 {
+				logger.Error("--git-path copies the repository-relative path of the files given as arguments")
+				os.Exit(common.ExitUsage)
+			}
+			if refFlag != "" {
+				if len(args) != 1 {
+					logger.Error("--ref points at lines in one file; give exactly one file argument")
+					os.Exit(common.ExitUsage)
+				}
+				start, end, err := parseLineRange(refFlag)
+				if err != nil {
+					logger.Error("%v", err)
+					os.Exit(common.ExitUsage)
+				}
+				refStart, refEnd = start, end
+			}
+			if cmd.Flags().Changed("manifest") && !cmd.Flags().Changed("recent") && !cmd.Flags().Changed("interactive") {
+				logger.Error("--manifest records the files -r or -i would copy; use it with one of them")
+				os.Exit(common.ExitUsage)
+			}
 
 			// If files are provided as arguments, handle them (takes precedence)
 			if len(args) > 0 {
+				// Expand quoted glob patterns such as '**/*.png'
+				expanded, err := clippy.ExpandGlobsWithOptions(args, clippy.GlobOptions{
+					IncludeHidden: includeHidden,
+					IncludeTemp:   includeTemp,
+				})
+				if err != nil {
+					logger.Error("%v", err)
+					os.Exit(common.ExitCode(err))
+				}
+				if len(expanded) != len(args) {
+					logger.Debug("Expanded %d arguments to %d files", len(args), len(expanded))
+				}
+				args = expanded
+
+				if gitPath || refFlag != "" {
+					handlePaths(args)
+					return
+				}
+
+				if explain {
+					if err := explainFiles(args); err != nil {
+						os.Exit(exitCode(err))
+					}
+					return
+				}
+
 				if len(args) == 1 {
 					handleFileMode(args[0])
 				} else {
@@ -154,8 +378,50 @@ MCP Server:
 				return
 			}
 
-			// Handle -f flag (Spotlight search)
-			if cmd.Flags().Changed("find") {
+			// Handle --html/--rtf/--plain/--flavor (several representations from files)
+			if htmlFile != "" || rtfFile != "" || plainFile != "" || len(flavorFiles) > 0 {
+				handleFlavorFiles()
+				return
+			}
+
+			// Handle --stdin-image flag (raw RGBA pixels on stdin)
+			if stdinImage != "" {
+				handleStdinImage(stdinImage)
+				return
+			}
+
+			// Handle --url flag (fetch and copy)
+			if cmd.Flags().Changed("url") {
+				handleURLMode(urlFlag)
+				// Run cleanup and return
+				if cleanup {
+					cleanupOldTempFiles()
+				}
+				return
+			}
+
+			// Handle --dir flag (every file of a type in a folder)
+			if cmd.Flags().Changed("dir") {
+				handleDirMode(dirFlag)
+				// Run cleanup and return
+				if cleanup {
+					cleanupOldTempFiles()
+				}
+				return
+			}
+
+			// Handle --screenshot flag (most recent screenshot)
+			if cmd.Flags().Changed("screenshot") {
+				handleScreenshotMode(screenshotFlag)
+				// Run cleanup and return
+				if cleanup {
+					cleanupOldTempFiles()
+				}
+				return
+			}
+
+			// Handle -f flag (Spotlight search), optionally filtered by --attr
+			if cmd.Flags().Changed("find") || len(attrFlags) > 0 {
 				handleFindMode(findFlag)
 				// Run cleanup and return
 				if cleanup {
@@ -184,13 +450,44 @@ MCP Server:
 				return
 			}
 
+			// Handle --image-to-file flag (clipboard image → file reference)
+			if imageToFile {
+				path, err := clippy.ImageToFile(tempDir)
+				if err != nil {
+					logger.Error("%v", err)
+					os.Exit(common.ExitCode(err))
+				}
+				reportSuccess("✅ Saved clipboard image to %s and copied it as a file reference", path)
+				runPostCopyHook("files", path)
+				if cleanup {
+					cleanupOldTempFiles()
+				}
+				return
+			}
+
+			// Handle --save-link flag (clipboard URL → bookmark file)
+			if saveLink != "" {
+				link, err := clippy.ClipboardURL()
+				if err != nil {
+					logger.Error("%v", err)
+					os.Exit(common.ExitCode(err))
+				}
+				path, err := clippy.SaveLinkFile(link, saveLink)
+				if err != nil {
+					logger.Error("%v", err)
+					os.Exit(common.ExitCode(err))
+				}
+				reportSuccess("✅ Saved a link to %s as %s", link, path)
+				return
+			}
+
 			// Handle --clear flag
 			if clearFlag {
 				if err := clearClipboard(); err != nil {
 					logger.Error("Failed to clear clipboard: %v", err)
-					os.Exit(1)
+					os.Exit(common.ExitCode(err))
 				}
-				logger.Verbose("✅ Clipboard cleared")
+				reportSuccess("✅ Clipboard cleared")
 				// Run cleanup and return
 				if cleanup {
 					cleanupOldTempFiles()
@@ -209,7 +506,7 @@ MCP Server:
 	}
 
 	// Add flags
-	common.AddCommonFlags(rootCmd, &verbose, &debug)
+	common.AddCommonFlags(rootCmd, &verbose, &debug, &quiet)
 
 	// Recent flag with optional value
 	rootCmd.PersistentFlags().StringVarP(&recentFlag, "recent", "r", "", "Copy most recent file(s) from Downloads, Desktop, and Documents (defaults to 1, or specify number/duration like 3, 5m, 1h)")
@@ -219,21 +516,72 @@ MCP Server:
 	rootCmd.PersistentFlags().StringVarP(&interactiveFlag, "interactive", "i", "", "Show interactive picker for recent files from Downloads, Desktop, and Documents (optional: number/duration like 3, 5m, 1h)")
 	rootCmd.PersistentFlags().Lookup("interactive").NoOptDefVal = " " // Allow -i without value
 
+	rootCmd.PersistentFlags().StringArrayVar(&attrFlags, "attr", nil, "Filter Spotlight search by metadata attribute (repeatable), e.g. --attr kMDItemIsScreenCapture=1 or --attr kMDItemAuthors='Jane*'")
+
+	rootCmd.PersistentFlags().StringVar(&sinceFlag, "since", "90d", "Only find files modified within this window for -f (e.g. 30d, 2 weeks ago, or 'all' for no limit; wider is slower)")
+
+	// Screenshot flag with optional value
+	rootCmd.PersistentFlags().StringVar(&screenshotFlag, "screenshot", "", "Copy the most recent screenshot (optional: number/duration like 3, 1h shows a picker)")
+	rootCmd.PersistentFlags().Lookup("screenshot").NoOptDefVal = " " // Allow --screenshot without value
+
 	// Find flag for Spotlight search
 	rootCmd.PersistentFlags().StringVarP(&findFlag, "find", "f", "", "Search for files using Spotlight (e.g., 'invoice', '.pdf', 'report.xlsx')")
+	rootCmd.PersistentFlags().BoolVar(&caseSensitive, "case-sensitive", false, "Match -f queries with exact case (accents are ignored either way)")
+	rootCmd.PersistentFlags().BoolVar(&noSpotlight, "no-spotlight", false, "Search for -f by walking --folders (or Downloads, Desktop, Documents) instead of using Spotlight")
 
 	rootCmd.PersistentFlags().BoolVar(&paste, "paste", false, "Also paste copied files to current directory")
+	rootCmd.PersistentFlags().BoolVar(&reveal, "reveal", false, "Also reveal copied files in Finder (brings Finder to the front)")
+	rootCmd.PersistentFlags().BoolVar(&openFlag, "open", false, "Also open copied files (or piped text, in your text editor) with their default app")
 	rootCmd.PersistentFlags().BoolVar(&cleanup, "cleanup", true, "Enable automatic temp file cleanup")
 	rootCmd.PersistentFlags().BoolVarP(&textMode, "text", "t", false, "Copy text files as content instead of file reference")
 	rootCmd.PersistentFlags().BoolVar(&clearFlag, "clear", false, "Clear the clipboard")
+	rootCmd.PersistentFlags().StringVar(&saveLink, "save-link", "", "Save the URL on the clipboard as a bookmark file: NAME.webloc, or a Windows shortcut if NAME ends in .url")
+	rootCmd.PersistentFlags().BoolVar(&quoteFlag, "quote", false, "Copy the arguments (or stdin) as a quote: an HTML blockquote for rich editors plus plain text")
+	rootCmd.PersistentFlags().StringVar(&quoteSource, "source", "", "Source URL to attribute a --quote to (shown as a cite link)")
+	rootCmd.PersistentFlags().BoolVar(&withThumbnail, "with-thumbnail", false, "Also put a small PNG preview of the image on the clipboard, alongside the file reference")
+	rootCmd.PersistentFlags().BoolVar(&imageToFile, "image-to-file", false, "Save the clipboard image to a temp file and replace the clipboard with a reference to it")
 	rootCmd.PersistentFlags().StringSliceVar(&foldersFlag, "folders", nil, "Specific folders to search (e.g., --folders downloads,desktop). Options: downloads, desktop, documents")
+	rootCmd.PersistentFlags().BoolVarP(&binaryFlag, "binary", "b", false, "Save piped input to a temp file and copy it as a file reference, even if it's text")
+	rootCmd.PersistentFlags().StringVar(&htmlFile, "html", "", "Copy the file's content as HTML (combine with --plain, --rtf or --flavor)")
+	rootCmd.PersistentFlags().StringVar(&rtfFile, "rtf", "", "Copy the file's content as RTF (combine with --html, --plain or --flavor)")
+	rootCmd.PersistentFlags().StringVar(&plainFile, "plain", "", "Copy the file's content as plain text (combine with --html, --rtf or --flavor)")
+	rootCmd.PersistentFlags().StringArrayVar(&flavorFiles, "flavor", nil, "Copy a file's content as the given type, as TYPE=FILE (UTI or MIME type, repeatable)")
+	rootCmd.PersistentFlags().StringVar(&stdinImage, "stdin-image", "", "Read raw RGBA pixels of the given size (WxH) from stdin and copy them as a PNG image")
+	rootCmd.PersistentFlags().StringVar(&wrapTemplate, "wrap", "", "Wrap copied text in a template: {} is the text, {filename} the file name (e.g. '```\\n{}\\n```')")
+	rootCmd.PersistentFlags().StringVar(&asName, "as", "", "Copy a single file under a different name (a temp link; the original is untouched)")
+	rootCmd.PersistentFlags().StringVarP(&nameFlag, "name", "o", "", "File name for piped binary data copied as a file reference (e.g. report.pdf)")
 	rootCmd.PersistentFlags().StringVarP(&mimeType, "mime", "m", "", "Manually specify MIME type for clipboard (e.g., text/html, application/json, text/xml)")
+	rootCmd.PersistentFlags().StringVar(&urlFlag, "url", "", "Fetch a URL and copy its content (binary as file reference, text as text)")
+	rootCmd.PersistentFlags().BoolVar(&includeHidden, "include-hidden", false, "Include dotfiles and hidden folders in recent files and glob matches")
+	rootCmd.PersistentFlags().BoolVar(&includeTemp, "include-temp", false, "Include temp files and partial downloads (.part, .crdownload) in recent files and glob matches")
+	rootCmd.PersistentFlags().DurationVar(&waitStable, "wait-stable", 0, "Before -r copies, wait until the files have stopped changing (optional: how long to wait, default 30s)")
+	rootCmd.PersistentFlags().Lookup("wait-stable").NoOptDefVal = defaultWaitStable.String()
+	rootCmd.PersistentFlags().StringVar(&sortFlag, "sort", "", "Order recent files and picker results by age, name or size, optionally with :asc or :desc (default newest first)")
+	rootCmd.PersistentFlags().StringVar(&pickFlag, "pick", "", "Answer the picker without showing it: 1-based positions such as 1,3 or 2-4 (or set CLIPPY_PICK)")
+	rootCmd.PersistentFlags().StringVar(&dirFlag, "dir", "", "Copy every file in a folder as references, regardless of age (narrow it with --type)")
+	rootCmd.PersistentFlags().StringSliceVar(&typeFlags, "type", nil, "Only copy --dir files of these types: MIME types (image/png), families (image/) or extensions (png, .pdf)")
+	rootCmd.PersistentFlags().BoolVar(&recursive, "recursive", false, "Include files in subfolders of --dir")
+	rootCmd.PersistentFlags().StringVar(&manifestPath, "manifest", "", "Write the files -r or -i would copy to a file instead (one path per line, or a playlist for .m3u/.m3u8; - for stdout)")
+	rootCmd.PersistentFlags().IntVar(&maxFilesFlag, "max-files", defaultMaxFiles, "Never copy more than this many files from -r or --dir, warning when the limit cuts the list (0 = no limit)")
+	rootCmd.PersistentFlags().BoolVarP(&assumeYes, "yes", "y", false, "Don't ask for confirmation before copying many files or pasting large ones")
+	rootCmd.PersistentFlags().BoolVar(&noClear, "no-clear", false, "Add to the clipboard instead of replacing its contents (combine text and files from separate runs)")
+	rootCmd.PersistentFlags().BoolVar(&skipIfSame, "skip-if-same", false, "Don't rewrite the clipboard when it already holds the same content (keeps clipboard managers quiet)")
+	rootCmd.PersistentFlags().BoolVar(&bellFlag, "bell", false, "Play a sound when the copy or paste finishes, and a different one on error")
+	rootCmd.PersistentFlags().BoolVar(&gitPath, "git-path", false, "Copy each file's path relative to its git repository root as text, instead of the file")
+	rootCmd.PersistentFlags().StringVar(&refFlag, "ref", "", "Copy a path:line reference (42) or path:start-end (42-50) to the file as text; combine with --git-path for a repo-relative path")
+	rootCmd.PersistentFlags().BoolVar(&smartFlavor, "smart-flavor", false, "Copy text as plain text when a terminal or code editor is the frontmost app (a heuristic; --mime wins)")
+	rootCmd.PersistentFlags().BoolVar(&notifyFlag, "notify", false, "Post a macOS notification when the copy or paste finishes (handy for hotkeys)")
+	rootCmd.PersistentFlags().StringVar(&pasteboardName, "pasteboard", "", "Use a named macOS pasteboard instead of the general clipboard (e.g. com.example.automation)")
+	rootCmd.PersistentFlags().BoolVar(&explain, "explain", false, "Print how each file would be copied (UTI, MIME type, text or reference, and why) without copying")
+	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "Detect and resolve everything but don't modify the clipboard or any files (use with -v to see what would happen)")
+	rootCmd.PersistentFlags().DurationVar(&urlTimeout, "url-timeout", clippy.DefaultURLTimeout, "Timeout for --url requests (e.g., 10s, 1m)")
 
 	// Add MCP server subcommand
 	var mcpExamplesPath string
 	var mcpToolsPath string
 	var mcpPromptsPath string
 	var mcpStrictMetadata bool
+	var mcpServerName string
 
 	var mcpCmd = &cobra.Command{
 		Use:   "mcp-server",
@@ -246,6 +594,8 @@ Available tools:
 - clipboard_copy: Copy text or files to clipboard
 - clipboard_paste: Paste clipboard content to files
 - get_recent_downloads: List recently downloaded files
+- clipboard_get: Read the current clipboard text, file paths or image details
+- clipboard_status: Snapshot of the clipboard and the most recent download
 
 Example usage with Claude Desktop:
 Add to ~/Library/Application Support/Claude/claude_desktop_config.json:
@@ -258,12 +608,18 @@ Add to ~/Library/Application Support/Claude/claude_desktop_config.json:
   }
 }`,
 		Run: func(cmd *cobra.Command, args []string) {
+			if err := common.UsePasteboard(pasteboardName); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(common.ExitUsage)
+			}
 			fmt.Fprintln(os.Stderr, "Starting Clippy MCP server...")
 			if err := mcp.StartServerWithOptions(mcp.ServerOptions{
 				ExamplesPath:   mcpExamplesPath,
 				ToolsPath:      mcpToolsPath,
 				PromptsPath:    mcpPromptsPath,
 				StrictMetadata: mcpStrictMetadata,
+				Name:           mcpServerName,
+				Version:        common.Version,
 			}); err != nil {
 				fmt.Fprintf(os.Stderr, "MCP server error: %v\n", err)
 				os.Exit(1)
@@ -275,13 +631,241 @@ Add to ~/Library/Application Support/Claude/claude_desktop_config.json:
 	mcpCmd.Flags().StringVar(&mcpToolsPath, "tools", "", "Path to JSON file with MCP tool description overrides")
 	mcpCmd.Flags().StringVar(&mcpPromptsPath, "prompts", "", "Path to JSON file with MCP prompt overrides")
 	mcpCmd.Flags().BoolVar(&mcpStrictMetadata, "strict-metadata", false, "Require override files to provide descriptions for every tool/prompt/parameter")
+	mcpCmd.Flags().StringVar(&mcpServerName, "server-name", "", "Name reported to MCP clients, e.g. to tell per-project instances apart (default: $CLIPPY_MCP_NAME or \"Clippy MCP Server\")")
 
 	rootCmd.AddCommand(mcpCmd)
 
-	// Execute the command
+	var doctorCmd = &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the environment for common problems",
+		Long: `Check the environment clippy runs in and print a pass/warn/fail report.
+
+Checks the clipboard backend, home directory, the Downloads/Desktop/Documents
+folders used by -r and -i, Spotlight indexing, temp directory writability and
+leftover clippy-* temp files. Exits with status 1 if any check fails.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			loadConfig(cmd)
+			if !runDoctor() {
+				os.Exit(1)
+			}
+		},
+	}
+	rootCmd.AddCommand(doctorCmd)
+
+	var selftestCmd = &cobra.Command{
+		Use:   "selftest",
+		Short: "Check that copying and reading back through the clipboard works",
+		Long: `Copy a known string and read it back, then copy a reference to a temp file and
+read it back, and report whether each round trip succeeded. Unlike doctor, this
+exercises the real clipboard backend end to end. The previous clipboard content
+is restored afterwards when possible. Exits with status 1 if a round trip fails.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			loadConfig(cmd)
+			if !runSelfTest(tempDir) {
+				os.Exit(1)
+			}
+		},
+	}
+	rootCmd.AddCommand(selftestCmd)
+
+	var infoJSON bool
+	var infoCmd = &cobra.Command{
+		Use:   "info",
+		Short: "Print build details and the resolved configuration",
+		Long: `Print the version, commit and build date, the config file path and its settings,
+the active clipboard backend, the temp directory and the folders searched by -r and -i.
+Include this output in bug reports. Use --json for machine-readable output.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			loadConfig(cmd)
+			logger = common.SetupLogger(verbose, debug)
+			if err := printInfo(buildInfoReport(), infoJSON); err != nil {
+				logger.Error("Could not print info: %v", err)
+				os.Exit(1)
+			}
+		},
+	}
+	infoCmd.Flags().BoolVar(&infoJSON, "json", false, "Print machine-readable JSON")
+	rootCmd.AddCommand(infoCmd)
+
+	var statsJSON bool
+	var statsCmd = &cobra.Command{
+		Use:   "stats",
+		Short: "Summarize clipboard usage",
+		// Hidden until clippy records a history of copies to summarize
+		Hidden: true,
+		Long: `Summarize clipboard usage: copies by content type, average size and copies
+per day. clippy doesn't keep a history of copies yet, so this currently reports
+that no history is available. Use --json for machine-readable output.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			loadConfig(cmd)
+			logger = common.SetupLogger(verbose, debug)
+			if err := printStats(os.Stdout, buildStatsReport(), statsJSON); err != nil {
+				logger.Error("Could not print stats: %v", err)
+				os.Exit(1)
+			}
+		},
+	}
+	statsCmd.Flags().BoolVar(&statsJSON, "json", false, "Print machine-readable JSON")
+	rootCmd.AddCommand(statsCmd)
+
+	// setupSubcommand does the config, logging, pasteboard and dry-run setup the
+	// root command does, for subcommands that read or write the clipboard
+	setupSubcommand := func(cmd *cobra.Command) {
+		loadConfig(cmd)
+		logger = common.SetupLoggerWithOptions(verbose, debug, common.LoggerOptions{Quiet: quiet})
+		if err := common.UsePasteboard(pasteboardName); err != nil {
+			logger.Error("%v", err)
+			os.Exit(common.ExitUsage)
+		}
+		if dryRun {
+			clippy.SetDryRun(true, func(action string) {
+				logger.Verbose("[dry-run] would %s", action)
+			})
+		}
+	}
+
+	var exportCmd = &cobra.Command{
+		Use:   "export [file]",
+		Short: "Save everything on the clipboard to a snapshot file",
+		Long: `Save every representation of the current clipboard item (plain text, HTML, RTF,
+images, file references...) to a JSON snapshot that clippy import can restore,
+later or on another Mac. Writes to stdout if no file (or -) is given, e.g.:
+
+  clippy export | ssh other-mac clippy import`,
+		Args: cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			setupSubcommand(cmd)
+			if err := runExport(snapshotPath(args)); err != nil {
+				logger.Error("%v", err)
+				os.Exit(exitCode(err))
+			}
+		},
+	}
+	rootCmd.AddCommand(exportCmd)
+
+	var importCmd = &cobra.Command{
+		Use:   "import [file]",
+		Short: "Restore the clipboard from a snapshot file",
+		Long: `Restore a snapshot saved by clippy export, putting all of its representations
+back on the clipboard at once. File references are restored as files when they
+all exist on this Mac. Reads stdin if no file (or -) is given.`,
+		Args: cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			setupSubcommand(cmd)
+			if err := runImport(snapshotPath(args)); err != nil {
+				logger.Error("%v", err)
+				os.Exit(exitCode(err))
+			}
+		},
+	}
+	rootCmd.AddCommand(importCmd)
+
+	var pushCmd = &cobra.Command{
+		Use:   "push",
+		Short: "Save the clipboard on a stack to restore later with pop",
+		Long: `Save everything on the clipboard (all representations, as clippy export does) on
+top of a small stack kept in your cache directory. The clipboard itself is left
+as it is, so you can copy something else and bring the saved content back with
+clippy pop. The stack keeps the 20 most recent pushes.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			setupSubcommand(cmd)
+			if err := runPush(); err != nil {
+				logger.Error("%v", err)
+				os.Exit(exitCode(err))
+			}
+		},
+	}
+	rootCmd.AddCommand(pushCmd)
+
+	var popCmd = &cobra.Command{
+		Use:   "pop",
+		Short: "Restore the clipboard saved by the last push",
+		Long:  `Put the clipboard saved by the most recent clippy push back and remove it from the stack.`,
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			setupSubcommand(cmd)
+			if err := runPop(); err != nil {
+				logger.Error("%v", err)
+				os.Exit(exitCode(err))
+			}
+		},
+	}
+	rootCmd.AddCommand(popCmd)
+
+	var stackCmd = &cobra.Command{
+		Use:   "stack",
+		Short: "Inspect the clipboard stack used by push and pop",
+	}
+	stackCmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List the saved clipboards, most recent (the next pop) first",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			setupSubcommand(cmd)
+			if err := printStack(); err != nil {
+				logger.Error("%v", err)
+				os.Exit(exitCode(err))
+			}
+		},
+	})
+	rootCmd.AddCommand(stackCmd)
+
+	// slotCommand builds save, restore and swap, which all take a slot name
+	slotCommand := func(use, short, long string, run func(name string) error) *cobra.Command {
+		return &cobra.Command{
+			Use:   use + " NAME",
+			Short: short,
+			Long:  long,
+			Args:  cobra.ExactArgs(1),
+			Run: func(cmd *cobra.Command, args []string) {
+				setupSubcommand(cmd)
+				if err := run(args[0]); err != nil {
+					logger.Error("%v", err)
+					os.Exit(exitCode(err))
+				}
+			},
+		}
+	}
+	rootCmd.AddCommand(slotCommand("save", "Save the clipboard in a named slot",
+		`Save everything on the clipboard (all representations, as clippy export does)
+under NAME in your cache directory, replacing anything saved there before. The
+clipboard itself is left as it is. Bring it back with clippy restore NAME.`, runSaveSlot))
+	rootCmd.AddCommand(slotCommand("restore", "Put the clipboard saved in a named slot back",
+		`Put the clipboard saved with clippy save NAME back. The slot is kept, so it can
+be restored again.`, runRestoreSlot))
+	rootCmd.AddCommand(slotCommand("swap", "Exchange the clipboard with a named slot",
+		`Put the clipboard saved in NAME back and save the current clipboard in NAME in
+its place. Swapping twice gets you back where you started.`, runSwapSlot))
+
+	var slotsCmd = &cobra.Command{
+		Use:   "slots",
+		Short: "List the named slots saved with clippy save",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			setupSubcommand(cmd)
+			if err := printSlots(); err != nil {
+				logger.Error("%v", err)
+				os.Exit(exitCode(err))
+			}
+		},
+	}
+	slotsCmd.AddCommand(slotCommand("rm", "Delete a named slot", "Delete the slot saved under NAME.", func(name string) error {
+		if err := clippy.DeleteSlot(name); err != nil {
+			return fmt.Errorf("could not delete slot %s: %w", name, err)
+		}
+		reportSuccess("✅ Deleted slot %s", name)
+		return nil
+	}))
+	rootCmd.AddCommand(slotsCmd)
+
+	// Execute the command, then give post-copy hooks a chance to finish
+	defer common.WaitForHooks()
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		if !quiet {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+		os.Exit(common.ExitUsage)
 	}
 }
 
@@ -290,13 +874,104 @@ func clearClipboard() error {
 	return clippy.ClearClipboard()
 }
 
+// errNoRecentFiles is returned when -r, -i or --screenshot find nothing to copy
+var errNoRecentFiles = errors.New("no recent files found")
+
+// exitCode maps an error to the exit code for its category (see common.ExitCode)
+func exitCode(err error) int {
+	if errors.Is(err, errNoRecentFiles) {
+		return common.ExitNoFiles
+	}
+	return common.ExitCode(err)
+}
+
+// handleDirMode copies every file in dir that matches --type, newest first (or
+// in --sort order). Hidden and temp files are skipped unless --include-hidden or
+// --include-temp says otherwise, as for -r.
+func handleDirMode(dir string) {
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		logger.Error("%s is not a folder", dir)
+		os.Exit(common.ExitNoFiles)
+	}
+
+	opts := recent.FindOptions{
+		Directories:   []string{dir},
+		ExcludeTemp:   true,
+		IncludeHidden: includeHidden,
+		IncludeTemp:   includeTemp,
+		NoRecurse:     !recursive,
+	}
+	for _, t := range typeFlags {
+		t = strings.ToLower(strings.TrimSpace(t))
+		switch {
+		case t == "":
+		case strings.Contains(t, "/"):
+			opts.MimeTypes = append(opts.MimeTypes, t)
+		default:
+			opts.Extensions = append(opts.Extensions, "."+strings.TrimPrefix(t, "."))
+		}
+	}
+
+	files, err := findRecentFiles(opts)
+	if err != nil {
+		logger.Error("Could not list %s: %v", dir, err)
+		os.Exit(exitCode(err))
+	}
+	if len(files) == 0 {
+		if len(typeFlags) > 0 {
+			logger.Error("No files in %s match --type %s", dir, strings.Join(typeFlags, ","))
+		} else {
+			logger.Error("No files in %s", dir)
+		}
+		os.Exit(common.ExitNoFiles)
+	}
+	if sortOrder != recent.DefaultSortOrder {
+		recent.SortFiles(files, sortOrder)
+	}
+
+	paths := make([]string, len(files))
+	for i, file := range files {
+		paths[i] = file.Path
+	}
+	paths = capFiles(paths)
+	if len(paths) == 1 {
+		handleFileMode(paths[0])
+		return
+	}
+	if !confirmCopy(len(paths)) {
+		fmt.Println("Cancelled.")
+		os.Exit(0)
+	}
+	logger.Verbose("Copying %s from %s", plural(len(paths), "file"), dir)
+	handleMultipleFiles(paths)
+}
+
+// defaultConfirmThreshold is how many files -r copies before asking for confirmation
+const defaultConfirmThreshold = 10
+
+// defaultMaxFiles is the most files -r or --dir copies unless --max-files or
+// max_files says otherwise
+const defaultMaxFiles = 100
+
+// capFiles cuts paths to the --max-files ceiling, warning when that drops any.
+// The ceiling guards against a wide -r or --dir flooding the clipboard; it is
+// separate from the count asked for, and from the confirmation prompt, which
+// sees the capped number.
+func capFiles(paths []string) []string {
+	if maxFilesLimit <= 0 || len(paths) <= maxFilesLimit {
+		return paths
+	}
+	logger.Warn("%s matched; copying only the first %d (raise --max-files or max_files to copy more)", plural(len(paths), "file"), maxFilesLimit)
+	return paths[:maxFilesLimit]
+}
+
 // handleRecentMode handles the --recent flag
 func handleRecentMode(timeStr string, interactiveMode bool) {
 	// Use Core function to parse the argument
 	count, maxAge, err := recent.ParseRecentArgument(timeStr)
 	if err != nil {
 		logger.Error("%v", err)
-		os.Exit(1)
+		os.Exit(common.ExitUsage)
 	}
 
 	// Get recent files based on criteria
@@ -313,28 +988,17 @@ func handleRecentMode(timeStr string, interactiveMode bool) {
 	}
 
 	// Handle folder selection if specified
-	var searchDirs []string
-	if len(foldersFlag) > 0 {
-		searchDirs = mapFoldersToDirectories(foldersFlag)
-		if len(searchDirs) == 0 {
-			logger.Error("Invalid folder selection. Use: downloads, desktop, documents")
-			os.Exit(1)
-		}
-	} else if len(defaultFolders) > 0 {
-		// Use config defaults if no command line folders specified
-		searchDirs = mapFoldersToDirectories(defaultFolders)
-		logger.Debug("Using default folders from config: %v", searchDirs)
-	}
+	searchDirs := selectedSearchDirs()
 
 	files, err := getRecentDownloadsWithDirs(config, maxFiles, searchDirs)
 	if err != nil {
 		logger.Error("Failed to find recent files: %v", err)
-		os.Exit(1)
+		os.Exit(exitCode(err))
 	}
 
 	if len(files) == 0 {
 		logger.Error("No recent files found")
-		os.Exit(1)
+		os.Exit(common.ExitNoFiles)
 	}
 
 	// If interactive mode is requested, show the picker
@@ -366,6 +1030,19 @@ func handleRecentMode(timeStr string, interactiveMode bool) {
 			paste = true
 		}
 
+		selected := make([]string, len(result.Files))
+		for i, file := range result.Files {
+			selected[i] = file.Path
+		}
+		waitForStableFiles(selected)
+		if manifestPath != "" {
+			if err := writeManifest(manifestPath, selected); err != nil {
+				logger.Error("%v", err)
+				os.Exit(exitCode(err))
+			}
+			return
+		}
+
 		// Handle selected files
 		if len(result.Files) == 1 {
 			logger.Verbose("Selected: %s (modified %s ago)", result.Files[0].Path, result.Files[0].Age().Round(time.Second))
@@ -381,11 +1058,31 @@ func handleRecentMode(timeStr string, interactiveMode bool) {
 		}
 	} else {
 		// Non-interactive mode: files are already limited by Core layer
+		selected := make([]string, len(files))
+		for i, file := range files {
+			selected[i] = file.Path
+		}
+		// files and selected are in the same order, so cutting one to the
+		// --max-files ceiling cuts the other
+		selected = capFiles(selected)
+		files = files[:len(selected)]
+		waitForStableFiles(selected)
+		if manifestPath != "" {
+			if err := writeManifest(manifestPath, selected); err != nil {
+				logger.Error("%v", err)
+				os.Exit(exitCode(err))
+			}
+			return
+		}
 		if len(files) == 1 {
 			logger.Verbose("Copying most recent file: %s (modified %s ago)",
 				files[0].Name, files[0].Age().Round(time.Second))
 			handleFileMode(files[0].Path)
 		} else {
+			if !confirmCopy(len(files)) {
+				fmt.Println("Cancelled.")
+				os.Exit(0)
+			}
 			logger.Verbose("Copying %d most recent files:", len(files))
 			var paths []string
 			for _, file := range files {
@@ -397,74 +1094,159 @@ func handleRecentMode(timeStr string, interactiveMode bool) {
 	}
 }
 
+// confirmCopy asks before copying more than confirmLimit files. It only
+// prompts when both stdin and stdout are terminals; --yes, a threshold of 0
+// and scripted use proceed without asking.
+func confirmCopy(count int) bool {
+	if assumeYes || confirmLimit <= 0 || count <= confirmLimit {
+		return true
+	}
+	if !common.IsInteractive() {
+		return true
+	}
+	return common.Confirm(fmt.Sprintf("Copy %d files?", count))
+}
+
+// defaultWaitStable is how long --wait-stable waits when no timeout is given
+const defaultWaitStable = 30 * time.Second
+
+// stableInterval is how long a file must stay unchanged for --wait-stable
+const stableInterval = time.Second
+
+// waitForStableFiles waits with --wait-stable until each file has finished
+// downloading, and exits if one is still being written at the timeout
+func waitForStableFiles(paths []string) {
+	if waitStable <= 0 {
+		return
+	}
+	deadline := time.Now().Add(waitStable)
+	for _, path := range paths {
+		logger.Verbose("Waiting for %s to stop changing...", filepath.Base(path))
+		if err := recent.WaitForStable(path, stableInterval, time.Until(deadline)); err != nil {
+			logger.Error("Not copying %v (waited %s)", err, waitStable)
+			os.Exit(1)
+		}
+	}
+}
+
+// findResultLimit caps how many Spotlight matches are shown in the picker
+const findResultLimit = 200
+
 func handleFindMode(query string) {
 	logger.Debug("Searching for files matching: %s", query)
 
+	attributes, err := parseAttrFlags(attrFlags)
+	if err != nil {
+		logger.Error("%v", err)
+		os.Exit(common.ExitUsage)
+	}
+	if len(attributes) > 0 {
+		logger.Debug("Filtering by attributes: %v", attributes)
+	}
+
 	// Core business logic: search with metadata
-	// Spotlight doesn't have reliable sorting, so we get results and sort in Go
-	// Limitation: for very broad queries (.pdf), might not get newest files
-	results, err := spotlight.SearchWithMetadata(spotlight.SearchOptions{
-		Query:      query,
-		MaxResults: 1000, // Reasonable limit - sorted by date after fetch
-	})
+	// Spotlight streams matches newest first, so the picker only needs the top
+	// files and metadata is never fetched for the rest
+	modifiedSince, err := parseSinceFlag(sinceFlag)
+	if err != nil {
+		logger.Error("%v", err)
+		os.Exit(common.ExitUsage)
+	}
+
+	searchOpts := spotlight.SearchOptions{
+		Query:         query,
+		MaxResults:    findResultLimit,
+		Attributes:    attributes,
+		ModifiedSince: modifiedSince,
+		CaseSensitive: caseSensitive,
+	}
 
+	// search runs either the Spotlight query or a filesystem walk; the picker's
+	// refresh reuses it
+	walkFolders := func() ([]recent.FileInfo, error) {
+		return findByWalking(query, modifiedSince, selectedSearchDirs())
+	}
+	var search func() ([]recent.FileInfo, error)
+	if noSpotlight {
+		if len(attributes) > 0 {
+			logger.Error("--attr filters need Spotlight and can't be used with --no-spotlight")
+			os.Exit(common.ExitUsage)
+		}
+		search = walkFolders
+	} else {
+		search = func() ([]recent.FileInfo, error) {
+			stop := logger.Timer("Spotlight query")
+			files, err := spotlight.SearchWithMetadata(searchOpts)
+			stop()
+			if err != nil {
+				return nil, err
+			}
+			return spotlightToRecent(files), nil
+		}
+	}
+
+	files, err := search()
 	if err != nil {
-		logger.Error("Spotlight search failed: %v", err)
+		if noSpotlight {
+			logger.Error("File search failed: %v", err)
+		} else {
+			logger.Error("Spotlight search failed: %v", err)
+		}
 		os.Exit(1)
 	}
 
-	if len(results) == 0 {
-		logger.Error("No files found matching '%s'", query)
-		os.Exit(1)
+	// Spotlight doesn't index everything (network mounts, excluded folders), so
+	// when it finds nothing walk the search folders instead. Attribute filters
+	// can't be checked without Spotlight, so those searches don't fall back.
+	walked := noSpotlight
+	if len(files) == 0 && !noSpotlight && query != "" && len(attributes) == 0 {
+		logger.Verbose("Spotlight found nothing, searching folders directly...")
+		search = walkFolders
+		walked = true
+		files, err = search()
+		if err != nil {
+			logger.Error("File search failed: %v", err)
+			os.Exit(1)
+		}
+	}
+
+	if len(files) == 0 {
+		if !noSpotlight && spotlightDisabled() {
+			if walked {
+				logger.Error("No files found in the search folders, and Spotlight indexing is disabled so nothing else was searched. Enable it with 'sudo mdutil -i on /' to search everywhere")
+			} else {
+				logger.Error("No files found, but Spotlight indexing is disabled. Enable it with 'sudo mdutil -i on /' or search without Spotlight using --no-spotlight")
+			}
+		} else if query == "" {
+			logger.Error("No files found matching attributes %v", attributes)
+		} else {
+			logger.Error("No files found matching '%s'", query)
+		}
+		os.Exit(common.ExitNoFiles)
 	}
 
-	logger.Debug("Found %d files", len(results))
+	logger.Debug("Found %d files", len(files))
 
 	// Debug: show first few results with dates
-	if debug && len(results) > 0 {
+	if debug && len(files) > 0 {
 		logger.Debug("First 10 results (sorted by date, newest first):")
 		limit := 10
-		if len(results) < limit {
-			limit = len(results)
+		if len(files) < limit {
+			limit = len(files)
 		}
 		for i := 0; i < limit; i++ {
-			logger.Debug("  [%d] %s (%s)", i+1, results[i].Name, results[i].Modified.Format("2006-01-02 15:04:05"))
+			logger.Debug("  [%d] %s (%s)", i+1, files[i].Name, files[i].Modified.Format("2006-01-02 15:04:05"))
 		}
 	}
 
-	// Convert spotlight.FileInfo to recent.FileInfo for picker compatibility
-	var files []recent.FileInfo
-	for _, r := range results {
-		files = append(files, recent.FileInfo{
-			Path:     r.Path,
-			Name:     r.Name,
-			Size:     r.Size,
-			Modified: r.Modified,
-			IsDir:    r.IsDir,
-		})
-	}
-
-	// Show picker with results
-	// Create refresh function that re-runs the spotlight search
+	// Show picker with files
+	// Refreshing re-runs the same search
 	refreshFunc := func() ([]recent.FileInfo, error) {
-		newResults, err := spotlight.SearchWithMetadata(spotlight.SearchOptions{
-			Query:      query,
-			MaxResults: 1000,
-		})
+		newResults, err := search()
 		if err != nil {
 			return files, err
 		}
-		var newFiles []recent.FileInfo
-		for _, r := range newResults {
-			newFiles = append(newFiles, recent.FileInfo{
-				Path:     r.Path,
-				Name:     r.Name,
-				Size:     r.Size,
-				Modified: r.Modified,
-				IsDir:    r.IsDir,
-			})
-		}
-		return newFiles, nil
+		return newResults, nil
 	}
 
 	// Spotlight doesn't watch specific directories, pass nil for watchDirs
@@ -499,44 +1281,168 @@ func handleFindMode(query string) {
 	}
 }
 
-// Load configuration from ~/.clippy.conf
-func loadConfig() {
-	homeDir, err := os.UserHomeDir()
+// handleScreenshotMode handles the --screenshot flag
+func handleScreenshotMode(arg string) {
+	count, maxAge, err := recent.ParseRecentArgument(arg)
 	if err != nil {
-		return
+		logger.Error("%v", err)
+		os.Exit(common.ExitUsage)
+	}
+
+	search := func() ([]recent.FileInfo, error) {
+		results, err := spotlight.SearchScreenshots(findResultLimit)
+		if err != nil {
+			return nil, err
+		}
+		files := spotlightToRecent(results)
+		if maxAge > 0 {
+			var withinAge []recent.FileInfo
+			for _, file := range files {
+				if file.Age() <= maxAge {
+					withinAge = append(withinAge, file)
+				}
+			}
+			files = withinAge
+		}
+		if count > 0 && len(files) > count {
+			files = files[:count]
+		}
+		return files, nil
 	}
 
-	configPath := filepath.Join(homeDir, ".clippy.conf")
-	file, err := os.Open(configPath)
+	files, err := search()
 	if err != nil {
-		return // No config file is fine
+		logger.Error("Spotlight search failed: %v", err)
+		os.Exit(1)
 	}
-	defer func() {
-		if err := file.Close(); err != nil && verbose {
-			fmt.Fprintf(os.Stderr, "Warning: failed to close config file: %v\n", err)
+
+	if len(files) == 0 {
+		logger.Error("No screenshots found")
+		os.Exit(common.ExitNoFiles)
+	}
+
+	if len(files) == 1 {
+		logger.Verbose("Copying most recent screenshot: %s (modified %s ago)", files[0].Path, files[0].Age().Round(time.Second))
+		handleFileMode(files[0].Path)
+		return
+	}
+
+	// Several screenshots match - let the user pick
+	pickerResult, err := showBubbleTeaPickerWithResult(files, absoluteTime, search, nil)
+	if err != nil {
+		if err.Error() == "cancelled" {
+			fmt.Println("Cancelled.")
+			os.Exit(0)
 		}
-	}()
+		logger.Error("Picker error: %v", err)
+		os.Exit(1)
+	}
 
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue
+	if len(pickerResult.Files) == 0 {
+		logger.Error("No files selected")
+		os.Exit(1)
+	}
+
+	// Override paste flag if user pressed 'p' in picker
+	if pickerResult.PasteMode {
+		paste = true
+	}
+
+	if len(pickerResult.Files) == 1 {
+		logger.Verbose("Selected: %s", pickerResult.Files[0].Path)
+		handleFileMode(pickerResult.Files[0].Path)
+	} else {
+		logger.Verbose("Selected %d files:", len(pickerResult.Files))
+		var paths []string
+		for _, file := range pickerResult.Files {
+			logger.Verbose("  - %s", file.Path)
+			paths = append(paths, file.Path)
 		}
+		handleMultipleFiles(paths)
+	}
+}
+
+// parseSinceFlag converts --since into the earliest modification time to search.
+// "all" or "0" disables the date filter and returns the zero time.
+func parseSinceFlag(since string) (time.Time, error) {
+	since = strings.TrimSpace(since)
+	if since == "all" || since == "0" {
+		return time.Time{}, nil
+	}
+	window, err := recent.ParseDuration(since)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --since %q: %w", since, err)
+	}
+	return time.Now().Add(-window), nil
+}
 
-		parts := strings.SplitN(line, "=", 2)
-		if len(parts) != 2 {
-			continue
+// parseAttrFlags parses repeated --attr name=value flags into Spotlight attribute filters
+func parseAttrFlags(flags []string) (map[string]string, error) {
+	if len(flags) == 0 {
+		return nil, nil
+	}
+	attributes := make(map[string]string, len(flags))
+	for _, flag := range flags {
+		name, value, ok := strings.Cut(flag, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid --attr %q: use name=value, e.g. kMDItemIsScreenCapture=1", flag)
 		}
+		attributes[name] = strings.TrimSpace(value)
+	}
+	return attributes, nil
+}
+
+// spotlightToRecent converts Spotlight results to recent.FileInfo for the picker
+func spotlightToRecent(results []spotlight.FileInfo) []recent.FileInfo {
+	var files []recent.FileInfo
+	for _, r := range results {
+		files = append(files, recent.FileInfo{
+			Path:     r.Path,
+			Name:     r.Name,
+			Size:     r.Size,
+			Modified: r.Modified,
+			IsDir:    r.IsDir,
+		})
+	}
+	return files
+}
+
+// configSettings holds every key = value pair read from the config file
+var configSettings = map[string]string{}
+
+// Load configuration from ~/.clippy.conf
+func loadConfig(cmd *cobra.Command) {
+	settings, err := common.ReadConfig(common.ConfigFilePath())
+	if err != nil {
+		return // No config file is fine
+	}
 
-		key := strings.TrimSpace(parts[0])
-		value := strings.TrimSpace(parts[1])
+	keySettings := map[string]string{}
+	for key, value := range settings {
+		configSettings[key] = value
 
 		switch key {
 		case "verbose":
 			if value == "true" || value == "1" {
 				verbose = true
 			}
+		case "quiet":
+			if value == "true" || value == "1" {
+				quiet = true
+			}
+		case "notify":
+			if value == "true" || value == "1" {
+				notifyFlag = true
+			}
+		case "smart_flavor":
+			if value == "true" || value == "1" {
+				smartFlavor = true
+			}
+		case "bell":
+			if value == "true" || value == "1" {
+				bellFlag = true
+			}
 		case "cleanup":
 			if value == "false" || value == "0" {
 				cleanup = false
@@ -549,8 +1455,49 @@ func loadConfig() {
 			}
 		case "default_folders":
 			defaultFolders = strings.Split(value, ",")
+		case "text_extensions":
+			textExtensions = strings.Split(value, ",")
+		case "reference_extensions":
+			refExtensions = strings.Split(value, ",")
+		case "pasteboard":
+			// An explicit --pasteboard beats the configured default
+			if !cmd.Flags().Changed("pasteboard") {
+				pasteboardName = value
+			}
+		case "post_copy_hook":
+			postCopyHook = value
+		case "post_paste_hook":
+			postPasteHook = value
+		case "confirm_threshold":
+			if n, err := strconv.Atoi(value); err == nil {
+				confirmLimit = n
+			}
+		case "max_files":
+			if n, err := strconv.Atoi(value); err == nil && n >= 0 {
+				maxFilesLimit = n
+			}
+		case "picker_paths":
+			if slices.Contains(pathModes, value) {
+				pickerPathMode = value
+			}
+		case "paste_confirm_size":
+			if n, err := common.ParseSize(value); err == nil {
+				pasteSizeLimit = n
+			}
+		case "url_timeout":
+			if d, err := time.ParseDuration(value); err == nil && d > 0 && !cmd.Flags().Changed("url-timeout") {
+				urlTimeout = d
+			}
+		default:
+			if action, ok := strings.CutPrefix(key, "picker_keys."); ok {
+				keySettings[action] = value
+			}
 		}
 	}
+	// Bad bindings are reported when the picker opens, not on every copy
+	pickerKeys, pickerKeysErr = parsePickerKeys(keySettings)
+	common.UseClipboardLock(settings)
+	common.UseTypeOverrides(settings)
 }
 
 // Logic for when a filename is provided as an argument
@@ -561,35 +1508,96 @@ func handleFileMode(filePath string) {
 	if mimeType != "" && textMode {
 		logger.Debug("Using manual MIME type: %s", mimeType)
 		// Core handles file I/O - interface just passes path and type
-		err := clippy.CopyFileAsTextWithType(filePath, mimeType)
+		stop := logger.Timer("Clipboard write")
+		err := clippy.CopyFileAsTextWithOptions(filePath, clippy.CopyOptions{MimeType: mimeType, Wrap: wrapTemplate})
+		stop()
 		if err != nil {
 			logger.Error("Could not copy file with MIME type %s: %v", mimeType, err)
-			os.Exit(1)
+			os.Exit(exitCode(err))
 		}
 
-		logger.Verbose("✅ Copied text content from '%s' as %s", filepath.Base(filePath), mimeType)
+		reportSuccess("✅ Copied text content from '%s' as %s", filepath.Base(filePath), mimeType)
 		logger.Debug("Manual MIME type: %s", mimeType)
+		runPostCopyHook("text", filePath)
+	} else if withThumbnail {
+		stop := logger.Timer("Clipboard write")
+		err := clippy.CopyFileWithThumbnail(filePath)
+		stop()
+		if err != nil {
+			logger.Error("Could not copy file %s: %v", filePath, err)
+			os.Exit(exitCode(err))
+		}
+		reportSuccess("✅ Copied file reference for '%s' with a thumbnail", filepath.Base(filePath))
+		runPostCopyHook("files", filePath)
+	} else if asName != "" {
+		stop := logger.Timer("Clipboard write")
+		alias, err := clippy.CopyFileAs(filePath, asName, tempDir, copyOptions())
+		stop()
+		if err != nil {
+			logger.Error("Could not copy file %s: %v", filePath, err)
+			os.Exit(exitCode(err))
+		}
+		if alias == "" {
+			// Dry run: nothing was created
+			reportSuccess("✅ Copied file reference for '%s' as '%s'", filepath.Base(filePath), clippy.SanitizeFileName(asName))
+		} else {
+			reportSuccess("✅ Copied file reference for '%s' as '%s'", filepath.Base(filePath), filepath.Base(alias))
+			runPostCopyHook("files", alias)
+			// --paste pastes what's on the clipboard, under the new name
+			filePath = alias
+		}
+	} else if noClear || skipIfSame {
+		stop := logger.Timer("Clipboard write")
+		err := clippy.CopyFileWithOptions(filePath, copyOptions())
+		stop()
+		switch {
+		case errors.Is(err, clippy.ErrAlreadyOnClipboard):
+			reportSuccess("✅ '%s' is already on the clipboard", filepath.Base(filePath))
+		case err != nil:
+			logger.Error("Could not copy file %s: %v", filePath, err)
+			os.Exit(exitCode(err))
+		case noClear:
+			reportSuccess("✅ Added file reference for '%s' to the clipboard", filepath.Base(filePath))
+			runPostCopyHook("files", filePath)
+		default:
+			reportSuccess("✅ Copied file reference for '%s'", filepath.Base(filePath))
+			runPostCopyHook("files", filePath)
+		}
 	} else {
 		// Use auto-detection as before
-		logger.Debug("Calling clippy.CopyWithResultAndMode for: %s (textMode=%v)", filePath, textMode)
-		result, err := clippy.CopyWithResultAndMode(filePath, textMode)
+		logger.Debug("Calling clippy.CopyWithResultAndOptions for: %s (textMode=%v)", filePath, textMode)
+		stop := logger.Timer("Clipboard write")
+		result, err := clippy.CopyWithResultAndOptions(filePath, textMode, clippy.CopyOptions{Wrap: wrapTemplate, Stats: verbose || debug, TextExtensions: textExtensions, ReferenceExtensions: refExtensions})
+		stop()
 		if err != nil {
 			logger.Error("Could not copy file %s: %v", filePath, err)
-			os.Exit(1)
+			os.Exit(exitCode(err))
 		}
-		logger.Debug("clippy.CopyWithResultAndMode returned successfully")
+		logger.Debug("clippy.CopyWithResultAndOptions returned successfully")
 
 		// Show user-friendly verbose output
-		if result.AsText {
-			logger.Verbose("✅ Copied text content from '%s'", filepath.Base(filePath))
+		if result.AsText && result.Stats != nil {
+			reportSuccess("✅ Copied text content from '%s' (%s)", filepath.Base(filePath), formatTextStats(*result.Stats))
+			runPostCopyHook("text", filePath)
+		} else if result.AsText {
+			reportSuccess("✅ Copied text content from '%s'", filepath.Base(filePath))
+			runPostCopyHook("text", filePath)
 		} else {
-			logger.Verbose("✅ Copied file reference for '%s'", filepath.Base(filePath))
+			reportSuccess("✅ Copied file reference for '%s'", filepath.Base(filePath))
+			runPostCopyHook("files", filePath)
 		}
 
-		// Show technical details in debug mode
-		logger.Debug("Detection method: %s, Type: %s, AsText: %v", result.Method, result.Type, result.AsText)
+		// Show technical details in debug mode, or always when previewing
+		if dryRun {
+			logger.Verbose("[dry-run] detected %s via %s (as text: %v)", result.Type, result.Method, result.AsText)
+		} else {
+			logger.Debug("Detection method: %s, Type: %s, AsText: %v", result.Method, result.Type, result.AsText)
+		}
 	}
 
+	revealFiles([]string{filePath})
+	openFiles([]string{filePath})
+
 	// Handle paste flag
 	logger.Debug("Paste flag is: %v", paste)
 	pasteFiles([]string{filePath})
@@ -598,31 +1606,95 @@ func handleFileMode(filePath string) {
 // Handle multiple files at once
 func handleMultipleFiles(paths []string) {
 	logger.Debug("handleMultipleFiles called with %d paths", len(paths))
+	if asName != "" {
+		logger.Error("--as renames a single file, but %d files were given", len(paths))
+		os.Exit(common.ExitUsage)
+	}
+	if withThumbnail {
+		logger.Error("--with-thumbnail copies a single image, but %d files were given", len(paths))
+		os.Exit(common.ExitUsage)
+	}
 	for i, path := range paths {
 		logger.Debug("  Path[%d]: %s", i, path)
 	}
 
+	// Drop duplicates up front so the count, output and --paste match what's copied
+	unique, err := clippy.UniquePaths(paths)
+	if err != nil {
+		logger.Error("Could not copy files: %v", err)
+		os.Exit(exitCode(err))
+	}
+	if duplicates := len(paths) - len(unique); duplicates > 0 {
+		logger.Verbose("Skipped %d duplicate path(s)", duplicates)
+	}
+	paths = unique
+
 	// Use the library function for multiple file copying
-	logger.Debug("Calling clippy.CopyMultiple")
-	err := clippy.CopyMultiple(paths)
+	logger.Debug("Calling clippy.CopyMultipleWithOptions (merge=%v, skipIfSame=%v)", noClear, skipIfSame)
+	stop := logger.Timer("Clipboard write")
+	err = clippy.CopyMultipleWithOptions(paths, copyOptions())
+	stop()
+	if errors.Is(err, clippy.ErrAlreadyOnClipboard) {
+		reportSuccess("✅ These %d files are already on the clipboard", len(paths))
+		revealFiles(paths)
+		openFiles(paths)
+		pasteFiles(paths)
+		return
+	}
 	if err != nil {
 		logger.Error("Could not copy files: %v", err)
-		os.Exit(1)
+		os.Exit(exitCode(err))
 	}
-	logger.Debug("clippy.CopyMultiple returned successfully")
+	logger.Debug("clippy.CopyMultipleWithOptions returned successfully")
 
-	logger.Verbose("✅ Copied %d file references", len(paths))
+	// Success output is verbose-only, so files are only stat'ed for the total
+	// then; --notify and --bell still need reportSuccess either way
 	if verbose {
+		reportSuccess("✅ Copied %d file references (total %s)", len(paths), formatSize(common.TotalSize(paths)))
 		for _, path := range paths {
 			fmt.Printf("  - %s\n", filepath.Base(path))
 		}
+	} else {
+		reportSuccess("✅ Copied %d file references", len(paths))
 	}
+	runPostCopyHook("files", paths...)
+	revealFiles(paths)
+	openFiles(paths)
 
 	// Handle paste flag
 	logger.Debug("Paste flag is: %v", paste)
 	pasteFiles(paths)
 }
 
+// runPostCopyHook reports copied files (or a file copied as text) to the
+// post_copy_hook. Hooks never run in dry-run mode.
+func runPostCopyHook(copyType string, files ...string) {
+	if dryRun {
+		return
+	}
+	paths := make([]string, len(files))
+	for i, file := range files {
+		paths[i] = file
+		if abs, err := filepath.Abs(file); err == nil {
+			paths[i] = abs
+		}
+	}
+	common.RunHook(postCopyHook, common.HookEvent{Type: copyType, Files: paths, Bytes: common.TotalSize(paths)}, logger)
+}
+
+// runDataHook reports piped or fetched content to the post_copy_hook
+func runDataHook(copyType string, size int64) {
+	if dryRun {
+		return
+	}
+	common.RunHook(postCopyHook, common.HookEvent{Type: copyType, Bytes: size}, logger)
+}
+
+// copyOptions builds the library copy options from the command-line flags
+func copyOptions() clippy.CopyOptions {
+	return clippy.CopyOptions{Merge: noClear, SkipIfSame: skipIfSame, Name: nameFlag, Wrap: wrapTemplate}
+}
+
 // Logic for when data is piped via stdin
 func handleStreamMode() {
 	// Check if stdin has data
@@ -637,38 +1709,223 @@ func handleStreamMode() {
 		}
 
 		// Check if input is empty
-		if buf.Len() == 0 {
+		if buf.Len() == 0 && noClear {
+			reportSuccess("✅ Nothing to add (empty input), clipboard unchanged")
+		} else if buf.Len() == 0 {
 			// Empty input - clear clipboard
 			if err := clearClipboard(); err != nil {
 				logger.Error("Failed to clear clipboard: %v", err)
-				os.Exit(1)
+				os.Exit(exitCode(err))
 			}
-			logger.Verbose("✅ Clipboard cleared (empty input)")
+			reportSuccess("✅ Clipboard cleared (empty input)")
 		} else {
-			// Non-empty input - copy to clipboard
+			// Non-empty input - copy to clipboard, as the --mime type if one was given
+			opts := copyOptions()
+			opts.MimeType = mimeType
+			opts.AsFile = binaryFlag
 			if mimeType != "" {
-				// Manual MIME type specified
 				logger.Debug("Using manual MIME type for stream: %s", mimeType)
-				err := clippy.CopyTextWithType(buf.String(), mimeType)
-				if err != nil {
+			}
+			stop := logger.Timer("Clipboard write")
+			data := buf.Bytes()
+			err := clippy.CopyDataWithOptions(bytes.NewReader(data), tempDir, opts)
+			stop()
+			if errors.Is(err, clippy.ErrAlreadyOnClipboard) {
+				reportSuccess("✅ Content is already on the clipboard")
+				return
+			}
+			if err != nil {
+				if mimeType != "" {
 					logger.Error("Could not copy with MIME type %s: %v", mimeType, err)
-					os.Exit(1)
-				}
-				logger.Verbose("✅ Copied content from stream as %s", mimeType)
-			} else {
-				// Auto-detection
-				err := clippy.CopyDataWithTempDir(&buf, tempDir)
-				if err != nil {
+				} else {
 					logger.Error("Could not copy from stdin: %v", err)
-					os.Exit(1)
 				}
-				logger.Verbose("✅ Copied content from stream using smart detection")
+				os.Exit(exitCode(err))
 			}
+			summary := ""
+			if verbose || debug {
+				summary = " (" + describeData(data) + ")"
+			}
+			if binaryFlag {
+				reportSuccess("✅ Copied content from stream as a file reference%s", summary)
+			} else if mimeType != "" {
+				reportSuccess("✅ Copied content from stream as %s%s", mimeType, summary)
+			} else {
+				reportSuccess("✅ Copied content from stream using smart detection%s", summary)
+			}
+			runDataHook("data", int64(len(data)))
+			openStreamData(data)
 		}
 	} else {
 		// No stdin data and no arguments - show usage
 		logger.Error("No input provided. Use --help for usage information.")
-		os.Exit(1)
+		os.Exit(common.ExitUsage)
+	}
+}
+
+// Logic for --quote: the arguments (or stdin) as an attributed quote
+func handleQuote(args []string) {
+	text := strings.Join(args, " ")
+	if len(args) == 0 {
+		stat, _ := os.Stdin.Stat()
+		if (stat.Mode() & os.ModeCharDevice) != 0 {
+			logger.Error("--quote needs the text to quote, as arguments or on stdin")
+			os.Exit(common.ExitUsage)
+		}
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			logger.Error("Could not read from stdin: %v", err)
+			os.Exit(1)
+		}
+		text = string(data)
+	}
+
+	if err := clippy.CopyQuote(text, quoteSource); err != nil {
+		logger.Error("%v", err)
+		os.Exit(exitCode(err))
+	}
+	if quoteSource != "" {
+		reportSuccess("✅ Copied quote with source %s", quoteSource)
+	} else {
+		reportSuccess("✅ Copied quote")
+	}
+	runDataHook("text", int64(len(text)))
+}
+
+// Logic for --html/--rtf/--plain/--flavor: one clipboard item with a
+// representation per file
+func handleFlavorFiles() {
+	var files []clippy.FlavorFile
+	if htmlFile != "" {
+		files = append(files, clippy.FlavorFile{Type: "public.html", Path: htmlFile})
+	}
+	if rtfFile != "" {
+		files = append(files, clippy.FlavorFile{Type: "public.rtf", Path: rtfFile})
+	}
+	if plainFile != "" {
+		files = append(files, clippy.FlavorFile{Type: "text/plain", Path: plainFile})
+	}
+	for _, flavor := range flavorFiles {
+		typeIdentifier, path, ok := strings.Cut(flavor, "=")
+		if !ok || typeIdentifier == "" || path == "" {
+			logger.Error("Invalid --flavor %q: use TYPE=FILE, e.g. public.html=body.html", flavor)
+			os.Exit(common.ExitUsage)
+		}
+		files = append(files, clippy.FlavorFile{Type: typeIdentifier, Path: path})
+	}
+
+	stop := logger.Timer("Clipboard write")
+	err := clippy.CopyFlavorFiles(files)
+	stop()
+	if err != nil {
+		logger.Error("Could not copy flavors: %v", err)
+		os.Exit(exitCode(err))
+	}
+
+	paths := make([]string, len(files))
+	for i, file := range files {
+		paths[i] = file.Path
+	}
+	reportSuccess("✅ Copied %d flavors from %s", len(files), strings.Join(paths, ", "))
+	runPostCopyHook("text", paths...)
+}
+
+// Logic for raw pixels piped with --stdin-image WxH
+func handleStdinImage(size string) {
+	width, height, err := parseImageSize(size)
+	if err != nil {
+		logger.Error("%v", err)
+		os.Exit(common.ExitUsage)
+	}
+
+	stop := logger.Timer("Clipboard write")
+	err = clippy.CopyRawImage(os.Stdin, width, height)
+	stop()
+	if err != nil {
+		logger.Error("Could not copy image from stdin: %v", err)
+		os.Exit(exitCode(err))
+	}
+	reportSuccess("✅ Copied %dx%d image from stdin as PNG", width, height)
+	runDataHook("image", int64(width)*int64(height)*4)
+}
+
+// parseImageSize parses a "WxH" size such as 1920x1080
+func parseImageSize(size string) (int, int, error) {
+	w, h, ok := strings.Cut(strings.ToLower(size), "x")
+	width, errW := strconv.Atoi(w)
+	height, errH := strconv.Atoi(h)
+	if !ok || errW != nil || errH != nil || width <= 0 || height <= 0 {
+		return 0, 0, fmt.Errorf("invalid image size %q: use WxH, e.g. 640x480", size)
+	}
+	return width, height, nil
+}
+
+// Logic for when a URL is provided with --url
+func handleURLMode(rawURL string) {
+	logger.Debug("Fetching URL: %s (timeout=%v)", rawURL, urlTimeout)
+
+	size, err := clippy.CopyURLWithOptions(rawURL, clippy.URLOptions{
+		Timeout:  urlTimeout,
+		TempDir:  tempDir,
+		MimeType: mimeType,
+	})
+	if err != nil {
+		logger.Error("Could not copy from URL: %v", err)
+		os.Exit(exitCode(err))
+	}
+
+	reportSuccess("✅ Copied content from %s", rawURL)
+	runDataHook("data", size)
+}
+
+// formatTextStats renders line, word and byte counts, e.g. "42 lines, 310 words, 2.1 KB"
+func formatTextStats(stats clippy.TextStats) string {
+	return fmt.Sprintf("%s, %s, %s", plural(stats.Lines, "line"), plural(stats.Words, "word"), formatSize(int64(stats.Bytes)))
+}
+
+// describeData summarizes piped input: text stats for text, just the size otherwise
+func describeData(data []byte) string {
+	if utf8.Valid(data) && !bytes.ContainsRune(data, 0) {
+		return formatTextStats(clippy.CountText(string(data)))
+	}
+	return formatSize(int64(len(data)))
+}
+
+// plural formats a count with its noun, adding "s" unless the count is 1
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// reportSuccess prints a verbose success message, marking it as simulated in dry-run mode.
+// With --notify the message is also posted as a notification banner, and --bell
+// plays the success sound.
+func reportSuccess(format string, args ...interface{}) {
+	if dryRun {
+		format = "[dry-run] " + strings.TrimPrefix(format, "✅ ") + " (not actually performed)"
+	} else {
+		if notifyFlag {
+			if err := notify.Post("clippy", fmt.Sprintf(strings.TrimPrefix(format, "✅ "), args...)); err != nil {
+				logger.Debug("%v", err)
+			}
+		}
+		if bellFlag {
+			successBell()
+		}
+	}
+	logger.Verbose(format, args...)
+}
+
+// successBell sounds once per run, however many steps (copy, then paste) report success
+var successBell = ringBell(true)
+
+// ringBell returns a function that plays the success or error sound the first time it's called
+func ringBell(success bool) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() { notify.Beep(success) })
 	}
 }
 
@@ -678,20 +1935,147 @@ func cleanupOldTempFiles() {
 	clippy.CleanupTempFiles(tempDir, verbose)
 }
 
+// revealFiles selects the copied files in Finder if --reveal is set. A failure
+// only warns, since the files are already on the clipboard.
+func revealFiles(files []string) {
+	if !reveal {
+		return
+	}
+
+	if dryRun {
+		logger.Verbose("[dry-run] would reveal %d files in Finder", len(files))
+		return
+	}
+
+	if err := workspace.Reveal(files); err != nil {
+		logger.Warn("could not reveal files in Finder: %v", err)
+	}
+}
+
+// openFiles opens the copied files with their default apps if --open is set.
+// Like --reveal, a failure only warns.
+func openFiles(files []string) {
+	if !openFlag {
+		return
+	}
+
+	if dryRun {
+		logger.Verbose("[dry-run] would open %d files with their default apps", len(files))
+		return
+	}
+
+	if err := workspace.Open(files); err != nil {
+		logger.Warn("could not open files: %v", err)
+	}
+}
+
+// openStreamData handles --open for piped input. Data that was saved to a temp
+// file opens that file; text is written to a clippy-*.txt temp file so it opens
+// in the default text editor.
+func openStreamData(data []byte) {
+	if !openFlag {
+		return
+	}
+
+	if dryRun {
+		logger.Verbose("[dry-run] would open the piped content with its default app")
+		return
+	}
+
+	// With --no-clear the clipboard may still hold older files, so only a fresh
+	// copy is trusted to be the piped data
+	if files := clippy.GetFiles(); len(files) > 0 && !noClear {
+		openFiles(files)
+		return
+	}
+
+	f, err := os.CreateTemp(tempDir, "clippy-*.txt")
+	if err != nil {
+		logger.Warn("could not create a temp file to open: %v", err)
+		return
+	}
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		logger.Warn("could not write a temp file to open: %v", err)
+		return
+	}
+	openFiles([]string{f.Name()})
+}
+
 // pasteFiles handles pasting files to current directory if --paste flag is set
 func pasteFiles(files []string) {
 	if !paste {
 		return
 	}
 
+	if dryRun {
+		for _, file := range files {
+			logger.Verbose("[dry-run] would paste %s to current directory", file)
+		}
+		return
+	}
+
+	// The post-copy hook is already running, and os.Exit skips main's deferred wait
+	if !confirmPasteSize(pasteSize(files)) {
+		fmt.Println("Cancelled; the clipboard was still updated.")
+		common.WaitForHooks()
+		os.Exit(0)
+	}
+
+	failed := 0
 	for _, file := range files {
-		err := recent.CopyFileToDestination(file, ".")
-		if err != nil {
+		if err := recent.CopyFileToDestination(file, "."); err != nil {
 			logger.Error("Failed to paste file %s: %v", filepath.Base(file), err)
-			continue
+			failed++
 		}
 	}
-	logger.Verbose("✅ Also pasted %d files to current directory", len(files))
+	if failed > 0 {
+		common.WaitForHooks()
+		os.Exit(1)
+	}
+	reportSuccess("✅ Also pasted %d files to current directory", len(files))
+	pasted := make([]string, len(files))
+	for i, file := range files {
+		pasted[i], _ = filepath.Abs(filepath.Base(file))
+	}
+	common.RunHook(postPasteHook, common.HookEvent{Type: "files", Files: pasted, Bytes: common.TotalSize(pasted)}, logger)
+}
+
+// defaultPasteConfirmSize is how many bytes --paste copies before asking for confirmation
+const defaultPasteConfirmSize int64 = 1 << 30
+
+// confirmPasteSize warns before --paste copies more than pasteSizeLimit bytes
+// and, in a terminal, asks whether to go ahead. --yes, a limit of 0 and
+// scripted use proceed after the warning.
+func confirmPasteSize(size int64) bool {
+	if pasteSizeLimit <= 0 || size <= pasteSizeLimit {
+		return true
+	}
+	logger.Warn("--paste will copy %s into the current directory", formatSize(size))
+	if assumeYes || !common.IsInteractive() {
+		return true
+	}
+	return common.Confirm(fmt.Sprintf("Paste %s here?", formatSize(size)))
+}
+
+// pasteSize returns the total bytes --paste would copy, including the contents of folders
+func pasteSize(files []string) int64 {
+	var total int64
+	for _, file := range files {
+		_ = filepath.WalkDir(file, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			if info, err := d.Info(); err == nil && info.Mode().IsRegular() {
+				total += info.Size()
+			}
+			return nil
+		})
+	}
+	return total
 }
 
 // preprocessArgs converts "-r 3" to "-r=3" for better Cobra compatibility
@@ -700,7 +2084,7 @@ func preprocessArgs(args []string) []string {
 	for i := 0; i < len(args); i++ {
 		arg := args[i]
 		// Check if this is -r or -i flag
-		if (arg == "-r" || arg == "--recent" || arg == "-i" || arg == "--interactive") && i+1 < len(args) {
+		if (arg == "-r" || arg == "--recent" || arg == "-i" || arg == "--interactive" || arg == "--screenshot") && i+1 < len(args) {
 			// Check if next arg looks like a value (not another flag)
 			nextArg := args[i+1]
 			if !strings.HasPrefix(nextArg, "-") {
@@ -710,11 +2094,90 @@ func preprocessArgs(args []string) []string {
 				continue
 			}
 		}
+		// --wait-stable takes an optional timeout, so only a duration is its value
+		if arg == "--wait-stable" && i+1 < len(args) {
+			if _, err := time.ParseDuration(args[i+1]); err == nil {
+				result = append(result, arg+"="+args[i+1])
+				i++
+				continue
+			}
+		}
 		result = append(result, arg)
 	}
 	return result
 }
 
+// selectedSearchDirs returns the directories chosen with --folders or the
+// default_folders config setting, or nil to use the standard folders
+func selectedSearchDirs() []string {
+	if len(foldersFlag) > 0 {
+		dirs := mapFoldersToDirectories(foldersFlag)
+		if len(dirs) == 0 {
+			logger.Error("Invalid folder selection. Use: downloads, desktop, documents")
+			os.Exit(common.ExitUsage)
+		}
+		return dirs
+	}
+	if len(defaultFolders) > 0 {
+		// Use config defaults if no command line folders specified
+		dirs := mapFoldersToDirectories(defaultFolders)
+		logger.Debug("Using default folders from config: %v", dirs)
+		return dirs
+	}
+	return nil
+}
+
+// findByWalking searches directories for files whose names match query, without
+// Spotlight. It's slower but works where Spotlight indexing is off or excluded.
+func findByWalking(query string, modifiedSince time.Time, dirs []string) ([]recent.FileInfo, error) {
+	opts := recent.DefaultFindOptions()
+	opts.NameQuery = query
+	opts.CaseSensitive = caseSensitive
+	opts.MaxCount = findResultLimit
+	opts.MaxAge = 0
+	if !modifiedSince.IsZero() {
+		opts.MaxAge = time.Since(modifiedSince)
+	}
+	opts.IncludeHidden = includeHidden
+	opts.IncludeTemp = includeTemp
+	if len(dirs) > 0 {
+		opts.Directories = dirs
+	}
+	logger.Debug("Walking %v for files matching '%s'", opts.Directories, query)
+	return findRecentFiles(opts)
+}
+
+// findRecentFiles runs recent.FindRecentFiles, logging how long each phase took
+// in debug mode
+func findRecentFiles(opts recent.FindOptions) ([]recent.FileInfo, error) {
+	if !debug {
+		return recent.FindRecentFiles(opts)
+	}
+
+	timings := &recent.FindTimings{}
+	opts.Timings = timings
+	files, err := recent.FindRecentFiles(opts)
+	logger.Debug("Directory walk took %v (MIME detection %v for %d files)",
+		timings.Walk.Round(time.Microsecond), timings.MimeDetect.Round(time.Microsecond), timings.FilesSeen)
+	logger.Debug("Sort took %v", timings.Sort.Round(time.Microsecond))
+	return files, err
+}
+
+// spotlightDisabled reports whether Spotlight indexing is known to be off for the
+// home volume, which makes every search come back empty
+func spotlightDisabled() bool {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		homeDir = "/"
+	}
+	enabled, err := spotlight.IndexingEnabled(homeDir)
+	if err != nil {
+		logger.Debug("Could not check Spotlight indexing status: %v", err)
+		return false
+	}
+	return !enabled
+}
+
 // mapFoldersToDirectories converts folder names to actual directory paths
 func mapFoldersToDirectories(folders []string) []string {
 	homeDir, err := os.UserHomeDir()
@@ -742,25 +2205,36 @@ func getRecentDownloadsWithDirs(config recent.PickerConfig, maxFiles int, custom
 	if config.MaxAge != 0 {
 		opts.MaxAge = config.MaxAge
 	}
-	if maxFiles > 0 {
+	switch {
+	case maxFiles > 0:
 		opts.MaxCount = maxFiles
-	} else {
-		opts.MaxCount = 20 // Default to 20 if not specified
+	case maxFilesLimit > 0:
+		// No count asked for: fetch one past the --max-files ceiling so capFiles
+		// can tell the user when files were left out
+		opts.MaxCount = maxFilesLimit + 1
+	default:
+		opts.MaxCount = 0 // --max-files 0 lifts the ceiling
 	}
 
+	opts.IncludeHidden = includeHidden
+	opts.IncludeTemp = includeTemp
+
 	// Override directories if custom ones are provided
 	if len(customDirs) > 0 {
 		opts.Directories = customDirs
 	}
 
-	files, err := recent.FindRecentFiles(opts)
+	files, err := findRecentFiles(opts)
 	if err != nil {
 		return nil, err
 	}
 
 	if len(files) == 0 {
-		return nil, fmt.Errorf("no recent files found")
+		return nil, errNoRecentFiles
 	}
 
+	if sortOrder != recent.DefaultSortOrder {
+		recent.SortFiles(files, sortOrder)
+	}
 	return files, nil
 }
@@ -2,18 +2,33 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/neilberkman/clippy/pkg/clipboard"
+	"github.com/neilberkman/clippy/pkg/recent"
 )
 
 func TestMain(m *testing.M) {
-	// Clippy only works on macOS
-	if runtime.GOOS != "darwin" {
-		panic("Clippy tests require macOS (detected: " + runtime.GOOS + ")")
+	// Without the macOS pasteboard, the tests share a file-backed memory clipboard
+	clipboardDir := ""
+	if runtime.GOOS != "darwin" && os.Getenv(clipboard.BackendEnvVar) == "" {
+		dir, err := os.MkdirTemp("", "clippy-test-")
+		if err != nil {
+			panic("Failed to create clipboard dir: " + err.Error())
+		}
+		clipboardDir = dir
+		path := filepath.Join(dir, "clipboard.json")
+		_ = os.Setenv(clipboard.BackendEnvVar, "memory")
+		_ = os.Setenv(clipboard.MemoryFileEnvVar, path)
+		clipboard.SetManager(clipboard.NewFileMemoryManager(path))
 	}
 
 	// Build the binary for testing
@@ -27,6 +42,9 @@ func TestMain(m *testing.M) {
 
 	// Cleanup
 	_ = os.Remove("clippy_test")
+	if clipboardDir != "" {
+		_ = os.RemoveAll(clipboardDir)
+	}
 
 	os.Exit(code)
 }
@@ -110,6 +128,30 @@ func TestStreamMode(t *testing.T) {
 	}
 }
 
+func TestBinaryFlag(t *testing.T) {
+	csv := "id,name\n1,alice\n"
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"-v"}, "Copied content from stream using smart detection"},
+		{[]string{"-v", "--binary", "--name", "people.csv"}, "Copied content from stream as a file reference"},
+	}
+
+	for _, tt := range tests {
+		cmd := exec.Command("./clippy_test", tt.args...)
+		cmd.Env = append(os.Environ(), "CLIPPY_BACKEND=memory")
+		cmd.Stdin = strings.NewReader(csv)
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("clippy %v failed: %v\nOutput: %s", tt.args, err, output)
+		}
+		if !strings.Contains(string(output), tt.want) {
+			t.Errorf("clippy %v output = %q, want %q", tt.args, output, tt.want)
+		}
+	}
+}
+
 func TestMultipleFiles(t *testing.T) {
 	t.Run("copy multiple files", func(t *testing.T) {
 		cmd := exec.Command("./clippy_test", "--verbose", "../../test-files/minimal.png", "../../test-files/sample.txt")
@@ -175,7 +217,7 @@ func TestFlags(t *testing.T) {
 			t.Fatalf("clippy failed: %v", err)
 		}
 
-		if !strings.Contains(string(output), "✅") {
+		if !strings.Contains(string(output), "Copied") {
 			t.Errorf("Expected verbose output, got: %s", output)
 		}
 	})
@@ -294,10 +336,157 @@ verbose = true
 		t.Fatalf("clippy failed with config: %v", err)
 	}
 
-	if !strings.Contains(string(output), "✅") {
+	if !strings.Contains(string(output), "Copied") {
 		t.Errorf("Config file verbose=true not working, got: %s", output)
 	}
 
 	// Cleanup
 	_ = os.Remove(configPath)
 }
+
+func TestDryRun(t *testing.T) {
+	const sentinel = "clippy dry-run sentinel"
+	if err := clipboard.CopyText(sentinel); err != nil {
+		t.Fatalf("Failed to set clipboard: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		pipeline   string
+		wantOutput string
+	}{
+		{"file as text", `./clippy_test -v --dry-run -t ../../test-files/sample.txt`, "would copy"},
+		{"file reference", `./clippy_test -v --dry-run ../../test-files/test.pdf`, "would copy file reference"},
+		{"binary stream", `cat ../../test-files/minimal.png | ./clippy_test -v --dry-run`, "new temp file"},
+		{"clear", `./clippy_test -v --dry-run --clear`, "would clear the clipboard"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := exec.Command("bash", "-c", tt.pipeline).CombinedOutput()
+			if err != nil {
+				t.Fatalf("clippy failed: %v\nOutput: %s", err, output)
+			}
+			if !strings.Contains(string(output), tt.wantOutput) {
+				t.Errorf("Expected output to contain %q, got: %s", tt.wantOutput, output)
+			}
+
+			if got, _ := clipboard.GetText(); got != sentinel {
+				t.Errorf("Clipboard was modified in dry-run mode: got %q", got)
+			}
+		})
+	}
+}
+
+func TestPick(t *testing.T) {
+	home := t.TempDir()
+	downloads := filepath.Join(home, "Downloads")
+	if err := os.Mkdir(downloads, 0755); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	for name, age := range map[string]time.Duration{"newer.txt": time.Hour, "older.txt": 2 * time.Hour} {
+		path := filepath.Join(downloads, name)
+		if err := os.WriteFile(path, []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, now.Add(-age), now.Add(-age)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		env  string
+		want string
+	}{
+		{"flag", []string{"-i", "--pick", "2"}, "", "older.txt"},
+		{"env", []string{"-i"}, "CLIPPY_PICK=1", "newer.txt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := exec.Command("./clippy_test", append(tt.args, "--folders", "downloads", "--dry-run", "-v")...)
+			cmd.Env = append(os.Environ(), "CLIPPY_BACKEND=memory", "HOME="+home)
+			if tt.env != "" {
+				cmd.Env = append(cmd.Env, tt.env)
+			}
+			output, err := cmd.CombinedOutput()
+			if err != nil {
+				t.Fatalf("clippy failed: %v\nOutput: %s", err, output)
+			}
+			if !strings.Contains(string(output), tt.want) {
+				t.Errorf("Expected %s to be picked, got: %s", tt.want, output)
+			}
+		})
+	}
+}
+
+func TestInfoJSON(t *testing.T) {
+	cmd := exec.Command("./clippy_test", "info", "--json")
+	cmd.Env = append(os.Environ(), "CLIPPY_BACKEND=memory")
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("clippy info failed: %v", err)
+	}
+
+	var report infoReport
+	if err := json.Unmarshal(output, &report); err != nil {
+		t.Fatalf("clippy info --json output is not valid JSON: %v\n%s", err, output)
+	}
+	if report.Version == "" || report.ConfigPath == "" || len(report.SearchDirs) == 0 {
+		t.Errorf("clippy info --json is missing fields: %+v", report)
+	}
+	if report.Backend != "memory" {
+		t.Errorf("clippy info backend = %q, want %q", report.Backend, "memory")
+	}
+}
+
+func TestQuiet(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr bool
+	}{
+		{"success with verbose", []string{"--quiet", "--verbose", "../../test-files/sample.txt"}, false},
+		{"missing file", []string{"-q", "../../test-files/does-not-exist.txt"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := exec.Command("./clippy_test", tt.args...).CombinedOutput()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("clippy error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(output) != 0 {
+				t.Errorf("--quiet printed %q, want no output", output)
+			}
+		})
+	}
+}
+
+func TestRecentWindowFetchesPastMaxFiles(t *testing.T) {
+	dir := t.TempDir()
+	for i := range 5 {
+		if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("file%d.txt", i)), []byte("x"), 0644); err != nil {
+			t.Fatalf("Failed to create file: %v", err)
+		}
+	}
+	config := recent.PickerConfig{MaxAge: time.Hour}
+
+	previous := maxFilesLimit
+	defer func() { maxFilesLimit = previous }()
+
+	// One more than the ceiling comes back, so capFiles sees the overflow
+	maxFilesLimit = 3
+	files, err := getRecentDownloadsWithDirs(config, 0, []string{dir})
+	if err != nil || len(files) != 4 {
+		t.Fatalf("with --max-files 3 got %d files, %v; want 4", len(files), err)
+	}
+
+	maxFilesLimit = 0
+	files, err = getRecentDownloadsWithDirs(config, 0, []string{dir})
+	if err != nil || len(files) != 5 {
+		t.Fatalf("with --max-files 0 got %d files, %v; want all 5", len(files), err)
+	}
+}
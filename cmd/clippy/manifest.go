@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/neilberkman/clippy/internal/fsutil"
+)
+
+// writeManifest records paths in a file instead of copying them: an M3U
+// playlist if path ends in .m3u or .m3u8, otherwise one path per line. An empty
+// path or "-" writes the list to stdout.
+func writeManifest(path string, paths []string) error {
+	if path == "" || path == "-" {
+		_, err := os.Stdout.Write(formatManifest(paths, false))
+		return err
+	}
+
+	if !dryRun {
+		if err := fsutil.WriteFileAtomic(path, formatManifest(paths, isPlaylist(path)), 0644); err != nil {
+			return fmt.Errorf("could not write %s: %w", path, err)
+		}
+	}
+	reportSuccess("✅ Wrote %s to %s", plural(len(paths), "path"), path)
+	return nil
+}
+
+// isPlaylist reports whether a manifest path asks for an M3U playlist
+func isPlaylist(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".m3u" || ext == ".m3u8"
+}
+
+// formatManifest lists paths one per line. A playlist gets the extended M3U
+// header and an #EXTINF title line (the file name) before each path.
+func formatManifest(paths []string, playlist bool) []byte {
+	var buf bytes.Buffer
+	if playlist {
+		buf.WriteString("#EXTM3U\n")
+	}
+	for _, path := range paths {
+		if playlist {
+			fmt.Fprintf(&buf, "#EXTINF:-1,%s\n", filepath.Base(path))
+		}
+		buf.WriteString(path)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
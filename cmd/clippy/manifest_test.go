@@ -0,0 +1,33 @@
+package main
+
+import "testing"
+
+func TestFormatManifest(t *testing.T) {
+	paths := []string{"/Users/me/Downloads/song.mp3", "/Users/me/Downloads/clip.mov"}
+
+	want := "/Users/me/Downloads/song.mp3\n/Users/me/Downloads/clip.mov\n"
+	if got := string(formatManifest(paths, false)); got != want {
+		t.Errorf("formatManifest(list) = %q, want %q", got, want)
+	}
+
+	want = "#EXTM3U\n" +
+		"#EXTINF:-1,song.mp3\n/Users/me/Downloads/song.mp3\n" +
+		"#EXTINF:-1,clip.mov\n/Users/me/Downloads/clip.mov\n"
+	if got := string(formatManifest(paths, true)); got != want {
+		t.Errorf("formatManifest(playlist) = %q, want %q", got, want)
+	}
+}
+
+func TestIsPlaylist(t *testing.T) {
+	for path, want := range map[string]bool{
+		"out.m3u":      true,
+		"Mix.M3U8":     true,
+		"out.txt":      false,
+		"manifest":     false,
+		"m3u/list.txt": false,
+	} {
+		if got := isPlaylist(path); got != want {
+			t.Errorf("isPlaylist(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package mcp
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/neilberkman/clippy/internal/fsutil"
+)
+
+// defaultFileMode is used when buffer_paste creates a new file
+const defaultFileMode os.FileMode = 0644
+
+// detectLineEnding returns "\r\n" if most line breaks in text are CRLF, otherwise "\n"
+func detectLineEnding(text string) string {
+	crlf := strings.Count(text, "\r\n")
+	lf := strings.Count(text, "\n") - crlf
+	if crlf > lf {
+		return "\r\n"
+	}
+	return "\n"
+}
+
+// splitLines splits text into lines and reports whether it ended with a newline.
+// Both LF and CRLF endings are accepted; the returned lines never carry a "\r".
+// A final newline terminates the last line rather than starting an empty one.
+func splitLines(text string) ([]string, bool) {
+	if text == "" {
+		return []string{}, false
+	}
+	trailingNewline := strings.HasSuffix(text, "\n")
+	if trailingNewline {
+		text = strings.TrimSuffix(text, "\n")
+	}
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+	return lines, trailingNewline
+}
+
+// joinLines joins lines with the given line ending, adding a final one if requested
+func joinLines(lines []string, eol string, trailingNewline bool) string {
+	joined := strings.Join(lines, eol)
+	if trailingNewline && len(lines) > 0 {
+		joined += eol
+	}
+	return joined
+}
+
+// lineRange returns lines start through end (1-indexed, inclusive) and the range
+// as "start-end". A missing start means the first line and a missing or too large
+// end the last; with neither, all lines are returned as range "all".
+func lineRange(lines []string, start, end int) ([]string, string, error) {
+	if start <= 0 && end <= 0 {
+		return lines, "all", nil
+	}
+	if start < 1 {
+		start = 1
+	}
+	if end < 1 || end > len(lines) {
+		end = len(lines)
+	}
+	if start > end {
+		return nil, "", fmt.Errorf("start_line (%d) cannot be greater than end_line (%d)", start, end)
+	}
+	return lines[start-1 : end], fmt.Sprintf("%d-%d", start, end), nil
+}
+
+// pasteBuffer writes buffer content into the file at absPath using the given mode
+// ("append", "insert", or "replace"). An existing file keeps its trailing-newline
+// state, line endings and permissions; a new file is created with defaultFileMode
+// and the buffer's own line endings.
+func pasteBuffer(absPath string, content []byte, mode string, atLine, toLine int) error {
+	// Read target file if it exists
+	targetLines := []string{}
+	fileMode := defaultFileMode
+	eol := detectLineEnding(string(content))
+	bufferLines, trailingNewline := splitLines(string(content))
+
+	existingContent, err := readFileLimited(absPath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return fmt.Errorf("failed to read target file: %w", err)
+		}
+		// File doesn't exist, it will be created with the buffer's line ending state
+	} else {
+		eol = detectLineEnding(string(existingContent))
+		targetLines, trailingNewline = splitLines(string(existingContent))
+		if info, err := os.Stat(absPath); err == nil {
+			fileMode = info.Mode().Perm()
+		}
+	}
+
+	var newLines []string
+
+	switch mode {
+	case "append":
+		// Append buffer content to end of file
+		newLines = append(targetLines, bufferLines...)
+
+	case "insert":
+		if atLine < 1 {
+			return fmt.Errorf("at_line is required for insert mode")
+		}
+		insertAt := atLine - 1
+		if insertAt > len(targetLines) {
+			insertAt = len(targetLines)
+		}
+		// Insert buffer content at specified line
+		newLines = make([]string, 0, len(targetLines)+len(bufferLines))
+		newLines = append(newLines, targetLines[:insertAt]...)
+		newLines = append(newLines, bufferLines...)
+		newLines = append(newLines, targetLines[insertAt:]...)
+
+	case "replace":
+		if atLine < 1 || toLine < 1 {
+			return fmt.Errorf("at_line and to_line are required for replace mode")
+		}
+		replaceFrom := atLine - 1
+		replaceTo := toLine
+		if replaceFrom >= len(targetLines) {
+			return fmt.Errorf("at_line %d is beyond file length %d", atLine, len(targetLines))
+		}
+		if replaceTo > len(targetLines) {
+			replaceTo = len(targetLines)
+		}
+		// Replace lines [from, to] with buffer content
+		newLines = make([]string, 0)
+		newLines = append(newLines, targetLines[:replaceFrom]...)
+		newLines = append(newLines, bufferLines...)
+		newLines = append(newLines, targetLines[replaceTo:]...)
+
+	default:
+		return fmt.Errorf("invalid mode %q: must be 'append', 'insert', or 'replace'", mode)
+	}
+
+	// Write the new content atomically, keeping the original line endings and permissions
+	newContent := []byte(joinLines(newLines, eol, trailingNewline))
+	if err := fsutil.WriteFileAtomic(absPath, newContent, fileMode); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return nil
+}
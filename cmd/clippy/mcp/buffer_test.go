@@ -0,0 +1,164 @@
+package mcp
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTempFile(t *testing.T, name, content string, mode os.FileMode) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), mode); err != nil {
+		t.Fatalf("write temp file: %v", err)
+	}
+	if err := os.Chmod(path, mode); err != nil {
+		t.Fatalf("chmod temp file: %v", err)
+	}
+	return path
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read file: %v", err)
+	}
+	return string(data)
+}
+
+func TestPasteBufferPreservesTrailingNewline(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		buffer string
+		mode   string
+		atLine int
+		toLine int
+		want   string
+	}{
+		{"append with trailing newline", "a\nb\n", "x", "append", 0, 0, "a\nb\nx\n"},
+		{"append without trailing newline", "a\nb", "x", "append", 0, 0, "a\nb\nx"},
+		{"insert with trailing newline", "a\nb\n", "x\n", "insert", 2, 0, "a\nx\nb\n"},
+		{"insert without trailing newline", "a\nb", "x\n", "insert", 2, 0, "a\nx\nb"},
+		{"replace last line keeps newline", "a\nb\nc\n", "x", "replace", 3, 3, "a\nb\nx\n"},
+		{"replace range", "a\nb\nc\nd\n", "x\ny", "replace", 2, 3, "a\nx\ny\nd\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeTempFile(t, "target.txt", tt.target, 0o644)
+			if err := pasteBuffer(path, []byte(tt.buffer), tt.mode, tt.atLine, tt.toLine); err != nil {
+				t.Fatalf("pasteBuffer: %v", err)
+			}
+			if got := readFile(t, path); got != tt.want {
+				t.Errorf("pasteBuffer result = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPasteBufferPreservesMode(t *testing.T) {
+	path := writeTempFile(t, "script.sh", "#!/bin/sh\necho hello\n", 0o755)
+
+	if err := pasteBuffer(path, []byte("echo world"), "append", 0, 0); err != nil {
+		t.Fatalf("pasteBuffer: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	if info.Mode().Perm() != 0o755 {
+		t.Errorf("mode = %v, want %v", info.Mode().Perm(), os.FileMode(0o755))
+	}
+	if got := readFile(t, path); got != "#!/bin/sh\necho hello\necho world\n" {
+		t.Errorf("unexpected content: %q", got)
+	}
+}
+
+func TestPasteBufferNewFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "new.txt")
+
+	if err := pasteBuffer(path, []byte("line1\nline2\n"), "append", 0, 0); err != nil {
+		t.Fatalf("pasteBuffer: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	if info.Mode().Perm() != defaultFileMode {
+		t.Errorf("mode = %v, want %v", info.Mode().Perm(), defaultFileMode)
+	}
+	if got := readFile(t, path); got != "line1\nline2\n" {
+		t.Errorf("unexpected content: %q", got)
+	}
+}
+
+func TestPasteBufferPreservesCRLF(t *testing.T) {
+	fixture, err := os.ReadFile(filepath.Join("testdata", "crlf.go.txt"))
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	path := writeTempFile(t, "crlf.go", string(fixture), 0o644)
+
+	// Buffer content copied from an LF file must take on the target's CRLF endings
+	if err := pasteBuffer(path, []byte("\t// greet\n\tprintln(\"hi\")"), "insert", 4, 0); err != nil {
+		t.Fatalf("pasteBuffer: %v", err)
+	}
+
+	want := "package main\r\n\r\nfunc main() {\r\n\t// greet\r\n\tprintln(\"hi\")\r\n\tprintln(\"hello\")\r\n}\r\n"
+	if got := readFile(t, path); got != want {
+		t.Errorf("pasteBuffer result = %q, want %q", got, want)
+	}
+}
+
+func TestDetectLineEnding(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"", "\n"},
+		{"a\nb\n", "\n"},
+		{"a\r\nb\r\n", "\r\n"},
+		{"a\r\nb\r\nc\n", "\r\n"},
+		{"a\nb\nc\r\n", "\n"},
+	}
+
+	for _, tt := range tests {
+		if got := detectLineEnding(tt.text); got != tt.want {
+			t.Errorf("detectLineEnding(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestLineRange(t *testing.T) {
+	lines := []string{"a", "b", "c", "d"}
+	tests := []struct {
+		name       string
+		start, end int
+		want       []string
+		wantRange  string
+		wantErr    bool
+	}{
+		{"no range", 0, 0, lines, "all", false},
+		{"middle", 2, 3, []string{"b", "c"}, "2-3", false},
+		{"open end", 3, 0, []string{"c", "d"}, "3-4", false},
+		{"end past file", 1, 10, lines, "1-4", false},
+		{"start after end", 3, 2, nil, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotRange, err := lineRange(lines, tt.start, tt.end)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("lineRange(%d, %d) error = %v, wantErr %v", tt.start, tt.end, err, tt.wantErr)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") || gotRange != tt.wantRange {
+				t.Errorf("lineRange(%d, %d) = (%v, %q), want (%v, %q)", tt.start, tt.end, got, gotRange, tt.want, tt.wantRange)
+			}
+		})
+	}
+}
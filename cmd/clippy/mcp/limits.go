@@ -0,0 +1,51 @@
+package mcp
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/neilberkman/clippy/cmd/internal/common"
+)
+
+// MaxFileSizeEnvVar sets the largest file MCP tools will read into memory, in
+// bytes or with a KB, MB or GB suffix (e.g. "200MB")
+const MaxFileSizeEnvVar = "CLIPPY_MCP_MAX_FILE_SIZE"
+
+// defaultMaxFileSize keeps one bad path from exhausting the server's memory
+const defaultMaxFileSize int64 = 50 << 20
+
+// maxFileSize returns the read limit from MaxFileSizeEnvVar, or the default if
+// it is unset or invalid
+func maxFileSize() int64 {
+	value := os.Getenv(MaxFileSizeEnvVar)
+	if strings.TrimSpace(value) == "" {
+		return defaultMaxFileSize
+	}
+	n, err := common.ParseSize(value)
+	if err != nil || n <= 0 {
+		return defaultMaxFileSize
+	}
+	return n
+}
+
+// checkFileSize returns an error if the file is larger than the MCP read limit.
+// A missing file returns the os.Stat error, so os.IsNotExist still works.
+func checkFileSize(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if limit := maxFileSize(); info.Size() > limit {
+		return fmt.Errorf("%s is %d bytes, over the %d byte limit for MCP file reads (set %s to raise it)", path, info.Size(), limit, MaxFileSizeEnvVar)
+	}
+	return nil
+}
+
+// readFileLimited is os.ReadFile with the MCP read limit applied
+func readFileLimited(path string) ([]byte, error) {
+	if err := checkFileSize(path); err != nil {
+		return nil, err
+	}
+	return os.ReadFile(path)
+}
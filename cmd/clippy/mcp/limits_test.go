@@ -0,0 +1,42 @@
+package mcp
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMaxFileSize(t *testing.T) {
+	tests := []struct {
+		value string
+		want  int64
+	}{
+		{"", defaultMaxFileSize},
+		{"1024", 1024},
+		{"200MB", 200 << 20},
+		{"1 gb", 1 << 30},
+		{"64KB", 64 << 10},
+		{"lots", defaultMaxFileSize},
+		{"-5", defaultMaxFileSize},
+	}
+
+	for _, tt := range tests {
+		t.Setenv(MaxFileSizeEnvVar, tt.value)
+		if got := maxFileSize(); got != tt.want {
+			t.Errorf("maxFileSize() with %q = %d, want %d", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestReadFileLimited(t *testing.T) {
+	path := writeTempFile(t, "big.txt", strings.Repeat("x", 2048), 0o644)
+
+	t.Setenv(MaxFileSizeEnvVar, "1KB")
+	if _, err := readFileLimited(path); err == nil || !strings.Contains(err.Error(), MaxFileSizeEnvVar) {
+		t.Errorf("readFileLimited over the limit = %v, want an error naming %s", err, MaxFileSizeEnvVar)
+	}
+
+	t.Setenv(MaxFileSizeEnvVar, "4KB")
+	if data, err := readFileLimited(path); err != nil || len(data) != 2048 {
+		t.Errorf("readFileLimited under the limit = %d bytes, %v, want the whole file", len(data), err)
+	}
+}
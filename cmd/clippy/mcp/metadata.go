@@ -10,12 +10,40 @@ import (
 	"github.com/neilberkman/clippy"
 )
 
-// ServerOptions controls optional MCP metadata overrides.
+// ServerOptions controls the server's identity and optional MCP metadata overrides.
 type ServerOptions struct {
 	ExamplesPath   string
 	ToolsPath      string
 	PromptsPath    string
 	StrictMetadata bool
+
+	// Name is reported to clients; empty means $CLIPPY_MCP_NAME or DefaultServerName.
+	// Set it to tell several instances apart, e.g. one per project.
+	Name string
+	// Version is reported to clients, normally clippy's build version ("dev" if empty)
+	Version string
+}
+
+// DefaultServerName is the name the MCP server reports unless overridden
+const DefaultServerName = "Clippy MCP Server"
+
+// ServerNameEnvVar overrides the MCP server name when ServerOptions.Name is empty
+const ServerNameEnvVar = "CLIPPY_MCP_NAME"
+
+// serverIdentity returns the name and version to report to clients
+func serverIdentity(opts ServerOptions) (string, string) {
+	name := opts.Name
+	if name == "" {
+		name = strings.TrimSpace(os.Getenv(ServerNameEnvVar))
+	}
+	if name == "" {
+		name = DefaultServerName
+	}
+	version := opts.Version
+	if version == "" {
+		version = "dev"
+	}
+	return name, version
 }
 
 // ServerMetadata describes the MCP server's tools, prompts, and examples.
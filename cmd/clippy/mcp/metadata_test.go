@@ -137,3 +137,18 @@ func TestLoadServerMetadataStrictPromptOverrideMissingArgs(t *testing.T) {
 		t.Fatalf("expected strict metadata error")
 	}
 }
+
+func TestServerIdentity(t *testing.T) {
+	t.Setenv(ServerNameEnvVar, "")
+	if name, version := serverIdentity(ServerOptions{}); name != DefaultServerName || version != "dev" {
+		t.Errorf("serverIdentity(defaults) = %q, %q, want %q, %q", name, version, DefaultServerName, "dev")
+	}
+
+	t.Setenv(ServerNameEnvVar, "clippy-project-a")
+	if name, _ := serverIdentity(ServerOptions{}); name != "clippy-project-a" {
+		t.Errorf("serverIdentity with %s set = %q, want the env value", ServerNameEnvVar, name)
+	}
+	if name, version := serverIdentity(ServerOptions{Name: "explicit", Version: "1.2.3"}); name != "explicit" || version != "1.2.3" {
+		t.Errorf("serverIdentity(explicit) = %q, %q, want %q, %q", name, version, "explicit", "1.2.3")
+	}
+}
@@ -0,0 +1,98 @@
+package mcp
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/neilberkman/clippy"
+	"github.com/neilberkman/clippy/pkg/recent"
+)
+
+// recentResourceRefresh is how often the list of recent download resources is rebuilt
+const recentResourceRefresh = 30 * time.Second
+
+// recentResourceURI names a recent download as a resource, e.g.
+// clippy://recent/Users/me/Downloads/report.pdf
+func recentResourceURI(path string) string {
+	return (&url.URL{Scheme: "clippy", Host: "recent", Path: path}).String()
+}
+
+// recentResources lists the current recent downloads as resources. Only listed
+// files can be read, so a client can't use a crafted URI to read anything else.
+func recentResources() []server.ServerResource {
+	files, err := recent.GetRecentDownloads(recent.PickerConfig{}, defaultRecentLimit)
+	if err != nil {
+		return nil
+	}
+
+	resources := make([]server.ServerResource, 0, len(files))
+	for _, file := range files {
+		if file.IsDir {
+			continue
+		}
+		uri := recentResourceURI(file.Path)
+		resources = append(resources, server.ServerResource{
+			Resource: mcp.NewResource(uri, file.Name,
+				mcp.WithResourceDescription(fmt.Sprintf("Downloaded %s (%d bytes)", file.Modified.Format("2006-01-02 15:04:05"), file.Size)),
+				mcp.WithMIMEType(file.MimeType),
+			),
+			Handler: readRecentResource(uri, file.Path, file.MimeType),
+		})
+	}
+	return resources
+}
+
+// readRecentResource returns a handler that serves the file's content: text as
+// text, anything else base64-encoded
+func readRecentResource(uri, path, mimeType string) server.ResourceHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		data, err := readFileLimited(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		if clippy.IsTextualMimeType(mimeType) {
+			return []mcp.ResourceContents{mcp.TextResourceContents{URI: uri, MIMEType: mimeType, Text: string(data)}}, nil
+		}
+		return []mcp.ResourceContents{mcp.BlobResourceContents{URI: uri, MIMEType: mimeType, Blob: base64.StdEncoding.EncodeToString(data)}}, nil
+	}
+}
+
+// serveRecentResources publishes recent downloads as resources and keeps the list
+// current until ctx is done, replacing it (and notifying clients) only when the
+// set of files changes
+func serveRecentResources(ctx context.Context, s *server.MCPServer) {
+	var current []string
+	refresh := func() {
+		resources := recentResources()
+		uris := make([]string, len(resources))
+		for i, r := range resources {
+			uris[i] = r.Resource.URI
+		}
+		if current != nil && slices.Equal(uris, current) {
+			return
+		}
+		current = uris
+		s.SetResources(resources...)
+	}
+
+	refresh()
+	go func() {
+		ticker := time.NewTicker(recentResourceRefresh)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				refresh()
+			}
+		}
+	}()
+}
@@ -0,0 +1,46 @@
+package mcp
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestRecentResourceURI(t *testing.T) {
+	got := recentResourceURI("/Users/me/Downloads/my report.pdf")
+	if want := "clippy://recent/Users/me/Downloads/my%20report.pdf"; got != want {
+		t.Errorf("recentResourceURI = %q, want %q", got, want)
+	}
+}
+
+func TestReadRecentResource(t *testing.T) {
+	text := writeTempFile(t, "notes.txt", "hello", 0o644)
+	contents, err := readRecentResource("clippy://recent"+text, text, "text/plain")(context.Background(), mcp.ReadResourceRequest{})
+	if err != nil {
+		t.Fatalf("read text resource: %v", err)
+	}
+	if got, ok := contents[0].(mcp.TextResourceContents); !ok || got.Text != "hello" {
+		t.Errorf("text resource = %+v, want text content", contents[0])
+	}
+
+	binary := writeTempFile(t, "image.png", "\x89PNG", 0o644)
+	contents, err = readRecentResource("clippy://recent"+binary, binary, "image/png")(context.Background(), mcp.ReadResourceRequest{})
+	if err != nil {
+		t.Fatalf("read binary resource: %v", err)
+	}
+	if got, ok := contents[0].(mcp.BlobResourceContents); !ok || got.Blob != base64.StdEncoding.EncodeToString([]byte("\x89PNG")) {
+		t.Errorf("binary resource = %+v, want base64 blob", contents[0])
+	}
+}
+
+func TestReadRecentResourceHonoursMaxFileSize(t *testing.T) {
+	path := writeTempFile(t, "big.txt", strings.Repeat("x", 2048), 0o644)
+	t.Setenv(MaxFileSizeEnvVar, "1KB")
+	_, err := readRecentResource("clippy://recent"+path, path, "text/plain")(context.Background(), mcp.ReadResourceRequest{})
+	if err == nil || !strings.Contains(err.Error(), MaxFileSizeEnvVar) {
+		t.Errorf("err = %v, want the %s limit error", err, MaxFileSizeEnvVar)
+	}
+}
@@ -1,24 +1,47 @@
 package mcp
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"image"
+	"io"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"syscall"
+	"unicode/utf8"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/neilberkman/clippy"
+	"github.com/neilberkman/clippy/internal/fsutil"
+	"github.com/neilberkman/clippy/pkg/clipboard"
 	"github.com/neilberkman/clippy/pkg/recent"
 )
 
+// statusPreviewChars caps the text preview returned by clipboard_status
+const statusPreviewChars = 200
+
+// Text returned by clipboard_get is capped so a huge copy can't flood the context
+const (
+	defaultGetChars  = 20000
+	maxGetChars      = 100000
+	maxGetImageBytes = 5 << 20
+)
+
 // CopyArgs defines arguments for the copy tool
 type CopyArgs struct {
 	Text      string `json:"text,omitempty" jsonschema:"description=Text content to copy to clipboard"`
 	File      string `json:"file,omitempty" jsonschema:"description=File path to copy to clipboard"`
 	ForceText string `json:"force_text,omitempty" jsonschema:"description=Set to 'true' to force copying file content as text (only used with 'file' parameter)"`
+	ForceFile string `json:"force_file,omitempty" jsonschema:"description=Set to 'true' to copy 'text' as a file reference when it is an existing path (only used with 'text' parameter)"`
+	StartLine int    `json:"start_line,omitempty" jsonschema:"description=Copy only from this line of 'file' (1-indexed); implies text"`
+	EndLine   int    `json:"end_line,omitempty" jsonschema:"description=Copy only up to this line of 'file' (inclusive); implies text"`
+	Append    string `json:"append,omitempty" jsonschema:"description=Set to 'true' to add 'text' after the clipboard's current text on a new line (only used with 'text' parameter)"`
 }
 
 // PasteArgs defines arguments for the paste tool
@@ -35,10 +58,20 @@ type BufferCutArgs struct {
 
 // RecentDownloadsArgs defines arguments for the recent downloads tool
 type RecentDownloadsArgs struct {
-	Count    int    `json:"count,omitempty" jsonschema:"description=Number of recent files to return (default: 10)"`
-	Duration string `json:"duration,omitempty" jsonschema:"description=Time duration to look back (e.g. 5m, 1h)"`
+	Count        int    `json:"count,omitempty" jsonschema:"description=Number of recent files to return when no duration is given (default: 10)"`
+	Duration     string `json:"duration,omitempty" jsonschema:"description=Time duration to look back (e.g. 5m, 1h)"`
+	Limit        int    `json:"limit,omitempty" jsonschema:"description=Maximum number of files to return for a duration query (default: 50)"`
+	PreviewBytes int    `json:"preview_bytes,omitempty" jsonschema:"description=Include the first N bytes of each text file (max 4096)"`
 }
 
+// Caps for get_recent_downloads: a plain listing returns the newest few files,
+// a duration query everything in the window up to a larger limit
+const (
+	defaultRecentCount = 10
+	defaultRecentLimit = 50
+	maxPreviewBytes    = 4096
+)
+
 // CopyResult defines the result of a copy operation
 type CopyResult struct {
 	Success bool   `json:"success"`
@@ -60,6 +93,60 @@ type RecentFile struct {
 	Name     string `json:"name"`
 	Size     int64  `json:"size"`
 	Modified string `json:"modified"`
+
+	// Set only when get_recent_downloads is asked for previews
+	Type             string `json:"type,omitempty"`
+	Preview          string `json:"preview,omitempty"`
+	PreviewTruncated bool   `json:"preview_truncated,omitempty"`
+}
+
+// RecentDownloadsResult is the response of get_recent_downloads. TotalFound counts
+// every match, so Truncated tells the caller to ask again with a larger count or limit.
+type RecentDownloadsResult struct {
+	Files      []RecentFile `json:"files"`
+	TotalFound int          `json:"total_found" jsonschema:"description=Number of matching files before the count or limit was applied"`
+	Truncated  bool         `json:"truncated" jsonschema:"description=True if more files matched than were returned"`
+}
+
+// ClipboardSnapshot describes the current system clipboard without dumping its content
+type ClipboardSnapshot struct {
+	Empty     bool     `json:"empty"`
+	Type      string   `json:"type,omitempty" jsonschema:"description=UTI of the clipboard content"`
+	Kind      string   `json:"kind,omitempty" jsonschema:"description=text, file, or data"`
+	Size      int      `json:"size,omitempty" jsonschema:"description=Size in bytes of text or binary data"`
+	Preview   string   `json:"preview,omitempty" jsonschema:"description=Short preview of text content"`
+	Truncated bool     `json:"truncated,omitempty"`
+	Files     []string `json:"files,omitempty" jsonschema:"description=File paths when the clipboard holds file references"`
+}
+
+// ClipboardGetArgs defines arguments for the clipboard_get tool
+type ClipboardGetArgs struct {
+	MaxChars    int    `json:"max_chars,omitempty" jsonschema:"description=Maximum characters of text to return (default: 20000)"`
+	IncludeData string `json:"include_data,omitempty" jsonschema:"description=Set to 'true' to also return image data as base64 (up to 5 MB)"`
+}
+
+// ClipboardGetResult defines the result of the clipboard_get tool
+type ClipboardGetResult struct {
+	Kind      string   `json:"kind" jsonschema:"description=text, file, image, data, or empty"`
+	Type      string   `json:"type,omitempty" jsonschema:"description=UTI of the clipboard content"`
+	Text      string   `json:"text,omitempty" jsonschema:"description=Clipboard text, up to max_chars"`
+	Size      int      `json:"size,omitempty" jsonschema:"description=Size in bytes of text or binary data"`
+	Truncated bool     `json:"truncated,omitempty"`
+	Files     []string `json:"files,omitempty" jsonschema:"description=File paths when the clipboard holds file references"`
+
+	// Images only
+	Format string `json:"format,omitempty" jsonschema:"description=Image format, e.g. png or tiff"`
+	Width  int    `json:"width,omitempty"`
+	Height int    `json:"height,omitempty"`
+	Data   string `json:"data,omitempty" jsonschema:"description=Base64 image data, only with include_data"`
+	Note   string `json:"note,omitempty" jsonschema:"description=Why data was not included"`
+}
+
+// ClipboardStatusResult defines the result of the clipboard_status tool
+type ClipboardStatusResult struct {
+	Clipboard   ClipboardSnapshot `json:"clipboard"`
+	RecentFile  *RecentFile       `json:"most_recent_download,omitempty"`
+	RecentError string            `json:"recent_error,omitempty"`
 }
 
 // AgentBuffer represents an in-memory clipboard buffer for agent use
@@ -76,6 +163,7 @@ type BufferCopyArgs struct {
 	File      string `json:"file" jsonschema:"description=File path to copy from (required)"`
 	StartLine int    `json:"start_line,omitempty" jsonschema:"description=Starting line number (1-indexed, omit for entire file)"`
 	EndLine   int    `json:"end_line,omitempty" jsonschema:"description=Ending line number (inclusive, omit for entire file)"`
+	MaxLines  int    `json:"max_lines,omitempty" jsonschema:"description=Maximum number of lines to copy (extra lines are truncated and reported)"`
 }
 
 // BufferPasteArgs defines arguments for buffer_paste tool
@@ -88,11 +176,13 @@ type BufferPasteArgs struct {
 
 // BufferResult defines the result of buffer operations
 type BufferResult struct {
-	Success     bool   `json:"success"`
-	Message     string `json:"message,omitempty"`
-	Lines       int    `json:"lines,omitempty"`
-	SourceFile  string `json:"source_file,omitempty"`
-	SourceRange string `json:"source_range,omitempty"`
+	Success        bool   `json:"success"`
+	Message        string `json:"message,omitempty"`
+	Lines          int    `json:"lines,omitempty"`
+	SourceFile     string `json:"source_file,omitempty"`
+	SourceRange    string `json:"source_range,omitempty"`
+	TotalLines     int    `json:"total_lines,omitempty"`     // Line count of the source file
+	TruncatedLines int    `json:"truncated_lines,omitempty"` // Lines dropped by max_lines
 }
 
 // StartServer starts the MCP server.
@@ -102,6 +192,10 @@ func StartServer() error {
 
 // StartServerWithOptions starts the MCP server with optional metadata overrides.
 func StartServerWithOptions(opts ServerOptions) error {
+	// SIGINT and SIGTERM cancel ctx, which stops the server and its background work
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	metadata, err := LoadServerMetadata(opts)
 	if err != nil {
 		return err
@@ -122,6 +216,14 @@ func StartServerWithOptions(opts ServerOptions) error {
 	if err != nil {
 		return err
 	}
+	statusSpec, err := requireToolSpec(toolSpecs, "clipboard_status")
+	if err != nil {
+		return err
+	}
+	getSpec, err := requireToolSpec(toolSpecs, "clipboard_get")
+	if err != nil {
+		return err
+	}
 	bufferCopySpec, err := requireToolSpec(toolSpecs, "buffer_copy")
 	if err != nil {
 		return err
@@ -149,9 +251,11 @@ func StartServerWithOptions(opts ServerOptions) error {
 	}
 
 	// Create MCP server
+	name, version := serverIdentity(opts)
 	s := server.NewMCPServer(
-		"Clippy MCP Server",
-		"1.0.0",
+		name,
+		version,
+		server.WithResourceCapabilities(false, true),
 	)
 
 	// Create agent clipboard buffer (persists for the session)
@@ -173,6 +277,24 @@ func StartServerWithOptions(opts ServerOptions) error {
 	if err != nil {
 		return err
 	}
+	copyForceFileDesc, err := toolParamDescription(copySpec, "force_file")
+	if err != nil {
+		return err
+	}
+
+	copyStartDesc, err := toolParamDescription(copySpec, "start_line")
+	if err != nil {
+		return err
+	}
+	copyEndDesc, err := toolParamDescription(copySpec, "end_line")
+	if err != nil {
+		return err
+	}
+
+	copyAppendDesc, err := toolParamDescription(copySpec, "append")
+	if err != nil {
+		return err
+	}
 
 	copyTool := mcp.NewTool(
 		"clipboard_copy",
@@ -180,6 +302,10 @@ func StartServerWithOptions(opts ServerOptions) error {
 		mcp.WithString("text", mcp.Description(copyTextDesc)),
 		mcp.WithString("file", mcp.Description(copyFileDesc)),
 		mcp.WithString("force_text", mcp.Description(copyForceTextDesc)),
+		mcp.WithString("force_file", mcp.Description(copyForceFileDesc)),
+		mcp.WithNumber("start_line", mcp.Description(copyStartDesc)),
+		mcp.WithNumber("end_line", mcp.Description(copyEndDesc)),
+		mcp.WithString("append", mcp.Description(copyAppendDesc)),
 	)
 
 	// Add copy tool handler
@@ -199,9 +325,77 @@ func StartServerWithOptions(opts ServerOptions) error {
 			return nil, fmt.Errorf("provide either text or file to copy")
 		}
 
+		forceText := isTrueArg(args.ForceText)
+		forceFile := isTrueArg(args.ForceFile)
+		if forceText && forceFile {
+			return nil, fmt.Errorf("force_text and force_file cannot both be set")
+		}
+
+		// force_file catches a path passed as text by mistake: if the text is
+		// an existing path, copy it as a file reference instead of as text
+		if forceFile && args.Text != "" {
+			if path := existingPath(args.Text); path != "" {
+				args.File = path
+				args.Text = ""
+			}
+		}
+
+		// A line range copies part of a file as text
+		hasRange := args.StartLine > 0 || args.EndLine > 0
+		if hasRange && args.File == "" {
+			return nil, fmt.Errorf("start_line and end_line can only be used with file")
+		}
+
+		// Appending builds up text across calls; it can't apply to a file
+		appendText := isTrueArg(args.Append)
+		if appendText && args.Text == "" {
+			return nil, fmt.Errorf("append can only be used with text")
+		}
+
 		var result CopyResult
 
-		if args.Text != "" {
+		if appendText {
+			if err := clippy.AppendText(args.Text, "\n"); err != nil {
+				result = CopyResult{
+					Success: false,
+					Message: fmt.Sprintf("Failed to append text: %v", err),
+				}
+			} else {
+				result = CopyResult{
+					Success: true,
+					Type:    "text",
+					Message: fmt.Sprintf("Appended %d characters to the clipboard text", len(args.Text)),
+				}
+			}
+		} else if hasRange {
+			absPath, err := filepath.Abs(args.File)
+			if err != nil {
+				return nil, fmt.Errorf("invalid file path: %w", err)
+			}
+			content, err := readFileLimited(absPath)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read file: %w", err)
+			}
+
+			lines, _ := splitLines(string(content))
+			selected, rangeStr, err := lineRange(lines, args.StartLine, args.EndLine)
+			if err != nil {
+				return nil, err
+			}
+
+			if err := clippy.CopyText(joinLines(selected, detectLineEnding(string(content)), false)); err != nil {
+				result = CopyResult{
+					Success: false,
+					Message: fmt.Sprintf("Failed to copy lines: %v", err),
+				}
+			} else {
+				result = CopyResult{
+					Success: true,
+					Type:    "text",
+					Message: fmt.Sprintf("Copied lines %s of %s as text", rangeStr, filepath.Base(absPath)),
+				}
+			}
+		} else if args.Text != "" {
 			// Copy text
 			err := clippy.CopyText(args.Text)
 			if err != nil {
@@ -228,7 +422,13 @@ func StartServerWithOptions(opts ServerOptions) error {
 				return nil, fmt.Errorf("file not found: %s", absPath)
 			}
 
-			forceText := args.ForceText == "true" || args.ForceText == "1"
+			// Copying as text reads the whole file into memory
+			if forceText {
+				if err := checkFileSize(absPath); err != nil {
+					return nil, err
+				}
+			}
+
 			copyResult, err := clippy.CopyWithResultAndMode(absPath, forceText)
 			if err != nil {
 				result = CopyResult{
@@ -350,12 +550,23 @@ func StartServerWithOptions(opts ServerOptions) error {
 	if err != nil {
 		return err
 	}
+	recentLimitDesc, err := toolParamDescription(recentSpec, "limit")
+	if err != nil {
+		return err
+	}
+
+	recentPreviewDesc, err := toolParamDescription(recentSpec, "preview_bytes")
+	if err != nil {
+		return err
+	}
 
 	recentTool := mcp.NewTool(
 		"get_recent_downloads",
 		mcp.WithDescription(recentSpec.Description),
 		mcp.WithNumber("count", mcp.Description(recentCountDesc)),
 		mcp.WithString("duration", mcp.Description(recentDurationDesc)),
+		mcp.WithNumber("limit", mcp.Description(recentLimitDesc)),
+		mcp.WithNumber("preview_bytes", mcp.Description(recentPreviewDesc)),
 	)
 
 	// Add recent downloads tool handler
@@ -366,39 +577,121 @@ func StartServerWithOptions(opts ServerOptions) error {
 			return nil, fmt.Errorf("invalid arguments: %w", err)
 		}
 
-		// Set defaults
-		if args.Count == 0 {
-			args.Count = 10
-		}
-
 		// Parse duration if provided
-		config := recent.PickerConfig{}
+		opts := recent.DefaultFindOptions()
 		if args.Duration != "" {
 			maxAge, err := recent.ParseDuration(args.Duration)
 			if err != nil {
 				return nil, fmt.Errorf("invalid duration: %w", err)
 			}
-			config.MaxAge = maxAge
+			opts.MaxAge = maxAge
 		}
 
-		// Get recent downloads
-		files, err := recent.GetRecentDownloads(config, args.Count)
+		// Get recent downloads, counting every match before the cap
+		var found int
+		opts.MaxCount = recentDownloadsCap(args)
+		opts.Found = &found
+		files, err := recent.FindRecentFiles(opts)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get recent downloads: %w", err)
 		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("failed to get recent downloads: no recent files found")
+		}
 
 		// Convert to response format
-		var recentFiles []RecentFile
+		result := RecentDownloadsResult{
+			Files:      make([]RecentFile, 0, len(files)),
+			TotalFound: found,
+			Truncated:  found > len(files),
+		}
+		previewBytes := min(args.PreviewBytes, maxPreviewBytes)
 		for _, file := range files {
-			recentFiles = append(recentFiles, RecentFile{
+			recentFile := RecentFile{
+				Path:     file.Path,
+				Name:     file.Name,
+				Size:     file.Size,
+				Modified: file.Modified.Format("2006-01-02 15:04:05"),
+			}
+			// Binary files only get their type, never their bytes
+			if previewBytes > 0 {
+				recentFile.Type = file.MimeType
+				if clippy.IsTextualMimeType(file.MimeType) {
+					recentFile.Preview, recentFile.PreviewTruncated, _ = filePreview(file.Path, previewBytes)
+				}
+			}
+			result.Files = append(result.Files, recentFile)
+		}
+
+		resultJSON, _ := json.Marshal(result)
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{mcp.TextContent{
+				Type: "text",
+				Text: string(resultJSON),
+			}},
+		}, nil
+	})
+
+	// Define clipboard_status tool
+	statusTool := mcp.NewTool(
+		"clipboard_status",
+		mcp.WithDescription(statusSpec.Description),
+	)
+
+	// Add clipboard_status tool handler
+	s.AddTool(statusTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result := ClipboardStatusResult{
+			Clipboard: clipboardSnapshot(),
+		}
+
+		// Only the single newest file - this is a quick "what's new" check
+		if file, err := recent.CopyMostRecentDownload(0); err != nil {
+			result.RecentError = err.Error()
+		} else {
+			result.RecentFile = &RecentFile{
 				Path:     file.Path,
 				Name:     file.Name,
 				Size:     file.Size,
 				Modified: file.Modified.Format("2006-01-02 15:04:05"),
-			})
+			}
+		}
+
+		resultJSON, _ := json.Marshal(result)
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{mcp.TextContent{
+				Type: "text",
+				Text: string(resultJSON),
+			}},
+		}, nil
+	})
+
+	// Define clipboard_get tool
+	getMaxCharsDesc, err := toolParamDescription(getSpec, "max_chars")
+	if err != nil {
+		return err
+	}
+
+	getIncludeDataDesc, err := toolParamDescription(getSpec, "include_data")
+	if err != nil {
+		return err
+	}
+
+	getTool := mcp.NewTool(
+		"clipboard_get",
+		mcp.WithDescription(getSpec.Description),
+		mcp.WithNumber("max_chars", mcp.Description(getMaxCharsDesc)),
+		mcp.WithString("include_data", mcp.Description(getIncludeDataDesc)),
+	)
+
+	// Add clipboard_get tool handler
+	s.AddTool(getTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args ClipboardGetArgs
+		argsBytes, _ := json.Marshal(request.Params.Arguments)
+		if err := json.Unmarshal(argsBytes, &args); err != nil {
+			return nil, fmt.Errorf("invalid arguments: %w", err)
 		}
 
-		resultJSON, _ := json.Marshal(recentFiles)
+		resultJSON, _ := json.Marshal(clipboardGet(args))
 		return &mcp.CallToolResult{
 			Content: []mcp.Content{mcp.TextContent{
 				Type: "text",
@@ -420,6 +713,10 @@ func StartServerWithOptions(opts ServerOptions) error {
 	if err != nil {
 		return err
 	}
+	bufferCopyMaxDesc, err := toolParamDescription(bufferCopySpec, "max_lines")
+	if err != nil {
+		return err
+	}
 
 	bufferCopyTool := mcp.NewTool(
 		"buffer_copy",
@@ -427,6 +724,7 @@ func StartServerWithOptions(opts ServerOptions) error {
 		mcp.WithString("file", mcp.Description(bufferCopyFileDesc), mcp.Required()),
 		mcp.WithNumber("start_line", mcp.Description(bufferCopyStartDesc)),
 		mcp.WithNumber("end_line", mcp.Description(bufferCopyEndDesc)),
+		mcp.WithNumber("max_lines", mcp.Description(bufferCopyMaxDesc)),
 	)
 
 	// Add buffer_copy tool handler
@@ -447,50 +745,54 @@ func StartServerWithOptions(opts ServerOptions) error {
 		}
 
 		// Read the entire file
-		content, err := os.ReadFile(absPath)
+		content, err := readFileLimited(absPath)
 		if err != nil {
 			return nil, fmt.Errorf("failed to read file: %w", err)
 		}
 
-		lines := strings.Split(string(content), "\n")
-		var rangeStr string
-		var linesToCopy []string
+		eol := detectLineEnding(string(content))
+		lines, trailingNewline := splitLines(string(content))
+		linesToCopy, rangeStr, err := lineRange(lines, args.StartLine, args.EndLine)
+		if err != nil {
+			return nil, err
+		}
 
-		// Handle line range
-		if args.StartLine > 0 || args.EndLine > 0 {
-			start := args.StartLine
-			end := args.EndLine
+		// Only a whole-file copy keeps the final newline
+		copyTrailingNewline := trailingNewline && rangeStr == "all"
 
+		// Guard against accidentally huge ranges
+		truncated := 0
+		if args.MaxLines > 0 && len(linesToCopy) > args.MaxLines {
+			truncated = len(linesToCopy) - args.MaxLines
+			linesToCopy = linesToCopy[:args.MaxLines]
+			start := args.StartLine
 			if start < 1 {
 				start = 1
 			}
-			if end < 1 || end > len(lines) {
-				end = len(lines)
-			}
-			if start > end {
-				return nil, fmt.Errorf("start_line (%d) cannot be greater than end_line (%d)", start, end)
-			}
-
-			linesToCopy = lines[start-1 : end]
-			rangeStr = fmt.Sprintf("%d-%d", start, end)
-		} else {
-			linesToCopy = lines
-			rangeStr = "all"
+			rangeStr = fmt.Sprintf("%d-%d", start, start+args.MaxLines-1)
+			copyTrailingNewline = false
 		}
 
-		// Store raw bytes in buffer
-		copiedContent := []byte(strings.Join(linesToCopy, "\n"))
+		// Store raw bytes in buffer, keeping the source file's line endings
+		copiedContent := []byte(joinLines(linesToCopy, eol, copyTrailingNewline))
 		agentBuffer.Content = copiedContent
 		agentBuffer.Lines = len(linesToCopy)
 		agentBuffer.SourceFile = filepath.Base(absPath)
 		agentBuffer.SourceRange = rangeStr
 
+		message := fmt.Sprintf("Copied %d lines from %s (lines %s)", len(linesToCopy), filepath.Base(absPath), rangeStr)
+		if truncated > 0 {
+			message += fmt.Sprintf("; truncated %d lines over max_lines %d", truncated, args.MaxLines)
+		}
+
 		result := BufferResult{
-			Success:     true,
-			Message:     fmt.Sprintf("Copied %d lines from %s (lines %s)", len(linesToCopy), filepath.Base(absPath), rangeStr),
-			Lines:       len(linesToCopy),
-			SourceFile:  filepath.Base(absPath),
-			SourceRange: rangeStr,
+			Success:        true,
+			Message:        message,
+			Lines:          len(linesToCopy),
+			SourceFile:     filepath.Base(absPath),
+			SourceRange:    rangeStr,
+			TotalLines:     len(lines),
+			TruncatedLines: truncated,
 		}
 
 		resultJSON, _ := json.Marshal(result)
@@ -555,67 +857,8 @@ func StartServerWithOptions(opts ServerOptions) error {
 			mode = "append"
 		}
 
-		// Read target file if it exists
-		var targetLines []string
-		existingContent, err := os.ReadFile(absPath)
-		if err != nil {
-			if !os.IsNotExist(err) {
-				return nil, fmt.Errorf("failed to read target file: %w", err)
-			}
-			// File doesn't exist, create it
-			targetLines = []string{}
-		} else {
-			targetLines = strings.Split(string(existingContent), "\n")
-		}
-
-		bufferLines := strings.Split(string(agentBuffer.Content), "\n")
-		var newLines []string
-
-		switch mode {
-		case "append":
-			// Append buffer content to end of file
-			newLines = append(targetLines, bufferLines...)
-
-		case "insert":
-			if args.AtLine < 1 {
-				return nil, fmt.Errorf("at_line is required for insert mode")
-			}
-			insertAt := args.AtLine - 1
-			if insertAt > len(targetLines) {
-				insertAt = len(targetLines)
-			}
-			// Insert buffer content at specified line
-			newLines = make([]string, 0, len(targetLines)+len(bufferLines))
-			newLines = append(newLines, targetLines[:insertAt]...)
-			newLines = append(newLines, bufferLines...)
-			newLines = append(newLines, targetLines[insertAt:]...)
-
-		case "replace":
-			if args.AtLine < 1 || args.ToLine < 1 {
-				return nil, fmt.Errorf("at_line and to_line are required for replace mode")
-			}
-			replaceFrom := args.AtLine - 1
-			replaceTo := args.ToLine
-			if replaceFrom >= len(targetLines) {
-				return nil, fmt.Errorf("at_line %d is beyond file length %d", args.AtLine, len(targetLines))
-			}
-			if replaceTo > len(targetLines) {
-				replaceTo = len(targetLines)
-			}
-			// Replace lines [from, to] with buffer content
-			newLines = make([]string, 0)
-			newLines = append(newLines, targetLines[:replaceFrom]...)
-			newLines = append(newLines, bufferLines...)
-			newLines = append(newLines, targetLines[replaceTo:]...)
-
-		default:
-			return nil, fmt.Errorf("invalid mode %q: must be 'append', 'insert', or 'replace'", mode)
-		}
-
-		// Write the new content
-		newContent := []byte(strings.Join(newLines, "\n"))
-		if err := os.WriteFile(absPath, newContent, 0644); err != nil {
-			return nil, fmt.Errorf("failed to write file: %w", err)
+		if err := pasteBuffer(absPath, agentBuffer.Content, mode, args.AtLine, args.ToLine); err != nil {
+			return nil, err
 		}
 
 		result := BufferResult{
@@ -675,12 +918,13 @@ func StartServerWithOptions(opts ServerOptions) error {
 		}
 
 		// Read the entire file
-		content, err := os.ReadFile(absPath)
+		content, err := readFileLimited(absPath)
 		if err != nil {
 			return nil, fmt.Errorf("failed to read file: %w", err)
 		}
 
-		lines := strings.Split(string(content), "\n")
+		eol := detectLineEnding(string(content))
+		lines, trailingNewline := splitLines(string(content))
 		var rangeStr string
 		var linesToCut []string
 		var remainingLines []string
@@ -716,15 +960,19 @@ func StartServerWithOptions(opts ServerOptions) error {
 		}
 
 		// Store cut content in buffer first (atomic - only delete if this succeeds)
-		cutContent := []byte(strings.Join(linesToCut, "\n"))
+		cutContent := []byte(joinLines(linesToCut, eol, trailingNewline && rangeStr == "all"))
 		agentBuffer.Content = cutContent
 		agentBuffer.Lines = len(linesToCut)
 		agentBuffer.SourceFile = filepath.Base(absPath)
 		agentBuffer.SourceRange = rangeStr
 
-		// Now write back the file without the cut lines
-		newContent := []byte(strings.Join(remainingLines, "\n"))
-		if err := os.WriteFile(absPath, newContent, 0644); err != nil {
+		// Now write back the file without the cut lines, keeping its permissions
+		fileMode := defaultFileMode
+		if info, err := os.Stat(absPath); err == nil {
+			fileMode = info.Mode().Perm()
+		}
+		newContent := []byte(joinLines(remainingLines, eol, trailingNewline))
+		if err := fsutil.WriteFileAtomic(absPath, newContent, fileMode); err != nil {
 			return nil, fmt.Errorf("failed to write file after cut: %w", err)
 		}
 
@@ -836,6 +1084,175 @@ func StartServerWithOptions(opts ServerOptions) error {
 		}, nil
 	})
 
+	// Expose recent downloads as resources alongside get_recent_downloads
+	serveRecentResources(ctx, s)
+
 	// Start the server
-	return server.ServeStdio(s)
+	return serveStdio(ctx, s)
+}
+
+// serveStdio runs the server on stdin/stdout until stdin closes or ctx is
+// cancelled. In-flight tool calls finish first; a signal is a clean exit.
+func serveStdio(ctx context.Context, s *server.MCPServer) error {
+	err := server.NewStdioServer(s).Listen(ctx, os.Stdin, os.Stdout)
+	if ctx.Err() != nil {
+		fmt.Fprintln(os.Stderr, "Clippy MCP server stopped by signal")
+		return nil
+	}
+	return err
+}
+
+// clipboardSnapshot summarizes the system clipboard.
+// Text gets a short preview; binary data is only described, never returned.
+func clipboardSnapshot() ClipboardSnapshot {
+	content, err := clipboard.GetClipboardContent()
+	if err != nil {
+		return ClipboardSnapshot{Empty: true}
+	}
+
+	snapshot := ClipboardSnapshot{Type: content.Type}
+
+	switch {
+	case content.IsFile:
+		snapshot.Kind = "file"
+		snapshot.Files = clippy.GetFiles()
+	case content.IsText:
+		snapshot.Kind = "text"
+		snapshot.Size = len(content.Data)
+		snapshot.Preview, snapshot.Truncated = previewText(string(content.Data), statusPreviewChars)
+	default:
+		snapshot.Kind = "data"
+		snapshot.Size = len(content.Data)
+	}
+
+	return snapshot
+}
+
+// clipboardGet reads the system clipboard: text up to max_chars runes, the list
+// of file references, image format and dimensions (and data on request), or just
+// the type and size of other data
+func clipboardGet(args ClipboardGetArgs) ClipboardGetResult {
+	maxChars := args.MaxChars
+	if maxChars <= 0 {
+		maxChars = defaultGetChars
+	}
+	maxChars = min(maxChars, maxGetChars)
+
+	content, err := clipboard.GetClipboardContent()
+	if err != nil {
+		return ClipboardGetResult{Kind: "empty"}
+	}
+
+	result := ClipboardGetResult{Type: content.Type}
+	switch {
+	case content.IsFile:
+		result.Kind = "file"
+		result.Files = clippy.GetFiles()
+	case content.IsText:
+		result.Kind = "text"
+		result.Size = len(content.Data)
+		result.Text, result.Truncated = previewText(string(content.Data), maxChars)
+	case clipboard.UTIConformsTo(content.Type, "public.image"):
+		result.Kind = "image"
+		result.Size = len(content.Data)
+		describeImage(&result, content.Data, isTrueArg(args.IncludeData))
+	default:
+		result.Kind = "data"
+		result.Size = len(content.Data)
+	}
+	return result
+}
+
+// describeImage fills in the image format and dimensions, and the base64 data if
+// requested and small enough. Formats Go can't decode (e.g. HEIC) keep just the type.
+func describeImage(result *ClipboardGetResult, data []byte, includeData bool) {
+	if config, format, err := image.DecodeConfig(bytes.NewReader(data)); err == nil {
+		result.Format = format
+		result.Width = config.Width
+		result.Height = config.Height
+	}
+
+	switch {
+	case !includeData:
+	case len(data) > maxGetImageBytes:
+		result.Note = fmt.Sprintf("image data is %d bytes, over the %d byte limit for include_data", len(data), maxGetImageBytes)
+	default:
+		result.Data = base64.StdEncoding.EncodeToString(data)
+	}
+}
+
+// recentDownloadsCap returns how many files get_recent_downloads may return.
+// Without a duration that is count; with one it is limit, so "everything from
+// the last hour" isn't silently cut to the small default count.
+func recentDownloadsCap(args RecentDownloadsArgs) int {
+	if args.Duration == "" {
+		if args.Count > 0 {
+			return args.Count
+		}
+		return defaultRecentCount
+	}
+	if args.Limit > 0 {
+		return args.Limit
+	}
+	return defaultRecentLimit
+}
+
+// filePreview returns up to maxBytes from the start of a file, without splitting
+// a UTF-8 character, and whether the file is longer than that
+func filePreview(path string, maxBytes int) (string, bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", false, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	data, err := io.ReadAll(io.LimitReader(f, int64(maxBytes)+1))
+	if err != nil {
+		return "", false, err
+	}
+	truncated := len(data) > maxBytes
+	if truncated {
+		data = data[:maxBytes]
+		for i := 0; i < utf8.UTFMax && len(data) > 0 && !utf8.Valid(data); i++ {
+			data = data[:len(data)-1]
+		}
+	}
+	return string(data), truncated, nil
+}
+
+// previewText returns at most maxChars runes of text and whether it was truncated
+func previewText(text string, maxChars int) (string, bool) {
+	runes := []rune(text)
+	if len(runes) <= maxChars {
+		return text, false
+	}
+	return string(runes[:maxChars]), true
+}
+
+// isTrueArg reports whether a string tool argument is set to true
+func isTrueArg(value string) bool {
+	return value == "true" || value == "1"
+}
+
+// existingPath returns the absolute path if text is a single existing path, or "" otherwise
+func existingPath(text string) string {
+	candidate := strings.TrimSpace(text)
+	if candidate == "" || strings.Contains(candidate, "\n") {
+		return ""
+	}
+	if strings.HasPrefix(candidate, "~/") {
+		if homeDir, err := os.UserHomeDir(); err == nil {
+			candidate = filepath.Join(homeDir, candidate[2:])
+		}
+	}
+	absPath, err := filepath.Abs(candidate)
+	if err != nil {
+		return ""
+	}
+	if _, err := os.Stat(absPath); err != nil {
+		return ""
+	}
+	return absPath
 }
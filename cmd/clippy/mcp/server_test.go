@@ -0,0 +1,187 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/neilberkman/clippy/pkg/clipboard"
+)
+
+func TestPreviewText(t *testing.T) {
+	tests := []struct {
+		name          string
+		text          string
+		maxChars      int
+		want          string
+		wantTruncated bool
+	}{
+		{"short text", "hello", 10, "hello", false},
+		{"exact length", "hello", 5, "hello", false},
+		{"truncated", "hello world", 5, "hello", true},
+		{"multibyte runes", "héllo wörld", 7, "héllo w", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, truncated := previewText(tt.text, tt.maxChars)
+			if got != tt.want || truncated != tt.wantTruncated {
+				t.Errorf("previewText(%q, %d) = (%q, %v), want (%q, %v)", tt.text, tt.maxChars, got, truncated, tt.want, tt.wantTruncated)
+			}
+		})
+	}
+}
+
+func TestExistingPath(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "report.pdf")
+	if err := os.WriteFile(file, []byte("pdf"), 0o644); err != nil {
+		t.Fatalf("write temp file: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"existing path", file, file},
+		{"surrounding whitespace", "  " + file + "\n", file},
+		{"missing path", filepath.Join(dir, "missing.pdf"), ""},
+		{"plain text", "just some generated text", ""},
+		{"multiple lines", file + "\n" + file, ""},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := existingPath(tt.text); got != tt.want {
+				t.Errorf("existingPath(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecentDownloadsCap(t *testing.T) {
+	tests := []struct {
+		name string
+		args RecentDownloadsArgs
+		want int
+	}{
+		{"defaults", RecentDownloadsArgs{}, defaultRecentCount},
+		{"count", RecentDownloadsArgs{Count: 3}, 3},
+		{"duration ignores count", RecentDownloadsArgs{Count: 3, Duration: "1h"}, defaultRecentLimit},
+		{"duration with limit", RecentDownloadsArgs{Duration: "1h", Limit: 200}, 200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := recentDownloadsCap(tt.args); got != tt.want {
+				t.Errorf("recentDownloadsCap(%+v) = %d, want %d", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilePreview(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "notes.txt")
+	if err := os.WriteFile(file, []byte("héllo"), 0o644); err != nil {
+		t.Fatalf("write temp file: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		maxBytes      int
+		want          string
+		wantTruncated bool
+	}{
+		{"whole file", 100, "héllo", false},
+		{"exact length", 6, "héllo", false},
+		{"truncated", 4, "hél", true},
+		{"does not split a character", 2, "h", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, truncated, err := filePreview(file, tt.maxBytes)
+			if err != nil {
+				t.Fatalf("filePreview returned error: %v", err)
+			}
+			if got != tt.want || truncated != tt.wantTruncated {
+				t.Errorf("filePreview(%d) = (%q, %v), want (%q, %v)", tt.maxBytes, got, truncated, tt.want, tt.wantTruncated)
+			}
+		})
+	}
+}
+
+func TestClipboardGet(t *testing.T) {
+	mem := clipboard.NewMemoryManager()
+	previous := clipboard.SetManager(mem)
+	t.Cleanup(func() { clipboard.SetManager(previous) })
+
+	if got := clipboardGet(ClipboardGetArgs{}); got.Kind != "empty" {
+		t.Errorf("clipboardGet on empty clipboard = %+v, want kind empty", got)
+	}
+
+	if err := mem.CopyText("hello world"); err != nil {
+		t.Fatalf("CopyText returned error: %v", err)
+	}
+	if got := clipboardGet(ClipboardGetArgs{MaxChars: 5}); got.Kind != "text" || got.Text != "hello" || !got.Truncated || got.Size != 11 {
+		t.Errorf("clipboardGet(5) = %+v, want truncated text", got)
+	}
+
+	if err := mem.CopyFiles([]string{"/tmp/a.pdf", "/tmp/b.pdf"}); err != nil {
+		t.Fatalf("CopyFiles returned error: %v", err)
+	}
+	if got := clipboardGet(ClipboardGetArgs{}); got.Kind != "file" || len(got.Files) != 2 {
+		t.Errorf("clipboardGet with files = %+v, want both files", got)
+	}
+
+	if err := mem.CopyFlavors([]clipboard.Flavor{{Type: "public.png", Data: []byte("png")}}); err != nil {
+		t.Fatalf("CopyFlavors returned error: %v", err)
+	}
+	if got := clipboardGet(ClipboardGetArgs{}); got.Kind != "image" || got.Type != "public.png" || got.Size != 3 || got.Text != "" {
+		t.Errorf("clipboardGet with an image = %+v, want image type and size only", got)
+	}
+}
+
+func TestServeStdioStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	s := server.NewMCPServer("test", "dev")
+	if err := serveStdio(ctx, s); err != nil {
+		t.Errorf("serveStdio after cancel = %v, want a clean shutdown", err)
+	}
+}
+
+func TestDescribeImage(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, 3, 2))); err != nil {
+		t.Fatalf("encode png: %v", err)
+	}
+
+	var result ClipboardGetResult
+	describeImage(&result, buf.Bytes(), false)
+	if result.Format != "png" || result.Width != 3 || result.Height != 2 || result.Data != "" {
+		t.Errorf("describeImage without data = %+v, want a 3x2 png and no data", result)
+	}
+
+	result = ClipboardGetResult{}
+	describeImage(&result, buf.Bytes(), true)
+	if result.Data != base64.StdEncoding.EncodeToString(buf.Bytes()) {
+		t.Errorf("describeImage with data did not return the base64 image")
+	}
+
+	result = ClipboardGetResult{}
+	describeImage(&result, []byte("not an image"), true)
+	if result.Format != "" || result.Width != 0 {
+		t.Errorf("describeImage(garbage) = %+v, want no format or size", result)
+	}
+}
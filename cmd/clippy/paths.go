@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/neilberkman/clippy"
+)
+
+// handlePaths copies the files' paths as text, one per line: relative to their
+// git repository roots with --git-path, otherwise absolute. Files outside a
+// repository fall back to their absolute path. With --ref the line or range is
+// appended, e.g. cmd/clippy/main.go:42-50.
+func handlePaths(files []string) {
+	paths := make([]string, len(files))
+	for i, file := range files {
+		abs, err := filepath.Abs(file)
+		if err != nil {
+			logger.Error("Invalid path %s: %v", file, err)
+			os.Exit(exitCode(err))
+		}
+		if _, err := os.Stat(abs); err != nil {
+			err = fmt.Errorf("%w: %s", clippy.ErrFileNotFound, abs)
+			logger.Error("%v", err)
+			os.Exit(exitCode(err))
+		}
+		paths[i] = abs
+
+		if gitPath {
+			rel, err := clippy.RepoRelativePath(file)
+			switch {
+			case errors.Is(err, clippy.ErrNotInRepo):
+				logger.Warn("%s is not in a git repository; copying its absolute path", file)
+			case err != nil:
+				logger.Error("%v", err)
+				os.Exit(exitCode(err))
+			default:
+				paths[i] = rel
+			}
+		}
+
+		if refFlag != "" {
+			if lines, err := countLines(abs); err == nil && refEnd > lines {
+				logger.Warn("%s has %s; the reference points past the end", file, plural(lines, "line"))
+			}
+			paths[i] = lineRef(paths[i], refStart, refEnd)
+		}
+	}
+
+	stop := logger.Timer("Clipboard write")
+	err := clippy.CopyText(strings.Join(paths, "\n"))
+	stop()
+	if err != nil {
+		logger.Error("Could not copy paths: %v", err)
+		os.Exit(exitCode(err))
+	}
+	switch {
+	case refFlag != "":
+		reportSuccess("✅ Copied reference %s", paths[0])
+	case len(paths) == 1:
+		reportSuccess("✅ Copied path %s", paths[0])
+	default:
+		reportSuccess("✅ Copied %s", plural(len(paths), "path"))
+	}
+	runPostCopyHook("text", files...)
+}
+
+// parseLineRange parses a --ref value: a line number ("42") or an inclusive
+// range ("42-50"). A single line returns the same start and end.
+func parseLineRange(spec string) (start, end int, err error) {
+	first, last, isRange := strings.Cut(strings.TrimSpace(spec), "-")
+	start, err = strconv.Atoi(first)
+	if err != nil || start < 1 {
+		return 0, 0, fmt.Errorf("invalid --ref %q: use a line number such as 42 or a range such as 42-50", spec)
+	}
+	end = start
+	if isRange {
+		end, err = strconv.Atoi(last)
+		if err != nil || end < start {
+			return 0, 0, fmt.Errorf("invalid --ref %q: use a line number such as 42 or a range such as 42-50", spec)
+		}
+	}
+	return start, end, nil
+}
+
+// lineRef formats a path:line or path:start-end reference
+func lineRef(path string, start, end int) string {
+	if end == start {
+		return fmt.Sprintf("%s:%d", path, start)
+	}
+	return fmt.Sprintf("%s:%d-%d", path, start, end)
+}
+
+// countLines counts the lines in a file; a last line without a newline counts
+func countLines(path string) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	lines := 0
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16<<20)
+	for scanner.Scan() {
+		lines++
+	}
+	return lines, scanner.Err()
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseLineRange(t *testing.T) {
+	tests := []struct {
+		spec       string
+		start, end int
+		wantErr    bool
+	}{
+		{"42", 42, 42, false},
+		{"42-50", 42, 50, false},
+		{"7-7", 7, 7, false},
+		{"0", 0, 0, true},
+		{"50-42", 0, 0, true},
+		{"x", 0, 0, true},
+		{"4-", 0, 0, true},
+	}
+	for _, tt := range tests {
+		start, end, err := parseLineRange(tt.spec)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseLineRange(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			continue
+		}
+		if start != tt.start || end != tt.end {
+			t.Errorf("parseLineRange(%q) = %d, %d, want %d, %d", tt.spec, start, end, tt.start, tt.end)
+		}
+	}
+}
+
+func TestLineRef(t *testing.T) {
+	if got := lineRef("cmd/clippy/main.go", 42, 42); got != "cmd/clippy/main.go:42" {
+		t.Errorf("lineRef(single) = %q", got)
+	}
+	if got := lineRef("cmd/clippy/main.go", 42, 50); got != "cmd/clippy/main.go:42-50" {
+		t.Errorf("lineRef(range) = %q", got)
+	}
+}
+
+func TestCountLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lines.txt")
+	if err := os.WriteFile(path, []byte("one\ntwo\nthree"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := countLines(path); err != nil || got != 3 {
+		t.Errorf("countLines() = %d, %v, want 3", got, err)
+	}
+}
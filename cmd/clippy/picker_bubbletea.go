@@ -2,12 +2,17 @@ package main
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/fsnotify/fsnotify"
+	"github.com/mattn/go-runewidth"
+	"github.com/neilberkman/clippy/cmd/internal/common"
 	"github.com/neilberkman/clippy/pkg/recent"
 	"github.com/neilberkman/mimedescription"
 )
@@ -27,7 +32,7 @@ type pickerModel struct {
 	selected       map[int]bool
 	done           bool
 	cancelled      bool
-	pasteMode      bool // true if user pressed 'p' to copy & paste
+	pasteMode      bool // true if user pressed the paste key to copy & paste
 	absoluteTime   bool
 	terminalWidth  int
 	terminalHeight int
@@ -35,8 +40,15 @@ type pickerModel struct {
 	watcher        *fsnotify.Watcher                 // File system watcher for auto-refresh
 	watchDirs      []string                          // Directories being watched
 	newFiles       map[string]time.Time              // Files that appeared recently (path -> time appeared)
+	keys           pickerKeyMap                      // Key bindings; nil uses defaultPickerKeys
+	pathMode       string                            // How much of each path the list shows (see pathModes)
+	sortOrder      recent.SortOrder                  // Order of files; the zero value is newest first
 }
 
+// pathModes are the ways the picker list can show a file, in the order the
+// paths key cycles through them: the name alone, with its folder, or its full path
+var pathModes = []string{"name", "folder", "full"}
+
 // pickerItem represents a file item with its display state
 type pickerItem struct {
 	file     recent.FileInfo
@@ -104,8 +116,11 @@ func (m pickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			existingFiles[f.Path] = true
 		}
 
-		// Update files list
+		// Update files list, keeping the chosen sort order
 		m.files = msg.files
+		if order := m.currentSortOrder(); order != recent.DefaultSortOrder {
+			recent.SortFiles(m.files, order)
+		}
 
 		// Mark new files that weren't in the previous list
 		if m.newFiles == nil {
@@ -183,24 +198,28 @@ func (m pickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
-		// Also handle string-based keys
-		switch msg.String() {
-		case "q":
+		// Then the rebindable keys
+		key := msg.String()
+		if key == "space" {
+			key = " "
+		}
+		switch m.keyMap().action(key) {
+		case pickerQuit:
 			m.cancelled = true
 			m.done = true
 			return m, tea.Quit
 
-		case "k":
+		case pickerUp:
 			if m.cursor > 0 {
 				m.cursor--
 			}
 
-		case "j":
+		case pickerDown:
 			if m.cursor < len(m.files)-1 {
 				m.cursor++
 			}
 
-		case " ", "space":
+		case pickerSelect:
 			// Toggle selection
 			if m.selected[m.cursor] {
 				delete(m.selected, m.cursor)
@@ -208,11 +227,17 @@ func (m pickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.selected[m.cursor] = true
 			}
 
-		case "enter":
+		case pickerCopy:
 			m.done = true
 			return m, tea.Quit
 
-		case "p":
+		case pickerPaths:
+			m.pathMode = nextPathMode(m.pathMode)
+
+		case pickerSort:
+			m.resort(m.currentSortOrder().Next())
+
+		case pickerPaste:
 			// Copy & paste mode
 			m.pasteMode = true
 			m.done = true
@@ -223,6 +248,50 @@ func (m pickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// currentSortOrder returns the order the files are shown in
+func (m pickerModel) currentSortOrder() recent.SortOrder {
+	if m.sortOrder.By == "" {
+		return recent.DefaultSortOrder
+	}
+	return m.sortOrder
+}
+
+// resort puts the files in a new order, keeping the cursor and selection on
+// the same files
+func (m *pickerModel) resort(order recent.SortOrder) {
+	var cursorPath string
+	if m.cursor >= 0 && m.cursor < len(m.files) {
+		cursorPath = m.files[m.cursor].Path
+	}
+	selectedPaths := make(map[string]bool, len(m.selected))
+	for i := range m.selected {
+		if i < len(m.files) {
+			selectedPaths[m.files[i].Path] = true
+		}
+	}
+
+	m.sortOrder = order
+	recent.SortFiles(m.files, order)
+
+	m.selected = make(map[int]bool, len(selectedPaths))
+	for i, file := range m.files {
+		if selectedPaths[file.Path] {
+			m.selected[i] = true
+		}
+		if file.Path == cursorPath {
+			m.cursor = i
+		}
+	}
+}
+
+// keyMap returns the picker's key bindings
+func (m pickerModel) keyMap() pickerKeyMap {
+	if m.keys == nil {
+		return defaultPickerKeys
+	}
+	return m.keys
+}
+
 // View renders the picker
 func (m pickerModel) View() string {
 	if m.done {
@@ -233,7 +302,9 @@ func (m pickerModel) View() string {
 
 	// Header
 	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("86"))
-	builder.WriteString(headerStyle.Render("Select files (Enter: current item, Space: multi-select, p: copy & paste)"))
+	keys := m.keyMap()
+	builder.WriteString(headerStyle.Render(fmt.Sprintf("Select files (%s: current item, %s: multi-select, %s: copy & paste)",
+		keys.label(pickerCopy), keys.label(pickerSelect), keys.label(pickerPaste))))
 	builder.WriteString("\n\n")
 
 	// Calculate viewport
@@ -293,7 +364,9 @@ func (m pickerModel) View() string {
 	// Help text
 	helpStyle := lipgloss.NewStyle().Faint(true)
 	builder.WriteString("\n")
-	builder.WriteString(helpStyle.Render("↑/↓ navigate • Enter: copy current • Space: toggle select • p: copy&paste • Esc: cancel"))
+	builder.WriteString(helpStyle.Render(fmt.Sprintf("↑/↓ navigate • %s: copy current • %s: toggle select • %s: copy&paste • %s: paths • %s: sort (%s) • Esc: cancel",
+		keys.label(pickerCopy), keys.label(pickerSelect), keys.label(pickerPaste), keys.label(pickerPaths),
+		keys.label(pickerSort), m.currentSortOrder().Description())))
 
 	return builder.String()
 }
@@ -326,34 +399,32 @@ func (m pickerModel) renderItem(item pickerItem) string {
 	if m.absoluteTime {
 		ageStr = item.file.Modified.Format("Jan 2 15:04")
 	} else {
-		age := item.file.Age()
-		if age < time.Minute {
-			ageStr = fmt.Sprintf("%ds ago", int(age.Seconds()))
-		} else if age < time.Hour {
-			ageStr = fmt.Sprintf("%dm ago", int(age.Minutes()))
-		} else if age < 24*time.Hour {
-			ageStr = fmt.Sprintf("%dh ago", int(age.Hours()))
-		} else {
-			ageStr = fmt.Sprintf("%dd ago", int(age.Hours()/24))
-		}
+		ageStr = common.FormatAge(item.file.Age())
 	}
 
 	// Get file type display
-	fileType := getFileTypeDisplay(item.file.MimeType)
+	fileType, category := getFileTypeDisplay(item.file.MimeType)
 
 	// Calculate available width for filename
 	// Account for: checkbox(3) + spaces(2) + age(~10) + file type + padding
 	availableWidth := 50 // default
 	if m.terminalWidth > 0 {
 		// Leave room for: "▶ " or "  " (2), checkbox (3), spaces (3), age (~10), file type, and some padding
-		availableWidth = m.terminalWidth - 25 - len(ageStr) - len(fileType)
+		availableWidth = m.terminalWidth - 25 - runewidth.StringWidth(ageStr) - runewidth.StringWidth(fileType)
 		if availableWidth < 20 {
 			availableWidth = 20
 		}
 	}
 
 	// Truncate filename using middle truncation
-	displayName := truncateMiddle(item.file.Name, availableWidth)
+	displayName := truncateMiddle(displayPath(item.file, m.pathMode), availableWidth)
+
+	// Color the name by file type, unless the whole line gets a highlight style
+	if !item.focused && !item.selected && !isNew && colorEnabled() {
+		if color, ok := categoryColors[category]; ok {
+			displayName = lipgloss.NewStyle().Foreground(color).Render(displayName)
+		}
+	}
 
 	// Build the line
 	line := fmt.Sprintf("%s %s [%s] (%s)",
@@ -383,6 +454,57 @@ func (m pickerModel) renderItem(item pickerItem) string {
 	return normalStyle.Render("  " + line[2:])
 }
 
+// nextPathMode returns the path mode after mode, wrapping around
+func nextPathMode(mode string) string {
+	for i, m := range pathModes {
+		if m == mode {
+			return pathModes[(i+1)%len(pathModes)]
+		}
+	}
+	return pathModes[1]
+}
+
+// displayPath returns how a file is named in the list for a path mode: its
+// name, its parent folder and name ("Desktop/invoice.pdf"), or its full path
+// with the home directory shortened to ~
+func displayPath(file recent.FileInfo, mode string) string {
+	switch mode {
+	case "folder":
+		if file.Path != "" {
+			return filepath.Join(filepath.Base(filepath.Dir(file.Path)), file.Name)
+		}
+	case "full":
+		if file.Path != "" {
+			return abbreviateHome(file.Path)
+		}
+	}
+	return file.Name
+}
+
+// abbreviateHome replaces the home directory at the start of path with ~
+func abbreviateHome(path string) string {
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return path
+	}
+	if rest, ok := strings.CutPrefix(path, home); ok && (rest == "" || strings.HasPrefix(rest, string(filepath.Separator))) {
+		return "~" + rest
+	}
+	return path
+}
+
+// formatSize formats a byte count for display (e.g. "42.3 MB")
+func formatSize(size int64) string {
+	if size < 1024 {
+		return fmt.Sprintf("%d B", size)
+	} else if size < 1024*1024 {
+		return fmt.Sprintf("%.1f KB", float64(size)/1024)
+	} else if size < 1024*1024*1024 {
+		return fmt.Sprintf("%.1f MB", float64(size)/(1024*1024))
+	}
+	return fmt.Sprintf("%.1f GB", float64(size)/(1024*1024*1024))
+}
+
 // renderDetails renders file details for the currently focused item
 func (m pickerModel) renderDetails(file recent.FileInfo) string {
 	detailStyle := lipgloss.NewStyle().
@@ -393,24 +515,14 @@ func (m pickerModel) renderDetails(file recent.FileInfo) string {
 	labelStyle := lipgloss.NewStyle().Faint(true)
 	valueStyle := lipgloss.NewStyle()
 
-	// Format size
-	var sizeStr string
-	if file.Size < 1024 {
-		sizeStr = fmt.Sprintf("%d B", file.Size)
-	} else if file.Size < 1024*1024 {
-		sizeStr = fmt.Sprintf("%.1f KB", float64(file.Size)/1024)
-	} else if file.Size < 1024*1024*1024 {
-		sizeStr = fmt.Sprintf("%.1f MB", float64(file.Size)/(1024*1024))
-	} else {
-		sizeStr = fmt.Sprintf("%.1f GB", float64(file.Size)/(1024*1024*1024))
-	}
+	sizeStr := formatSize(file.Size)
 
 	details := fmt.Sprintf(
 		"%s %s\n%s %s\n%s %s\n%s %s\n%s %s",
 		labelStyle.Render("Name:"),
 		valueStyle.Render(file.Name),
 		labelStyle.Render("Type:"),
-		valueStyle.Render(getFileTypeDisplay(file.MimeType)),
+		valueStyle.Render(fileTypeName(file.MimeType)),
 		labelStyle.Render("Size:"),
 		valueStyle.Render(sizeStr),
 		labelStyle.Render("Modified:"),
@@ -422,20 +534,21 @@ func (m pickerModel) renderDetails(file recent.FileInfo) string {
 	return detailStyle.Render(details)
 }
 
-// truncateString truncates a string to the specified length
+// truncateString truncates a string to fit in maxLen terminal cells. Widths are
+// display cells, not bytes, so CJK characters and emoji count as two.
 func truncateString(s string, maxLen int) string {
-	if len(s) <= maxLen {
+	if runewidth.StringWidth(s) <= maxLen {
 		return s
 	}
 	if maxLen <= 3 {
-		return s[:maxLen]
+		return headWidth(s, maxLen)
 	}
-	return s[:maxLen-3] + "..."
+	return headWidth(s, maxLen-3) + "..."
 }
 
 // truncateMiddle truncates a string in the middle, preserving start and end
 func truncateMiddle(s string, maxLen int) string {
-	if len(s) <= maxLen {
+	if runewidth.StringWidth(s) <= maxLen {
 		return s
 	}
 	if maxLen <= 5 {
@@ -446,11 +559,74 @@ func truncateMiddle(s string, maxLen int) string {
 	startLen := (maxLen - 3) / 2
 	endLen := maxLen - 3 - startLen
 
-	return s[:startLen] + "..." + s[len(s)-endLen:]
+	return headWidth(s, startLen) + "..." + tailWidth(s, endLen)
+}
+
+// headWidth returns the longest prefix of s that fits in width cells, never
+// splitting a rune
+func headWidth(s string, width int) string {
+	used := 0
+	for i, r := range s {
+		w := runewidth.RuneWidth(r)
+		if used+w > width {
+			return s[:i]
+		}
+		used += w
+	}
+	return s
+}
+
+// tailWidth returns the longest suffix of s that fits in width cells, never
+// splitting a rune
+func tailWidth(s string, width int) string {
+	used := 0
+	for i := len(s); i > 0; {
+		r, size := utf8.DecodeLastRuneInString(s[:i])
+		w := runewidth.RuneWidth(r)
+		if used+w > width {
+			return s[i:]
+		}
+		used += w
+		i -= size
+	}
+	return s
+}
+
+// fileCategory groups file types for color-coding the picker list
+type fileCategory string
+
+const (
+	categoryOther    fileCategory = ""
+	categoryImage    fileCategory = "image"
+	categoryMedia    fileCategory = "media"
+	categoryDocument fileCategory = "document"
+	categoryArchive  fileCategory = "archive"
+	categoryCode     fileCategory = "code"
+)
+
+// categoryColors are the name colors for each category; other files keep the default
+var categoryColors = map[fileCategory]lipgloss.Color{
+	categoryImage:    lipgloss.Color("205"), // Pink
+	categoryMedia:    lipgloss.Color("73"),  // Teal
+	categoryDocument: lipgloss.Color("39"),  // Blue
+	categoryArchive:  lipgloss.Color("208"), // Orange
+	categoryCode:     lipgloss.Color("141"), // Purple
+}
+
+// colorEnabled reports whether the picker may color file names; NO_COLOR or
+// TERM=dumb turns it off (see common.NoColor)
+func colorEnabled() bool {
+	return !common.NoColor()
 }
 
-// getFileTypeDisplay returns a human-readable file type based on MIME type
-func getFileTypeDisplay(mimeType string) string {
+// getFileTypeDisplay returns a human-readable file type based on MIME type,
+// and the category used to color the file's name
+func getFileTypeDisplay(mimeType string) (string, fileCategory) {
+	return fileTypeName(mimeType), fileTypeCategory(mimeType)
+}
+
+// fileTypeName returns a human-readable file type based on MIME type
+func fileTypeName(mimeType string) string {
 	if mimeType == "" {
 		return ""
 	}
@@ -472,8 +648,48 @@ func getFileTypeDisplay(mimeType string) string {
 	return "File"
 }
 
-// showBubbleTeaPickerWithResult shows an interactive picker and returns the full result
+// fileTypeCategory sorts a MIME type into a color category
+func fileTypeCategory(mimeType string) fileCategory {
+	mimeType, _, _ = strings.Cut(mimeType, ";")
+	mainType, subType, _ := strings.Cut(strings.TrimSpace(mimeType), "/")
+	switch {
+	case mainType == "image":
+		return categoryImage
+	case mainType == "audio", mainType == "video":
+		return categoryMedia
+	case mimeType == "text/plain", mimeType == "text/rtf", mimeType == "text/markdown":
+		return categoryDocument
+	case mainType == "text":
+		return categoryCode
+	}
+
+	switch subType {
+	case "zip", "gzip", "x-gzip", "x-tar", "x-bzip2", "x-xz", "x-7z-compressed", "x-rar-compressed", "vnd.rar", "zstd", "x-apple-diskimage":
+		return categoryArchive
+	case "pdf", "rtf", "msword", "epub+zip", "vnd.apple.pages", "vnd.apple.numbers", "vnd.apple.keynote":
+		return categoryDocument
+	case "json", "xml", "javascript", "x-javascript", "x-sh", "x-python", "sql", "x-yaml", "yaml", "toml", "wasm":
+		return categoryCode
+	}
+	if strings.HasPrefix(subType, "vnd.openxmlformats-officedocument.") || strings.HasPrefix(subType, "vnd.ms-") || strings.HasPrefix(subType, "vnd.oasis.opendocument.") {
+		return categoryDocument
+	}
+	return categoryOther
+}
+
+// showBubbleTeaPickerWithResult shows an interactive picker and returns the full
+// result. With --pick or CLIPPY_PICK the picker isn't shown and those positions
+// are the result; without either it needs a terminal.
 func showBubbleTeaPickerWithResult(files []recent.FileInfo, absoluteTime bool, refreshFunc func() ([]recent.FileInfo, error), watchDirs []string) (*recent.PickerResult, error) {
+	if pickPositions != nil {
+		return headlessPick(files, pickPositions)
+	}
+	if !common.IsInteractive() {
+		return nil, fmt.Errorf("the picker needs a terminal; use --pick or set %s to choose files without one", pickEnv)
+	}
+	if pickerKeysErr != nil {
+		return nil, fmt.Errorf("invalid picker key binding in config: %w", pickerKeysErr)
+	}
 	m := pickerModel{
 		files:        files,
 		cursor:       0,
@@ -481,6 +697,11 @@ func showBubbleTeaPickerWithResult(files []recent.FileInfo, absoluteTime bool, r
 		absoluteTime: absoluteTime,
 		refreshFunc:  refreshFunc,
 		watchDirs:    watchDirs,
+		keys:         pickerKeys,
+		pathMode:     pickerPathMode,
+	}
+	if sortOrder != recent.DefaultSortOrder {
+		m.resort(sortOrder)
 	}
 
 	// Setup file system watcher if we have directories to watch
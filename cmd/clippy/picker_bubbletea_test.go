@@ -1,9 +1,16 @@
 package main
 
 import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 	"time"
+	"unicode/utf8"
 
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mattn/go-runewidth"
 	"github.com/neilberkman/clippy/pkg/recent"
 )
 
@@ -69,3 +76,267 @@ func TestPickerModel(t *testing.T) {
 		t.Errorf("Expected truncated string length 10, got %d", len(truncated))
 	}
 }
+
+func TestTruncateMultibyte(t *testing.T) {
+	tests := []struct {
+		name   string
+		s      string
+		maxLen int
+		middle bool
+		want   string
+	}{
+		{"accented fits", "café.pdf", 8, false, "café.pdf"},
+		{"accented end", "résumé-final-draft.pdf", 10, false, "résumé-..."},
+		{"cjk end", "日本語のファイル名.txt", 10, false, "日本語..."},
+		{"emoji end", "🎉🎉🎉🎉🎉party.png", 9, false, "🎉🎉🎉..."},
+		{"cjk middle", "報告書_2024年_最終版.pdf", 14, true, "報告...版.pdf"},
+		{"accented middle", "éèêëéèêë-report-ñ.txt", 13, true, "éèêëé...ñ.txt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			if tt.middle {
+				got = truncateMiddle(tt.s, tt.maxLen)
+			} else {
+				got = truncateString(tt.s, tt.maxLen)
+			}
+			if got != tt.want {
+				t.Errorf("truncate(%q, %d) = %q, want %q", tt.s, tt.maxLen, got, tt.want)
+			}
+			if !utf8.ValidString(got) {
+				t.Errorf("truncate(%q, %d) = %q splits a rune", tt.s, tt.maxLen, got)
+			}
+			if w := runewidth.StringWidth(got); w > tt.maxLen {
+				t.Errorf("truncate(%q, %d) is %d cells wide", tt.s, tt.maxLen, w)
+			}
+		})
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	tests := []struct {
+		size int64
+		want string
+	}{
+		{512, "512 B"},
+		{1536, "1.5 KB"},
+		{44354765, "42.3 MB"},
+		{3 * 1024 * 1024 * 1024, "3.0 GB"},
+	}
+
+	for _, tt := range tests {
+		if got := formatSize(tt.size); got != tt.want {
+			t.Errorf("formatSize(%d) = %q, want %q", tt.size, got, tt.want)
+		}
+	}
+}
+
+func TestParsePickerKeys(t *testing.T) {
+	keys, err := parsePickerKeys(map[string]string{"select": "x", "copy": "enter, o", "paste": "space"})
+	if err != nil {
+		t.Fatalf("parsePickerKeys returned error: %v", err)
+	}
+	for key, want := range map[string]string{"x": pickerSelect, "o": pickerCopy, "enter": pickerCopy, " ": pickerPaste, "j": pickerDown, "p": ""} {
+		if got := keys.action(key); got != want {
+			t.Errorf("action(%q) = %q, want %q", key, got, want)
+		}
+	}
+
+	for _, settings := range []map[string]string{
+		{"select": "j"}, // j is still down
+		{"copy": "x", "quit": "x"},
+		{"jump": "g"},
+		{"quit": " , "},
+	} {
+		if _, err := parsePickerKeys(settings); err == nil {
+			t.Errorf("parsePickerKeys(%v) succeeded, want error", settings)
+		}
+	}
+}
+
+func TestPickerCustomKeys(t *testing.T) {
+	keys, err := parsePickerKeys(map[string]string{"down": "n", "select": "x", "paste": "v"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := pickerModel{
+		files:    []recent.FileInfo{{Name: "a.txt"}, {Name: "b.txt"}},
+		selected: make(map[int]bool),
+		keys:     keys,
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	m = updated.(pickerModel)
+	if m.cursor != 1 {
+		t.Errorf("cursor after rebound down key = %d, want 1", m.cursor)
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	m = updated.(pickerModel)
+	if !m.selected[1] {
+		t.Error("rebound select key didn't select the file")
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	if m = updated.(pickerModel); m.done {
+		t.Error("old paste key still works after rebinding")
+	}
+	if view := m.View(); !strings.Contains(view, "v: copy&paste") || !strings.Contains(view, "x: toggle select") {
+		t.Errorf("help line doesn't show the rebound keys:\n%s", view)
+	}
+}
+
+func TestDisplayPath(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+	file := recent.FileInfo{Name: "invoice.pdf", Path: filepath.Join(home, "Desktop", "invoice.pdf")}
+
+	tests := []struct {
+		mode string
+		want string
+	}{
+		{"", "invoice.pdf"},
+		{"name", "invoice.pdf"},
+		{"folder", filepath.Join("Desktop", "invoice.pdf")},
+		{"full", filepath.Join("~", "Desktop", "invoice.pdf")},
+	}
+	for _, tt := range tests {
+		if got := displayPath(file, tt.mode); got != tt.want {
+			t.Errorf("displayPath(%q) = %q, want %q", tt.mode, got, tt.want)
+		}
+	}
+
+	// The paths key cycles name -> folder -> full -> name
+	mode := ""
+	for _, want := range []string{"folder", "full", "name", "folder"} {
+		m := pickerModel{files: []recent.FileInfo{file}, selected: make(map[int]bool), pathMode: mode}
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("f")})
+		if mode = updated.(pickerModel).pathMode; mode != want {
+			t.Errorf("path mode after f = %q, want %q", mode, want)
+		}
+	}
+}
+
+func TestFileTypeCategory(t *testing.T) {
+	tests := map[string]fileCategory{
+		"image/png":       categoryImage,
+		"video/mp4":       categoryMedia,
+		"application/pdf": categoryDocument,
+		"application/vnd.openxmlformats-officedocument.wordprocessingml.document": categoryDocument,
+		"text/plain; charset=utf-8": categoryDocument,
+		"application/zip":           categoryArchive,
+		"application/gzip":          categoryArchive,
+		"text/x-go":                 categoryCode,
+		"application/json":          categoryCode,
+		"application/octet-stream":  categoryOther,
+		"":                          categoryOther,
+	}
+	for mimeType, want := range tests {
+		if _, got := getFileTypeDisplay(mimeType); got != want {
+			t.Errorf("category of %q = %q, want %q", mimeType, got, want)
+		}
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	if colorEnabled() {
+		t.Error("colorEnabled() = true with NO_COLOR set")
+	}
+}
+
+func TestPickerSortKey(t *testing.T) {
+	now := time.Now()
+	m := pickerModel{
+		files: []recent.FileInfo{
+			{Name: "new.txt", Path: "/tmp/new.txt", Size: 10, Modified: now},
+			{Name: "big.iso", Path: "/tmp/big.iso", Size: 9000, Modified: now.Add(-time.Hour)},
+			{Name: "mid.pdf", Path: "/tmp/mid.pdf", Size: 500, Modified: now.Add(-2 * time.Hour)},
+		},
+		selected: map[int]bool{1: true},
+		cursor:   2,
+	}
+
+	// age:desc, then name A to Z
+	for i := 0; i < 2; i++ {
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+		m = updated.(pickerModel)
+	}
+	if order := m.currentSortOrder(); order.By != recent.SortByName || order.Descending {
+		t.Fatalf("sort order after two presses = %s, want name:asc", order)
+	}
+	var names []string
+	for _, f := range m.files {
+		names = append(names, f.Name)
+	}
+	if got := strings.Join(names, ","); got != "big.iso,mid.pdf,new.txt" {
+		t.Errorf("files sorted by name = %s", got)
+	}
+	if m.files[m.cursor].Name != "mid.pdf" {
+		t.Errorf("cursor moved to %s, want it to stay on mid.pdf", m.files[m.cursor].Name)
+	}
+	if len(m.selected) != 1 || !m.selected[0] {
+		t.Errorf("selection = %v, want just big.iso (now index 0)", m.selected)
+	}
+	if !strings.Contains(m.View(), "s: sort (A to Z)") {
+		t.Error("help line doesn't show the current sort order")
+	}
+}
+
+func TestParsePick(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    []int
+		wantErr bool
+	}{
+		{"1", []int{1}, false},
+		{"3,1", []int{3, 1}, false},
+		{"2-4, 7", []int{2, 3, 4, 7}, false},
+		{"1,1,2-3,2", []int{1, 2, 3}, false},
+		{"0", nil, true},
+		{"a", nil, true},
+		{"4-2", nil, true},
+		{",", nil, true},
+		{"1-999999999", nil, true},
+		{"10001", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := parsePick(tt.spec)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parsePick(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("parsePick(%q) = %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestHeadlessPick(t *testing.T) {
+	files := []recent.FileInfo{
+		{Path: "/tmp/a.txt", Name: "a.txt"},
+		{Path: "/tmp/b.txt", Name: "b.txt"},
+		{Path: "/tmp/c.txt", Name: "c.txt"},
+	}
+
+	pickPositions = []int{3, 1}
+	defer func() { pickPositions = nil }()
+
+	result, err := showBubbleTeaPickerWithResult(files, false, nil, nil)
+	if err != nil {
+		t.Fatalf("showBubbleTeaPickerWithResult() error = %v", err)
+	}
+	var got []string
+	for _, file := range result.Files {
+		got = append(got, file.Name)
+	}
+	if want := []string{"c.txt", "a.txt"}; !slices.Equal(got, want) {
+		t.Errorf("picked %v, want %v", got, want)
+	}
+	if result.PasteMode {
+		t.Error("PasteMode = true, want false")
+	}
+
+	pickPositions = []int{4}
+	if _, err := showBubbleTeaPickerWithResult(files, false, nil, nil); err == nil {
+		t.Error("picking past the end of the list succeeded, want an error")
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/neilberkman/clippy/pkg/recent"
+)
+
+// pickEnv names the environment variable that answers the picker without
+// showing it, like --pick
+const pickEnv = "CLIPPY_PICK"
+
+// maxPickPosition bounds the positions --pick accepts. No picker list comes
+// close, and it keeps a typo like 1-999999999 from expanding into a huge list
+// before the positions are checked against the files.
+const maxPickPosition = 10000
+
+// parsePick parses a picker selection such as "1,3" or "2-4,7": 1-based
+// positions in the list the picker would show, in the order given. Repeated
+// positions are kept once.
+func parsePick(spec string) ([]int, error) {
+	var positions []int
+	seen := make(map[int]bool)
+	add := func(n int) {
+		if !seen[n] {
+			seen[n] = true
+			positions = append(positions, n)
+		}
+	}
+
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		first, last, isRange := strings.Cut(part, "-")
+		from, err := strconv.Atoi(strings.TrimSpace(first))
+		if err != nil || from < 1 {
+			return nil, fmt.Errorf("invalid pick %q: positions are numbers starting at 1, e.g. 1,3 or 2-4", part)
+		}
+		to := from
+		if isRange {
+			to, err = strconv.Atoi(strings.TrimSpace(last))
+			if err != nil || to < from {
+				return nil, fmt.Errorf("invalid pick %q: positions are numbers starting at 1, e.g. 1,3 or 2-4", part)
+			}
+		}
+		if to > maxPickPosition {
+			return nil, fmt.Errorf("invalid pick %q: positions go up to %d", part, maxPickPosition)
+		}
+		for n := from; n <= to; n++ {
+			add(n)
+		}
+	}
+	if len(positions) == 0 {
+		return nil, fmt.Errorf("invalid pick %q: no positions given", spec)
+	}
+	return positions, nil
+}
+
+// pickSpec returns the selection from --pick, or from CLIPPY_PICK if the flag
+// wasn't given; empty means show the picker
+func pickSpec() string {
+	if pickFlag != "" {
+		return pickFlag
+	}
+	return os.Getenv(pickEnv)
+}
+
+// headlessPick answers the picker with the files at the given positions,
+// sorted the way the picker would show them
+func headlessPick(files []recent.FileInfo, positions []int) (*recent.PickerResult, error) {
+	if sortOrder != recent.DefaultSortOrder {
+		recent.SortFiles(files, sortOrder)
+	}
+	result := &recent.PickerResult{}
+	for _, n := range positions {
+		if n > len(files) {
+			return nil, fmt.Errorf("cannot pick %d: only %s to choose from", n, plural(len(files), "file"))
+		}
+		file := files[n-1]
+		result.Files = append(result.Files, &file)
+	}
+	return result, nil
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Picker actions that can be rebound with picker_keys.<action> in the config.
+// The arrow keys, Esc and Ctrl-C always work as well.
+const (
+	pickerUp     = "up"
+	pickerDown   = "down"
+	pickerSelect = "select"
+	pickerCopy   = "copy"
+	pickerPaste  = "paste"
+	pickerQuit   = "quit"
+	pickerPaths  = "paths"
+	pickerSort   = "sort"
+)
+
+// pickerKeyMap maps each picker action to the keys that trigger it, as reported
+// by tea.KeyMsg.String() (e.g. "k", "enter", "ctrl+n", " " for space)
+type pickerKeyMap map[string][]string
+
+// defaultPickerKeys are the bindings used when the config doesn't change them
+var defaultPickerKeys = pickerKeyMap{
+	pickerUp:     {"k"},
+	pickerDown:   {"j"},
+	pickerSelect: {" "},
+	pickerCopy:   {"enter"},
+	pickerPaste:  {"p"},
+	pickerQuit:   {"q"},
+	pickerPaths:  {"f"},
+	pickerSort:   {"s"},
+}
+
+// parsePickerKeys builds the key map from picker_keys.<action> = key[,key...]
+// config values on top of the defaults. Keys are written as bubbletea names
+// them, with "space" for the space bar. It fails on an unknown action or a key
+// bound to two actions.
+func parsePickerKeys(settings map[string]string) (pickerKeyMap, error) {
+	keys := pickerKeyMap{}
+	for action, bound := range defaultPickerKeys {
+		keys[action] = bound
+	}
+	for action, value := range settings {
+		if _, ok := defaultPickerKeys[action]; !ok {
+			return nil, fmt.Errorf("unknown picker action picker_keys.%s (use up, down, select, copy, paste, quit, paths or sort)", action)
+		}
+		var bound []string
+		for _, key := range strings.Split(value, ",") {
+			key = strings.TrimSpace(key)
+			if key == "space" {
+				key = " "
+			}
+			if key != "" {
+				bound = append(bound, key)
+			}
+		}
+		if len(bound) == 0 {
+			return nil, fmt.Errorf("picker_keys.%s has no keys", action)
+		}
+		keys[action] = bound
+	}
+
+	// Check in a fixed order so the error names the same actions every run
+	actions := make([]string, 0, len(keys))
+	for action := range keys {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+	owner := map[string]string{}
+	for _, action := range actions {
+		for _, key := range keys[action] {
+			if other, taken := owner[key]; taken {
+				return nil, fmt.Errorf("picker key %q is bound to both %s and %s", keyLabel(key), other, action)
+			}
+			owner[key] = action
+		}
+	}
+	return keys, nil
+}
+
+// action returns the action bound to key, or "" if none is
+func (k pickerKeyMap) action(key string) string {
+	for action, bound := range k {
+		for _, b := range bound {
+			if b == key {
+				return action
+			}
+		}
+	}
+	return ""
+}
+
+// label returns how the first key for action is shown in the help line
+func (k pickerKeyMap) label(action string) string {
+	if bound := k[action]; len(bound) > 0 {
+		return keyLabel(bound[0])
+	}
+	return "?"
+}
+
+// keyLabel turns a key name into help text, e.g. " " into "Space"
+func keyLabel(key string) string {
+	switch key {
+	case " ":
+		return "Space"
+	case "enter":
+		return "Enter"
+	}
+	return key
+}
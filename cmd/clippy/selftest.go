@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/neilberkman/clippy/pkg/clipboard"
+)
+
+// savedClipboard holds what was on the clipboard before the self-test so it can be put back
+type savedClipboard struct {
+	files   []string
+	flavors []clipboard.Flavor
+}
+
+// runSelfTest copies known text and a temp file, reads each back, prints a
+// pass/fail line per round trip and restores the previous clipboard. Returns
+// false if any round trip failed.
+func runSelfTest(dir string) bool {
+	saved := saveClipboard()
+	results := []checkResult{selfTestText(), selfTestFile(dir)}
+	results = append(results, restoreClipboard(saved))
+
+	ok := true
+	for _, r := range results {
+		fmt.Printf("%s  %s: %s\n", r.Status, r.Name, r.Message)
+		if r.Status == statusFail {
+			ok = false
+		}
+	}
+	return ok
+}
+
+// selfTestText copies a unique string and reads it back with GetText
+func selfTestText() checkResult {
+	const name = "Text round trip"
+	want := fmt.Sprintf("clippy selftest %d", time.Now().UnixNano())
+	if err := clipboard.CopyText(want); err != nil {
+		return checkResult{name, statusFail, fmt.Sprintf("could not copy text: %v", err)}
+	}
+	got, ok := clipboard.GetText()
+	if !ok {
+		return checkResult{name, statusFail, "copied text but could not read any text back"}
+	}
+	if got != want {
+		return checkResult{name, statusFail, fmt.Sprintf("read back %q, want %q", got, want)}
+	}
+	return checkResult{name, statusPass, fmt.Sprintf("copied and read back %d bytes", len(want))}
+}
+
+// selfTestFile copies a reference to a new temp file and reads it back with GetFiles
+func selfTestFile(dir string) checkResult {
+	const name = "File round trip"
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	f, err := os.CreateTemp(dir, "clippy-selftest-*.txt")
+	if err != nil {
+		return checkResult{name, statusFail, fmt.Sprintf("could not create a temp file in %s: %v", dir, err)}
+	}
+	_, _ = f.WriteString("clippy selftest\n")
+	_ = f.Close()
+	defer func() {
+		_ = os.Remove(f.Name())
+	}()
+
+	if err := clipboard.CopyFile(f.Name()); err != nil {
+		return checkResult{name, statusFail, fmt.Sprintf("could not copy file reference: %v", err)}
+	}
+	files := clipboard.GetFiles()
+	if len(files) != 1 {
+		return checkResult{name, statusFail, fmt.Sprintf("copied 1 file reference but read back %d", len(files))}
+	}
+	if !samePath(files[0], f.Name()) {
+		return checkResult{name, statusFail, fmt.Sprintf("read back %s, want %s", files[0], f.Name())}
+	}
+	return checkResult{name, statusPass, "copied and read back " + f.Name()}
+}
+
+// samePath compares two paths after resolving symlinks (/var is /private/var on macOS)
+func samePath(a, b string) bool {
+	if resolved, err := filepath.EvalSymlinks(a); err == nil {
+		a = resolved
+	}
+	if resolved, err := filepath.EvalSymlinks(b); err == nil {
+		b = resolved
+	}
+	return a == b
+}
+
+// saveClipboard records the current file references, or every representation
+// of the current item
+func saveClipboard() savedClipboard {
+	if files := clipboard.GetFiles(); len(files) > 0 {
+		return savedClipboard{files: files}
+	}
+	var saved savedClipboard
+	for _, t := range clipboard.GetClipboardTypes() {
+		if data, ok := clipboard.GetClipboardDataForType(t); ok {
+			saved.flavors = append(saved.flavors, clipboard.Flavor{Type: t, Data: data})
+		}
+	}
+	return saved
+}
+
+// restoreClipboard puts saved content back, or clears the clipboard if it was empty
+func restoreClipboard(saved savedClipboard) checkResult {
+	const name = "Restore clipboard"
+	var err error
+	switch {
+	case len(saved.files) > 0:
+		err = clipboard.CopyFiles(saved.files)
+	case len(saved.flavors) > 0:
+		err = clipboard.CopyFlavors(saved.flavors)
+	default:
+		err = clipboard.Clear()
+	}
+	if err != nil {
+		return checkResult{name, statusWarn, fmt.Sprintf("could not restore the previous clipboard: %v", err)}
+	}
+	switch {
+	case len(saved.files) > 0:
+		return checkResult{name, statusPass, fmt.Sprintf("restored %d file references", len(saved.files))}
+	case len(saved.flavors) > 0:
+		return checkResult{name, statusPass, fmt.Sprintf("restored %d representations", len(saved.flavors))}
+	default:
+		return checkResult{name, statusPass, "clipboard was empty and has been cleared"}
+	}
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/neilberkman/clippy/pkg/clipboard"
+)
+
+func TestRunSelfTest(t *testing.T) {
+	mem := clipboard.NewMemoryManager()
+	previous := clipboard.SetManager(mem)
+	t.Cleanup(func() { clipboard.SetManager(previous) })
+
+	if err := mem.CopyText("before"); err != nil {
+		t.Fatalf("CopyText returned error: %v", err)
+	}
+	if !runSelfTest(t.TempDir()) {
+		t.Error("runSelfTest reported a failure on the memory backend")
+	}
+	if got, ok := mem.GetText(); !ok || got != "before" {
+		t.Errorf("clipboard after selftest = %q, %v, want the previous text restored", got, ok)
+	}
+}
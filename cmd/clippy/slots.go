@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/neilberkman/clippy"
+)
+
+// runSaveSlot saves the clipboard under name
+func runSaveSlot(name string) error {
+	snapshot, err := clippy.SaveSlot(name)
+	if err != nil {
+		return fmt.Errorf("could not save slot %s: %w", name, err)
+	}
+	reportSuccess("✅ Saved %s to slot %s", describeSnapshot(snapshot), name)
+	return nil
+}
+
+// runRestoreSlot puts the clipboard saved under name back
+func runRestoreSlot(name string) error {
+	snapshot, err := clippy.RestoreSlot(name)
+	if err != nil {
+		return fmt.Errorf("could not restore slot %s: %w", name, err)
+	}
+	reportSuccess("✅ Restored %s from slot %s", describeSnapshot(snapshot), name)
+	runDataHook("snapshot", snapshotSize(snapshot))
+	return nil
+}
+
+// runSwapSlot exchanges the clipboard with the slot
+func runSwapSlot(name string) error {
+	snapshot, err := clippy.SwapSlot(name)
+	if err != nil {
+		return fmt.Errorf("could not swap with slot %s: %w", name, err)
+	}
+	reportSuccess("✅ Swapped: restored %s from slot %s, which now holds the previous clipboard", describeSnapshot(snapshot), name)
+	runDataHook("snapshot", snapshotSize(snapshot))
+	return nil
+}
+
+// printSlots lists the saved slots by name
+func printSlots() error {
+	slots, err := clippy.Slots()
+	if err != nil {
+		return err
+	}
+	if len(slots) == 0 {
+		fmt.Println("No saved slots. Save the clipboard with: clippy save NAME")
+		return nil
+	}
+	width := 0
+	for _, slot := range slots {
+		width = max(width, len(slot.Name))
+	}
+	for _, slot := range slots {
+		fmt.Printf("%-*s  %s\n", width, slot.Name, describeSnapshot(slot.Snapshot))
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/neilberkman/clippy"
+	"github.com/neilberkman/clippy/pkg/workspace"
+)
+
+// textPreference is what kind of text an app is known to paste well
+type textPreference int
+
+const (
+	preferDetected textPreference = iota // Unknown app: keep the detected type
+	preferPlain                          // Terminals and code editors: plain text only
+	preferRich                           // Rich editors: keep HTML, RTF and friends
+)
+
+// appTextPreferences maps bundle identifiers to what the app pastes well. It's a
+// short list of common apps, not a full catalog; anything missing keeps the
+// detected type. Entries ending in "." match every bundle ID with that prefix.
+var appTextPreferences = map[string]textPreference{
+	"com.apple.Terminal":        preferPlain,
+	"com.googlecode.iterm2":     preferPlain,
+	"com.mitchellh.ghostty":     preferPlain,
+	"net.kovidgoyal.kitty":      preferPlain,
+	"io.alacritty":              preferPlain,
+	"com.github.wez.wezterm":    preferPlain,
+	"dev.warp.Warp-Stable":      preferPlain,
+	"com.microsoft.VSCode":      preferPlain,
+	"com.sublimetext.4":         preferPlain,
+	"com.apple.dt.Xcode":        preferPlain,
+	"dev.zed.Zed":               preferPlain,
+	"org.vim.MacVim":            preferPlain,
+	"com.jetbrains.":            preferPlain,
+	"com.apple.mail":            preferRich,
+	"com.apple.Notes":           preferRich,
+	"com.apple.TextEdit":        preferRich,
+	"com.apple.iWork.Pages":     preferRich,
+	"com.microsoft.Word":        preferRich,
+	"com.microsoft.Outlook":     preferRich,
+	"com.google.Chrome":         preferRich,
+	"com.apple.Safari":          preferRich,
+	"notion.id":                 preferRich,
+	"com.tinyspeck.slackmacgap": preferRich,
+}
+
+// textPreferenceFor looks up an app by exact bundle ID, then by prefix entry
+func textPreferenceFor(bundleID string) textPreference {
+	if pref, ok := appTextPreferences[bundleID]; ok {
+		return pref
+	}
+	for prefix, pref := range appTextPreferences {
+		if strings.HasSuffix(prefix, ".") && strings.HasPrefix(bundleID, prefix) {
+			return pref
+		}
+	}
+	return preferDetected
+}
+
+// applySmartFlavor copies text as plain text when the frontmost app is known to
+// want it. It's a guess: the app in front now is usually, but not always, where
+// the paste goes, and run from a terminal it is the terminal itself.
+func applySmartFlavor() {
+	app, err := workspace.FrontmostApp()
+	if err != nil {
+		logger.Debug("Smart flavor: could not find the frontmost app: %v", err)
+		return
+	}
+	switch textPreferenceFor(app.BundleID) {
+	case preferPlain:
+		logger.Debug("Smart flavor: %s (%s) is in front, copying text as plain text", app.Name, app.BundleID)
+		clippy.SetPreferPlainText(true)
+	case preferRich:
+		logger.Debug("Smart flavor: %s (%s) is in front, keeping rich text types", app.Name, app.BundleID)
+	default:
+		logger.Debug("Smart flavor: no preference for %s (%s), keeping detected types", app.Name, app.BundleID)
+	}
+}
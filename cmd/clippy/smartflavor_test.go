@@ -0,0 +1,22 @@
+package main
+
+import "testing"
+
+func TestTextPreferenceFor(t *testing.T) {
+	tests := []struct {
+		bundleID string
+		want     textPreference
+	}{
+		{"com.apple.Terminal", preferPlain},
+		{"com.jetbrains.goland", preferPlain},
+		{"com.apple.mail", preferRich},
+		{"com.example.Unknown", preferDetected},
+		{"com.jetbrains", preferDetected},
+		{"", preferDetected},
+	}
+	for _, tt := range tests {
+		if got := textPreferenceFor(tt.bundleID); got != tt.want {
+			t.Errorf("textPreferenceFor(%q) = %v, want %v", tt.bundleID, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/neilberkman/clippy"
+	"github.com/neilberkman/clippy/internal/fsutil"
+)
+
+// largeFlavorBytes is the size above which export and import warn about a flavor
+const largeFlavorBytes = 10 << 20
+
+// runExport writes a snapshot of the clipboard to path, or to stdout if path is
+// empty or "-"
+func runExport(path string) error {
+	if path == "" || path == "-" {
+		snapshot, err := clippy.ExportClipboard(os.Stdout)
+		if err != nil {
+			return err
+		}
+		warnLargeFlavors(snapshot)
+		return nil
+	}
+
+	var buf bytes.Buffer
+	snapshot, err := clippy.ExportClipboard(&buf)
+	if err != nil {
+		return err
+	}
+	if err := fsutil.WriteFileAtomic(path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("could not write %s: %w", path, err)
+	}
+	warnLargeFlavors(snapshot)
+	reportSuccess(fmt.Sprintf("✅ Exported %d representations to %s", len(snapshot.Flavors), path))
+	return nil
+}
+
+// runImport restores a snapshot from path, or from stdin if path is empty or "-"
+func runImport(path string) error {
+	var r io.Reader = os.Stdin
+	name := "stdin"
+	if path != "" && path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("could not open snapshot: %w", err)
+		}
+		defer func() {
+			_ = f.Close()
+		}()
+		r, name = f, path
+	}
+
+	snapshot, err := clippy.ImportClipboard(r)
+	if err != nil {
+		return fmt.Errorf("could not import %s: %w", name, err)
+	}
+	warnLargeFlavors(snapshot)
+	reportSuccess(fmt.Sprintf("✅ Imported %d representations from %s", len(snapshot.Flavors), name))
+	runDataHook("snapshot", snapshotSize(snapshot))
+	return nil
+}
+
+// warnLargeFlavors warns about each flavor over largeFlavorBytes, since those
+// make snapshot files slow to move around and apps slow to paste
+func warnLargeFlavors(snapshot *clippy.Snapshot) {
+	for _, flavor := range snapshot.Flavors {
+		if len(flavor.Data) > largeFlavorBytes {
+			logger.Warn("%s is %s", flavor.Type, formatSize(int64(len(flavor.Data))))
+		}
+	}
+}
+
+// snapshotSize returns the total bytes across a snapshot's flavors
+func snapshotSize(snapshot *clippy.Snapshot) int64 {
+	var size int64
+	for _, flavor := range snapshot.Flavors {
+		size += int64(len(flavor.Data))
+	}
+	return size
+}
+
+// snapshotPath returns the file argument of export or import, or "" for stdin/stdout
+func snapshotPath(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	return args[0]
+}
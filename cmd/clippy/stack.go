@@ -0,0 +1,84 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/neilberkman/clippy"
+	"github.com/neilberkman/clippy/cmd/internal/common"
+	"github.com/neilberkman/clippy/pkg/clipboard"
+)
+
+// stackPreviewLength is how many characters of text clippy stack list shows
+const stackPreviewLength = 50
+
+// runPush saves the clipboard on the stack
+func runPush() error {
+	snapshot, err := clippy.PushClipboard()
+	if err != nil {
+		return fmt.Errorf("could not push clipboard: %w", err)
+	}
+	reportSuccess("✅ Pushed %s", describeSnapshot(snapshot))
+	return nil
+}
+
+// runPop restores the top of the stack
+func runPop() error {
+	snapshot, err := clippy.PopClipboard()
+	if err != nil {
+		if errors.Is(err, clippy.ErrStackEmpty) {
+			return err
+		}
+		return fmt.Errorf("could not pop clipboard: %w", err)
+	}
+	reportSuccess("✅ Restored %s", describeSnapshot(snapshot))
+	runDataHook("snapshot", snapshotSize(snapshot))
+	return nil
+}
+
+// printStack lists the stack, top first
+func printStack() error {
+	entries, err := clippy.ClipboardStack()
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		fmt.Println("The clipboard stack is empty. Save the clipboard with: clippy push")
+		return nil
+	}
+	for i, entry := range entries {
+		fmt.Printf("%2d  %-8s  %s\n", i+1, common.FormatAge(entry.Snapshot.Age()), describeSnapshot(entry.Snapshot))
+	}
+	return nil
+}
+
+// describeSnapshot summarizes a snapshot in one line: its files, or a preview
+// of its text, and how many representations it holds
+func describeSnapshot(snapshot *clippy.Snapshot) string {
+	summary := fmt.Sprintf("%s, %s", plural(len(snapshot.Flavors), "representation"), formatSize(snapshotSize(snapshot)))
+	if len(snapshot.Files) > 0 {
+		names := make([]string, len(snapshot.Files))
+		for i, file := range snapshot.Files {
+			names[i] = filepath.Base(file)
+		}
+		return fmt.Sprintf("%s: %s (%s)", plural(len(names), "file"), strings.Join(names, ", "), summary)
+	}
+	for _, flavor := range snapshot.Flavors {
+		if flavor.Type == clipboard.PlainTextType && utf8.Valid(flavor.Data) {
+			return fmt.Sprintf("%q (%s)", previewText(string(flavor.Data)), summary)
+		}
+	}
+	return summary
+}
+
+// previewText collapses whitespace and shortens text for a one-line listing
+func previewText(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if utf8.RuneCountInString(text) <= stackPreviewLength {
+		return text
+	}
+	return string([]rune(text)[:stackPreviewLength-1]) + "…"
+}
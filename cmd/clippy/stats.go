@@ -0,0 +1,44 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// statsReport summarizes clipboard usage for `clippy stats`. clippy doesn't keep
+// a history of copies yet, so History is false and the counts are zero; the
+// fields are the ones a history log would fill in, so scripts reading --json
+// won't have to change when it does.
+type statsReport struct {
+	History      bool           `json:"history"`
+	Message      string         `json:"message,omitempty"`
+	Copies       int            `json:"copies"`
+	ByType       map[string]int `json:"by_type"`
+	AverageBytes int64          `json:"average_bytes"`
+	CopiesPerDay float64        `json:"copies_per_day"`
+}
+
+// noHistoryMessage explains why there are no statistics
+const noHistoryMessage = "no clipboard history is available: clippy doesn't record past copies, so there is nothing to summarize"
+
+// buildStatsReport collects the statistics. With no history to read it
+// reports that instead.
+func buildStatsReport() statsReport {
+	return statsReport{
+		Message: noHistoryMessage,
+		ByType:  map[string]int{},
+	}
+}
+
+// printStats writes the report as text, or as indented JSON if asJSON is set
+func printStats(w io.Writer, report statsReport, asJSON bool) error {
+	if asJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+
+	_, err := fmt.Fprintf(w, "No statistics: %s.\n", report.Message)
+	return err
+}
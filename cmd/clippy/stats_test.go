@@ -0,0 +1,33 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestPrintStatsWithoutHistory(t *testing.T) {
+	var text bytes.Buffer
+	if err := printStats(&text, buildStatsReport(), false); err != nil {
+		t.Fatalf("printStats returned error: %v", err)
+	}
+	if !strings.Contains(text.String(), "no clipboard history") {
+		t.Errorf("text output = %q, want it to say there is no history", text.String())
+	}
+
+	var out bytes.Buffer
+	if err := printStats(&out, buildStatsReport(), true); err != nil {
+		t.Fatalf("printStats returned error: %v", err)
+	}
+	var report statsReport
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("JSON output %q doesn't parse: %v", out.String(), err)
+	}
+	if report.History || report.Copies != 0 || report.Message == "" {
+		t.Errorf("JSON report = %+v, want no history with an explanation", report)
+	}
+	if !strings.Contains(out.String(), `"by_type": {}`) {
+		t.Errorf("JSON output = %s, want an empty by_type object", out.String())
+	}
+}
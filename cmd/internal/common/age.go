@@ -0,0 +1,20 @@
+package common
+
+import (
+	"fmt"
+	"time"
+)
+
+// FormatAge renders a duration in its largest whole unit, e.g. "42s ago" or "3d ago"
+func FormatAge(age time.Duration) string {
+	switch {
+	case age < time.Minute:
+		return fmt.Sprintf("%ds ago", int(age.Seconds()))
+	case age < time.Hour:
+		return fmt.Sprintf("%dm ago", int(age.Minutes()))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(age.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(age.Hours()/24))
+	}
+}
@@ -0,0 +1,53 @@
+package common
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ConfigFilePath returns the path of ~/.clippy.conf, or "" if there is no home directory
+func ConfigFilePath() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(homeDir, ".clippy.conf")
+}
+
+// ReadConfig parses the key = value lines of a config file, skipping blank lines
+// and # comments. When a key appears twice the last value wins.
+func ReadConfig(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := map[string]string{}
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		settings[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+	return settings, scanner.Err()
+}
+
+// TotalSize returns the combined size of the given files, skipping any that can't be read
+func TotalSize(paths []string) int64 {
+	var total int64
+	for _, path := range paths {
+		if info, err := os.Stat(path); err == nil {
+			total += info.Size()
+		}
+	}
+	return total
+}
@@ -0,0 +1,31 @@
+package common
+
+import (
+	"errors"
+
+	"github.com/neilberkman/clippy"
+	"github.com/neilberkman/clippy/pkg/clipboard"
+)
+
+// Exit codes let scripts tell failures apart. 0 is success.
+const (
+	ExitError        = 1 // Anything not covered below
+	ExitUsage        = 2 // No input, or flags that can't be used together
+	ExitNoFiles      = 3 // A search (-r, -i, -f, --screenshot) found nothing
+	ExitFileNotFound = 4 // A file named on the command line doesn't exist
+	ExitClipboard    = 5 // The clipboard couldn't be written (or its lock wasn't released in time)
+)
+
+// ExitCode maps an error from the clippy library to its exit code category
+func ExitCode(err error) int {
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, clippy.ErrFileNotFound):
+		return ExitFileNotFound
+	case errors.Is(err, clipboard.ErrWriteFailed), errors.Is(err, clipboard.ErrTimeout), errors.Is(err, clippy.ErrLockTimeout):
+		return ExitClipboard
+	default:
+		return ExitError
+	}
+}
@@ -0,0 +1,32 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/neilberkman/clippy"
+	"github.com/neilberkman/clippy/pkg/clipboard"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"success", nil, 0},
+		{"missing file", fmt.Errorf("%w: /tmp/nope", clippy.ErrFileNotFound), ExitFileNotFound},
+		{"wrapped clipboard failure", fmt.Errorf("could not copy: %w", clipboard.ErrWriteFailed), ExitClipboard},
+		{"clipboard timeout", clipboard.ErrTimeout, ExitClipboard},
+		{"anything else", errors.New("boom"), ExitError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -2,8 +2,9 @@ package common
 
 import "github.com/spf13/cobra"
 
-// AddCommonFlags adds verbose and debug flags that are shared by all commands
-func AddCommonFlags(cmd *cobra.Command, verbose, debug *bool) {
+// AddCommonFlags adds verbose, debug and quiet flags that are shared by all commands
+func AddCommonFlags(cmd *cobra.Command, verbose, debug, quiet *bool) {
 	cmd.PersistentFlags().BoolVarP(verbose, "verbose", "v", false, "Enable verbose output")
 	cmd.PersistentFlags().BoolVar(debug, "debug", false, "Enable debug output (includes technical details)")
+	cmd.PersistentFlags().BoolVarP(quiet, "quiet", "q", false, "Print nothing, not even errors; check the exit status instead (overrides -v and --debug)")
 }
@@ -0,0 +1,67 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/neilberkman/clippy/internal/log"
+)
+
+// HookTimeout is how long a post-copy or post-paste hook may run before it's killed
+const HookTimeout = 10 * time.Second
+
+// HookEvent describes a completed copy or paste. It reaches the hook as
+// CLIPPY_TYPE, CLIPPY_FILES (newline-separated) and CLIPPY_BYTES.
+type HookEvent struct {
+	Type  string // "text", "files" or "data"
+	Files []string
+	Bytes int64
+}
+
+// Environ returns the environment variables describing the event
+func (e HookEvent) Environ() []string {
+	return []string{
+		"CLIPPY_TYPE=" + e.Type,
+		"CLIPPY_FILES=" + strings.Join(e.Files, "\n"),
+		fmt.Sprintf("CLIPPY_BYTES=%d", e.Bytes),
+	}
+}
+
+var hooks sync.WaitGroup
+
+// RunHook starts command through /bin/sh in the background with the event in
+// its environment. Failures and timeouts are logged, never returned: a hook
+// can't fail the copy it reports on. Call WaitForHooks before exiting.
+func RunHook(command string, event HookEvent, logger *log.Logger) {
+	if command == "" {
+		return
+	}
+	hooks.Add(1)
+	go func() {
+		defer hooks.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), HookTimeout)
+		defer cancel()
+
+		cmd := exec.CommandContext(ctx, "/bin/sh", "-c", command)
+		cmd.Env = append(os.Environ(), event.Environ()...)
+		output, err := cmd.CombinedOutput()
+		switch {
+		case ctx.Err() == context.DeadlineExceeded:
+			logger.Warn("Hook %q timed out after %v", command, HookTimeout)
+		case err != nil:
+			logger.Warn("Hook %q failed: %v %s", command, err, strings.TrimSpace(string(output)))
+		default:
+			logger.Debug("Hook %q finished", command)
+		}
+	}()
+}
+
+// WaitForHooks blocks until every hook started with RunHook has finished or timed out
+func WaitForHooks() {
+	hooks.Wait()
+}
@@ -0,0 +1,33 @@
+package common
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/neilberkman/clippy/internal/log"
+)
+
+func TestRunHook(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "env.txt")
+	var errOut bytes.Buffer
+	logger := log.New(log.Config{ErrOutput: &errOut})
+
+	RunHook(`printf '%s|%s|%s' "$CLIPPY_TYPE" "$CLIPPY_FILES" "$CLIPPY_BYTES" > "`+out+`"`,
+		HookEvent{Type: "files", Files: []string{"/a", "/b"}, Bytes: 42}, logger)
+	RunHook("exit 3", HookEvent{Type: "text"}, logger)
+	WaitForHooks()
+
+	got, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("hook didn't run: %v", err)
+	}
+	if want := "files|/a\n/b|42"; string(got) != want {
+		t.Errorf("hook saw %q, want %q", got, want)
+	}
+	if !strings.Contains(errOut.String(), "failed") {
+		t.Errorf("failing hook wasn't logged, got %q", errOut.String())
+	}
+}
@@ -0,0 +1,21 @@
+package common
+
+import (
+	"time"
+
+	"github.com/neilberkman/clippy"
+)
+
+// UseClipboardLock turns on the cross-process clipboard lock when the config
+// sets lock = true. The lock file goes in temp_dir (or the system temp dir) and
+// lock_timeout (e.g. 30s) sets how long to wait for another run.
+func UseClipboardLock(settings map[string]string) {
+	if value := settings["lock"]; value != "true" && value != "1" {
+		return
+	}
+	opts := clippy.LockOptions{Path: clippy.DefaultLockPath(settings["temp_dir"])}
+	if timeout, err := time.ParseDuration(settings["lock_timeout"]); err == nil {
+		opts.Timeout = timeout
+	}
+	clippy.SetLockOptions(opts)
+}
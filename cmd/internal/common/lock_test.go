@@ -0,0 +1,25 @@
+package common
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/neilberkman/clippy"
+)
+
+func TestUseClipboardLock(t *testing.T) {
+	defer clippy.SetLockOptions(clippy.LockOptions{})
+	dir := t.TempDir()
+
+	UseClipboardLock(map[string]string{"lock_timeout": "5s"})
+	if opts := clippy.SetLockOptions(clippy.LockOptions{}); opts.Path != "" {
+		t.Errorf("lock enabled without lock = true: %+v", opts)
+	}
+
+	UseClipboardLock(map[string]string{"lock": "true", "lock_timeout": "5s", "temp_dir": dir})
+	want := clippy.LockOptions{Path: filepath.Join(dir, "clippy.lock"), Timeout: 5 * time.Second}
+	if opts := clippy.SetLockOptions(clippy.LockOptions{}); opts != want {
+		t.Errorf("lock options = %+v, want %+v", opts, want)
+	}
+}
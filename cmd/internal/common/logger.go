@@ -1,13 +1,51 @@
 package common
 
 import (
+	"io"
+	"os"
+
 	"github.com/neilberkman/clippy/internal/log"
 )
 
+// LoggerOptions configures where and how a logger writes. The zero value keeps
+// the CLI behavior: text lines on stdout and stderr.
+type LoggerOptions struct {
+	Output    io.Writer // Verbose and debug output (nil = stdout)
+	ErrOutput io.Writer // Errors and warnings (nil = stderr)
+	JSON      bool      // One JSON object per line instead of plain text
+	Quiet     bool      // Discard everything, including errors (overrides verbose and debug)
+	OnError   func()    // Called after every logged error, even in quiet mode
+}
+
 // SetupLogger creates a new logger with the given verbose and debug settings
 func SetupLogger(verbose, debug bool) *log.Logger {
+	return SetupLoggerWithOptions(verbose, debug, LoggerOptions{})
+}
+
+// SetupLoggerWithOptions is like SetupLogger but can redirect output or switch to JSON.
+// Status emoji are dropped unless the output is a terminal that allows styling.
+func SetupLoggerWithOptions(verbose, debug bool, opts LoggerOptions) *log.Logger {
+	plain := true
+	if f, ok := opts.Output.(*os.File); ok {
+		plain = !StyleEnabled(f)
+	} else if opts.Output == nil {
+		plain = !StyleEnabled(os.Stdout)
+	}
+	format := log.FormatText
+	if opts.JSON {
+		format = log.FormatJSON
+	}
+	if opts.Quiet {
+		verbose, debug = false, false
+		opts.Output, opts.ErrOutput = io.Discard, io.Discard
+	}
 	return log.New(log.Config{
-		Verbose: verbose || debug,
-		Debug:   debug,
+		Verbose:   verbose || debug,
+		Debug:     debug,
+		Output:    opts.Output,
+		ErrOutput: opts.ErrOutput,
+		Format:    format,
+		OnError:   opts.OnError,
+		Plain:     plain,
 	})
 }
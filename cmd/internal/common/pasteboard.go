@@ -0,0 +1,20 @@
+package common
+
+import (
+	"fmt"
+
+	"github.com/neilberkman/clippy/pkg/clipboard"
+)
+
+// UsePasteboard points the clipboard backend at the named macOS pasteboard.
+// An empty name keeps the general pasteboard.
+func UsePasteboard(name string) error {
+	if name == "" {
+		return nil
+	}
+	if backend := clipboard.BackendName(); backend != "system" {
+		return fmt.Errorf("--pasteboard needs the system clipboard backend, but the %s backend is active", backend)
+	}
+	clipboard.SetManager(clipboard.NewPasteboardManager(name))
+	return nil
+}
@@ -0,0 +1,21 @@
+package common
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/neilberkman/clippy/pkg/clipboard"
+)
+
+// RequireMacOS exits with an error outside macOS, where there is no system
+// clipboard, unless the memory backend (CLIPPY_BACKEND=memory) is selected.
+// name is the tool name used in the message, e.g. "Clippy".
+func RequireMacOS(name string) {
+	if runtime.GOOS == "darwin" || clipboard.BackendName() == "memory" {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Error: %s only works on macOS (detected: %s)\n", name, runtime.GOOS)
+	fmt.Fprintf(os.Stderr, "%s uses macOS-specific clipboard APIs and frameworks; set %s=memory for an in-process clipboard.\n", name, clipboard.BackendEnvVar)
+	os.Exit(1)
+}
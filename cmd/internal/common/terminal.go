@@ -0,0 +1,47 @@
+package common
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// IsTerminal reports whether f is attached to a terminal
+func IsTerminal(f *os.File) bool {
+	stat, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return stat.Mode()&os.ModeCharDevice != 0
+}
+
+// NoColor reports whether the environment asks for unstyled output: NO_COLOR
+// (https://no-color.org) is set to anything, or TERM is "dumb"
+func NoColor() bool {
+	return os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb"
+}
+
+// StyleEnabled reports whether output to f may use ANSI styling and emoji. It
+// is off when NoColor says so or f isn't a terminal (piped, redirected, logged).
+func StyleEnabled(f *os.File) bool {
+	return !NoColor() && IsTerminal(f)
+}
+
+// IsInteractive reports whether both stdin and stdout are terminals, so a prompt
+// can be shown and answered
+func IsInteractive() bool {
+	return IsTerminal(os.Stdin) && IsTerminal(os.Stdout)
+}
+
+// Confirm prints a yes/no question and reads the answer from stdin.
+// Anything other than "y" or "yes" counts as no.
+func Confirm(question string) bool {
+	fmt.Printf("%s [y/N] ", question)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
@@ -0,0 +1,28 @@
+package common
+
+import (
+	"strings"
+
+	"github.com/neilberkman/clippy"
+)
+
+// UseTypeOverrides loads the user's type table from the config: each
+// mime_uti.<MIME type> = <UTI> line maps a MIME type to a UTI, and text_types
+// lists extra MIME types (comma-separated) to treat as text.
+func UseTypeOverrides(settings map[string]string) {
+	var overrides clippy.TypeOverrides
+	for key, value := range settings {
+		if mime, ok := strings.CutPrefix(key, "mime_uti."); ok && mime != "" && value != "" {
+			if overrides.UTIs == nil {
+				overrides.UTIs = make(map[string]string)
+			}
+			overrides.UTIs[mime] = value
+		}
+	}
+	for _, mime := range strings.Split(settings["text_types"], ",") {
+		if mime = strings.TrimSpace(mime); mime != "" {
+			overrides.TextTypes = append(overrides.TextTypes, mime)
+		}
+	}
+	clippy.SetTypeOverrides(overrides)
+}
@@ -0,0 +1,26 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/neilberkman/clippy"
+)
+
+func TestUseTypeOverrides(t *testing.T) {
+	defer clippy.SetTypeOverrides(clippy.TypeOverrides{})
+
+	UseTypeOverrides(map[string]string{
+		"mime_uti.application/x-myformat": "com.me.myformat",
+		"mime_uti.":                       "ignored",
+		"text_types":                      "application/x-myformat, application/x-other,",
+		"verbose":                         "true",
+	})
+
+	overrides := clippy.SetTypeOverrides(clippy.TypeOverrides{})
+	if len(overrides.UTIs) != 1 || overrides.UTIs["application/x-myformat"] != "com.me.myformat" {
+		t.Errorf("UTIs = %v, want only application/x-myformat", overrides.UTIs)
+	}
+	if len(overrides.TextTypes) != 2 || overrides.TextTypes[0] != "application/x-myformat" || overrides.TextTypes[1] != "application/x-other" {
+		t.Errorf("TextTypes = %v, want [application/x-myformat application/x-other]", overrides.TextTypes)
+	}
+}
@@ -6,32 +6,43 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"runtime"
+	"time"
 
 	"github.com/neilberkman/clippy"
 	"github.com/neilberkman/clippy/cmd/internal/common"
 	"github.com/neilberkman/clippy/internal/log"
 	"github.com/neilberkman/clippy/pkg/clipboard"
+	"github.com/neilberkman/clippy/pkg/notify"
 	"github.com/spf13/cobra"
 )
 
 var (
 	verbose        bool
 	debug          bool
+	quiet          bool
 	preserveFormat bool
 	inspect        bool
 	plain          bool
+	flavor         string
+	markdown       bool
+	urlOnly        bool
 	force          bool
+	preserveTree   bool
+	preserveTimes  bool
+	preserveXattrs bool
+	stripMetadata  bool
+	maxWidth       int
+	move           bool
+	verify         bool
+	pasteboard     string
+	notifyFlag     bool
+	bellFlag       bool
 	logger         *log.Logger
 )
 
 func main() {
-	// Pasty only works on macOS
-	if runtime.GOOS != "darwin" {
-		fmt.Fprintf(os.Stderr, "Error: Pasty only works on macOS (detected: %s)\n", runtime.GOOS)
-		fmt.Fprintln(os.Stderr, "Pasty uses macOS-specific clipboard APIs and frameworks.")
-		os.Exit(1)
-	}
+	// Pasty only works on macOS, or anywhere with the memory backend
+	common.RequireMacOS("Pasty")
 
 	var rootCmd = &cobra.Command{
 		Use:   "pasty [destination]",
@@ -56,6 +67,21 @@ Examples:
   # Force plain text (strip formatting)
   pasty --plain notes.txt
 
+  # Save exactly one representation (see --inspect for what's there)
+  pasty --type public.html page.html
+
+  # Save copied web content as Markdown
+  pasty --markdown notes.md
+
+  # Print just the copied link
+  pasty --url
+
+  # Move copied files here instead of copying them (like Finder's cut and paste)
+  pasty --move ~/Projects/
+
+  # Paste files from nested folders, keeping their layout
+  pasty --preserve-structure backup/
+
 Description:
   Pasty intelligently pastes clipboard content:
   - Text content is written directly
@@ -64,8 +90,34 @@ Description:
   - If no destination specified, outputs to stdout`,
 		Version: fmt.Sprintf("%s (%s) built on %s", common.Version, common.Commit, common.Date),
 		Run: func(cmd *cobra.Command, args []string) {
-			// Initialize logger
-			logger = common.SetupLogger(verbose, debug)
+			// Initialize logger; --quiet wins over -v and --debug
+			if quiet {
+				verbose, debug = false, false
+			}
+			// pasty shares clippy's config file for hooks, notifications, sounds, locking, types and the pasteboard
+			settings, _ := common.ReadConfig(common.ConfigFilePath())
+			if value := settings["notify"]; value == "true" || value == "1" {
+				notifyFlag = true
+			}
+			if value := settings["bell"]; value == "true" || value == "1" {
+				bellFlag = true
+			}
+			if value := settings["pasteboard"]; value != "" && !cmd.Flags().Changed("pasteboard") {
+				pasteboard = value
+			}
+			common.UseClipboardLock(settings)
+			common.UseTypeOverrides(settings)
+
+			loggerOpts := common.LoggerOptions{Quiet: quiet}
+			if bellFlag {
+				loggerOpts.OnError = func() { notify.Beep(false) }
+			}
+			logger = common.SetupLoggerWithOptions(verbose, debug, loggerOpts)
+
+			if err := common.UsePasteboard(pasteboard); err != nil {
+				logger.Error("%v", err)
+				os.Exit(common.ExitUsage)
+			}
 
 			// Handle --inspect flag
 			if inspect {
@@ -73,6 +125,21 @@ Description:
 				return
 			}
 
+			// Handle --url flag (just the link, for scripts)
+			if urlOnly {
+				if len(args) > 0 {
+					logger.Error("--url prints the URL to stdout; it doesn't take a destination")
+					os.Exit(common.ExitUsage)
+				}
+				link, err := clippy.ClipboardURL()
+				if err != nil {
+					logger.Error("%v", err)
+					os.Exit(common.ExitCode(err))
+				}
+				fmt.Println(link)
+				return
+			}
+
 			// Get destination from args
 			var destination string
 			if len(args) > 0 {
@@ -83,25 +150,71 @@ Description:
 			var result *clippy.PasteResult
 			var err error
 
-			if destination == "" {
+			if flavor != "" && (move || plain || preserveTree) {
+				logger.Error("--type pastes one clipboard representation; it can't be combined with --move, --plain or --preserve-structure")
+				os.Exit(common.ExitUsage)
+			}
+			if maxWidth < 0 {
+				logger.Error("--max-width must be a positive number of pixels")
+				os.Exit(common.ExitUsage)
+			}
+			if markdown && (flavor != "" || move || plain || preserveTree) {
+				logger.Error("--markdown pastes HTML or text as Markdown; it can't be combined with --type, --move, --plain or --preserve-structure")
+				os.Exit(common.ExitUsage)
+			}
+
+			if destination == "" && flavor == "" && !markdown {
 				// Check if clipboard has files - if so, default to current directory
 				if files := clippy.GetFiles(); len(files) > 0 {
 					destination = "."
 				}
 			}
 
-			if destination == "" {
+			// Moving deletes the originals, so confirm before touching system locations
+			allowProtected := false
+			if move {
+				if destination == "" {
+					logger.Error("--move needs file references on the clipboard")
+					os.Exit(common.ExitUsage)
+				}
+				if protected := protectedFiles(clippy.GetFiles()); len(protected) > 0 {
+					if !common.IsInteractive() {
+						logger.Error("Refusing to move files out of system locations: %v", protected)
+						os.Exit(1)
+					}
+					if !common.Confirm(fmt.Sprintf("Move %d file(s) out of system locations (%s)?", len(protected), protected[0])) {
+						fmt.Println("Cancelled.")
+						os.Exit(0)
+					}
+					allowProtected = true
+				}
+			}
+
+			if flavor != "" {
+				result, err = clippy.PasteFlavor(flavor, destination, clippy.PasteOptions{Force: force})
+			} else if markdown {
+				result, err = clippy.PasteMarkdown(destination, clippy.PasteOptions{Force: force})
+			} else if destination == "" {
 				result, err = clippy.PasteToStdout()
 			} else {
 				result, err = clippy.PasteToFileWithOptions(destination, clippy.PasteOptions{
-					PreserveFormat: preserveFormat,
-					PlainTextOnly:  plain,
-					Force:          force,
+					PreserveFormat:    preserveFormat,
+					PlainTextOnly:     plain,
+					Force:             force,
+					PreserveStructure: preserveTree,
+					PreserveTimes:     preserveTimes,
+					PreserveXattrs:    preserveXattrs,
+					Move:              move,
+					AllowProtected:    allowProtected,
+					Verify:            verify,
+					StripMetadata:     stripMetadata,
+					MaxWidth:          maxWidth,
 				})
 			}
 
 			if err != nil {
 				logger.Error("%v", err)
+				os.Exit(common.ExitCode(err))
 			}
 
 			// Show verbose output
@@ -109,6 +222,8 @@ Description:
 				if destination == "" {
 					if result.Type == "text" {
 						logger.Verbose("Pasted text content to stdout")
+					} else if result.Type == "data" {
+						logger.Verbose("Pasted %s to stdout", flavor)
 					} else {
 						logger.Verbose("Listed %d file references from clipboard", len(result.Files))
 					}
@@ -120,8 +235,17 @@ Description:
 						logger.Verbose("Saved image data to '%s'", result.Files[0])
 					case "rtfd":
 						logger.Verbose("Saved rich text with embedded images to '%s'", result.Files[0])
+					case "data":
+						logger.Verbose("Saved %s to '%s'", flavor, result.Files[0])
 					case "files":
-						logger.Verbose("Copied %d files to '%s'", result.FilesRead, destination)
+						if result.Moved {
+							logger.Verbose("Moved %d files to '%s'", result.FilesRead, destination)
+						} else {
+							logger.Verbose("Copied %d files to '%s'", result.FilesRead, destination)
+						}
+						if result.Verified {
+							logger.Verbose("Verified all copies match their sources (SHA-256)")
+						}
 						if verbose {
 							for _, file := range result.Files {
 								fmt.Fprintf(os.Stderr, "  - %s\n", filepath.Base(file))
@@ -129,22 +253,58 @@ Description:
 						}
 					}
 				}
+				if notifyFlag {
+					if err := notify.Post("pasty", pasteSummary(result, destination)); err != nil {
+						logger.Debug("%v", err)
+					}
+				}
+				if bellFlag {
+					notify.Beep(true)
+				}
+				runPostPasteHook(settings["post_paste_hook"], result, destination)
 			}
 		},
 	}
 
 	// Add flags
-	common.AddCommonFlags(rootCmd, &verbose, &debug)
+	common.AddCommonFlags(rootCmd, &verbose, &debug, &quiet)
 	rootCmd.Flags().BoolVar(&preserveFormat, "preserve-format", false, "Preserve original image format (skip TIFF to PNG conversion)")
 	rootCmd.Flags().BoolVar(&inspect, "inspect", false, "Show clipboard types and paste priority (metadata only)")
 	rootCmd.Flags().BoolVar(&plain, "plain", false, "Force plain text output (strip all formatting)")
+	rootCmd.Flags().BoolVar(&urlOnly, "url", false, "Print only the copied URL (the link's URL, or the first URL in the text); error if there is none")
+	rootCmd.Flags().BoolVar(&markdown, "markdown", false, "Convert copied HTML (e.g. from a web page) to Markdown; plain text is used if there's no HTML")
+	rootCmd.Flags().StringVar(&flavor, "type", "", "Paste exactly this clipboard type (UTI or MIME, e.g. public.html), erroring if it's not there")
 	rootCmd.Flags().BoolVarP(&force, "force", "f", false, "Overwrite existing files without Finder-style duplicate naming")
+	rootCmd.Flags().BoolVar(&move, "move", false, "Move clipboard files to the destination (delete originals after a verified copy)")
+	rootCmd.Flags().BoolVar(&verify, "verify", false, "Check pasted files are byte-identical to their sources (SHA-256)")
+	rootCmd.Flags().BoolVar(&bellFlag, "bell", false, "Play a sound when the paste finishes, and a different one on error")
+	rootCmd.Flags().BoolVar(&notifyFlag, "notify", false, "Post a macOS notification when the paste finishes (handy for hotkeys)")
+	rootCmd.Flags().StringVar(&pasteboard, "pasteboard", "", "Paste from a named macOS pasteboard instead of the general clipboard")
+	rootCmd.Flags().BoolVar(&preserveTimes, "preserve-times", false, "Give pasted files their originals' modification and access times")
+	rootCmd.Flags().BoolVar(&preserveXattrs, "preserve-xattrs", false, "Copy extended attributes (Finder tags, download origin) to pasted files")
+	rootCmd.Flags().IntVar(&maxWidth, "max-width", 0, "Downscale pasted images wider than this many pixels (keeps the aspect ratio)")
+	rootCmd.Flags().BoolVar(&stripMetadata, "strip-metadata", false, "Remove EXIF and other metadata (location, camera, timestamps) from pasted PNG and JPEG images")
+	rootCmd.Flags().BoolVar(&preserveTree, "preserve-structure", false, "Recreate the copied files' folder layout under the destination instead of flattening them")
 
-	// Execute the command
+	// Execute the command, then give the post-paste hook a chance to finish
+	defer common.WaitForHooks()
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		if !quiet {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+		os.Exit(common.ExitUsage)
+	}
+}
+
+// protectedFiles returns the files that are in system locations
+func protectedFiles(files []string) []string {
+	var protected []string
+	for _, file := range files {
+		if clippy.IsProtectedPath(file) {
+			protected = append(protected, file)
+		}
 	}
+	return protected
 }
 
 func inspectClipboard() {
@@ -166,4 +326,59 @@ func inspectClipboard() {
 	} else {
 		fmt.Println("  → No supported content found")
 	}
+
+	if changed, ok := clippy.LastChanged(); ok {
+		fmt.Printf("\nLast changed: %s (%s)\n", common.FormatAge(time.Since(changed)), changed.Format("Jan 2 15:04:05"))
+	} else {
+		fmt.Println("\nLast changed: unknown (not copied by clippy)")
+	}
+}
+
+// pasteSummary describes a completed paste in a few words, for notifications
+func pasteSummary(result *clippy.PasteResult, destination string) string {
+	switch {
+	case result.Type == "text" && destination == "":
+		return "Pasted text to stdout"
+	case result.Type == "text":
+		return fmt.Sprintf("Pasted text to '%s'", destination)
+	case result.Type == "data" && destination == "":
+		return fmt.Sprintf("Pasted %s to stdout", flavor)
+	case destination == "":
+		return fmt.Sprintf("Listed %d file references", len(result.Files))
+	case result.Type == "files" && result.Moved:
+		return fmt.Sprintf("Moved %d files to '%s'", result.FilesRead, destination)
+	case result.Type == "files":
+		return fmt.Sprintf("Copied %d files to '%s'", result.FilesRead, destination)
+	case len(result.Files) > 0:
+		return fmt.Sprintf("Saved '%s'", filepath.Base(result.Files[0]))
+	default:
+		return "Pasted clipboard content"
+	}
+}
+
+// runPostPasteHook runs the post_paste_hook from ~/.clippy.conf, if one is set
+func runPostPasteHook(hook string, result *clippy.PasteResult, destination string) {
+	if hook == "" {
+		return
+	}
+
+	event := common.HookEvent{Type: result.Type, Files: result.Files}
+	if result.Type == "text" {
+		event.Bytes = int64(len(result.Content))
+		if destination != "" && len(event.Files) == 0 {
+			if path, err := filepath.Abs(destination); err == nil {
+				event.Files = []string{path}
+			}
+		}
+	} else if result.Type == "data" && destination == "" {
+		event.Bytes = int64(len(result.Content))
+	} else {
+		// Pasted file references are reported by their copies: under --move
+		// the sources are already gone
+		if len(result.Copies) > 0 {
+			event.Files = result.Copies
+		}
+		event.Bytes = common.TotalSize(event.Files)
+	}
+	common.RunHook(hook, event, logger)
 }
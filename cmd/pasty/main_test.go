@@ -4,12 +4,27 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/neilberkman/clippy/pkg/clipboard"
 )
 
 func TestMain(m *testing.M) {
+	// Without the macOS pasteboard, clippy and pasty share a file-backed memory clipboard
+	clipboardDir := ""
+	if runtime.GOOS != "darwin" && os.Getenv(clipboard.BackendEnvVar) == "" {
+		dir, err := os.MkdirTemp("", "pasty-test-")
+		if err != nil {
+			panic("Failed to create clipboard dir: " + err.Error())
+		}
+		clipboardDir = dir
+		_ = os.Setenv(clipboard.BackendEnvVar, "memory")
+		_ = os.Setenv(clipboard.MemoryFileEnvVar, filepath.Join(dir, "clipboard.json"))
+	}
+
 	// Build the pasty binary for testing
 	cmd := exec.Command("go", "build", "-o", "pasty_test", ".")
 	if err := cmd.Run(); err != nil {
@@ -28,6 +43,9 @@ func TestMain(m *testing.M) {
 	// Cleanup
 	_ = os.Remove("pasty_test")
 	_ = os.Remove("clippy_test")
+	if clipboardDir != "" {
+		_ = os.RemoveAll(clipboardDir)
+	}
 
 	os.Exit(code)
 }
@@ -0,0 +1,260 @@
+package clippy
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/neilberkman/clippy/pkg/clipboard"
+)
+
+// CopyOptions configures the *WithOptions copy functions
+type CopyOptions struct {
+	// Verify reads the clipboard back after writing and returns an error if it
+	// doesn't hold what was written. It costs an extra clipboard read, so it's off
+	// by default.
+	Verify bool
+
+	// Merge adds to the clipboard instead of clearing it first, so text from one
+	// call and files from another end up on the clipboard together. Text replaces
+	// an existing representation of the same type.
+	Merge bool
+
+	// SkipIfSame leaves the clipboard alone when it already holds the content,
+	// so repeated copies don't bump the pasteboard change count and show up in
+	// clipboard managers. Skipped writes return ErrAlreadyOnClipboard.
+	SkipIfSame bool
+
+	// Name is the file name to use when piped data is saved to a temp file and
+	// copied as a file reference, so apps show a meaningful name on paste. It is
+	// sanitized, and the detected extension is added if it has none.
+	Name string
+
+	// MimeType skips content detection for piped data and copies it as this
+	// type: textual MIME types (and UTIs) as text of that type, anything else as
+	// a temp file with the type's extension.
+	MimeType string
+
+	// AsFile saves piped data to a temp file and copies a reference to it even
+	// when it is text, e.g. a CSV to attach rather than paste.
+	AsFile bool
+
+	// Wrap is a template applied to text before it's copied: {} becomes the text
+	// and {filename} the file's name (or Name for piped data). See ApplyTemplate.
+	// The wrapped text's type is detected again. Binary data is never wrapped.
+	Wrap string
+
+	// Stats counts the lines, words and bytes of text copied from a file and
+	// reports them in CopyResult.Stats (CopyWithResultAndOptions only)
+	Stats bool
+
+	// TextExtensions lists file extensions ("md", ".txt") that copy as text
+	// without forcing text mode, as long as the content really is text
+	// (CopyWithResultAndOptions only). Everything else still copies as a reference.
+	TextExtensions []string
+
+	// ReferenceExtensions lists file extensions ("pdf", ".docx") that always copy
+	// as a file reference, even when text mode is forced or the extension is also
+	// in TextExtensions (CopyWithResultAndOptions only)
+	ReferenceExtensions []string
+}
+
+// ErrAlreadyOnClipboard is returned when CopyOptions.SkipIfSame skipped a write
+// because the clipboard already held the content. Treat it as success.
+var ErrAlreadyOnClipboard = errors.New("already on clipboard")
+
+// CopyTextWithOptions is like CopyText but can verify or merge the write
+func CopyTextWithOptions(text string, opts CopyOptions) error {
+	typeIdentifier := detectTextUTI(text)
+	if opts.SkipIfSame && textOnClipboard(text, typeIdentifier, opts.Merge) {
+		return ErrAlreadyOnClipboard
+	}
+	return writeText(text, typeIdentifier, opts)
+}
+
+// CopyFileWithOptions copies path to the clipboard as a file reference and can
+// verify or merge the write
+func CopyFileWithOptions(path string, opts CopyOptions) error {
+	return CopyMultipleWithOptions([]string{path}, opts)
+}
+
+// CopyFileAs copies a reference to path under a different file name, which is
+// what apps show when it's pasted or attached. The content is identical: the
+// file is cloned (or copied) into a clippy-named-* temp directory, leaving the
+// original untouched, and CleanupTempFiles removes it once it leaves the
+// clipboard. Returns the path that was copied.
+func CopyFileAs(path string, name string, tempDir string, opts CopyOptions) (string, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("could not resolve path %s: %w", path, err)
+	}
+	if _, err := os.Stat(absPath); os.IsNotExist(err) {
+		return "", fmt.Errorf("%w: %s", ErrFileNotFound, absPath)
+	}
+
+	if skipForDryRun("copy file reference %s as %s", absPath, describeTempFile(name, filepath.Ext(absPath))) {
+		return "", nil
+	}
+	alias, err := createTempAlias(tempDir, absPath, name)
+	if err != nil {
+		return "", fmt.Errorf("could not create %s: %w", name, err)
+	}
+	if err := writeFiles([]string{alias}, opts); err != nil {
+		return "", fmt.Errorf("could not copy file to clipboard: %w", err)
+	}
+	return alias, nil
+}
+
+// CopyMultipleWithOptions is like CopyMultiple but can verify or merge the write
+func CopyMultipleWithOptions(paths []string, opts CopyOptions) error {
+	if len(paths) == 0 {
+		return fmt.Errorf("no files provided")
+	}
+
+	// Convert to absolute paths, drop duplicates and verify all files exist
+	absPaths, err := UniquePaths(paths)
+	if err != nil {
+		return err
+	}
+	for _, absPath := range absPaths {
+		if _, err := os.Stat(absPath); os.IsNotExist(err) {
+			return fmt.Errorf("%w: %s", ErrFileNotFound, absPath)
+		}
+	}
+
+	if opts.SkipIfSame && filesOnClipboard(absPaths, opts.Merge) {
+		return ErrAlreadyOnClipboard
+	}
+	if err := writeFiles(absPaths, opts); err != nil {
+		return fmt.Errorf("could not copy files to clipboard: %w", err)
+	}
+	return nil
+}
+
+// CopyDataWithOptions is like CopyDataWithTempDir but can verify or merge the write
+func CopyDataWithOptions(reader io.Reader, tempDir string, opts CopyOptions) error {
+	return copyData(reader, tempDir, "", opts)
+}
+
+// writeText writes text as typeIdentifier, honoring opts. Replacing writes of a
+// rich type also carry plain text; merged writes only add plain text when the
+// clipboard has none yet.
+func writeText(text string, typeIdentifier string, opts CopyOptions) error {
+	var err error
+	switch {
+	case opts.Merge:
+		err = addClipboardText(text, typeIdentifier)
+		if err == nil && typeIdentifier != clipboard.PlainTextType && !clipboard.ContainsType(clipboard.PlainTextType) {
+			err = addClipboardText(text, clipboard.PlainTextType)
+		}
+	case typeIdentifier == clipboard.PlainTextType:
+		err = writeClipboardText(text)
+	default:
+		err = writeClipboardTextWithType(text, typeIdentifier)
+	}
+	if err != nil || !opts.Verify || IsDryRun() {
+		return err
+	}
+	return verifyClipboardText(text, typeIdentifier)
+}
+
+// writeFiles writes file references, honoring opts
+func writeFiles(paths []string, opts CopyOptions) error {
+	var err error
+	if opts.Merge {
+		err = addClipboardFiles(paths)
+	} else {
+		err = writeClipboardFiles(paths)
+	}
+	if err != nil || !opts.Verify || IsDryRun() {
+		return err
+	}
+	return verifyClipboardFiles(paths, opts.Merge)
+}
+
+// textOnClipboard reports whether the clipboard already holds text as
+// typeIdentifier. Unless merging, it must also hold no file references.
+func textOnClipboard(text string, typeIdentifier string, merge bool) bool {
+	if !merge && len(clipboard.GetFiles()) > 0 {
+		return false
+	}
+	data, ok := clipboard.GetClipboardDataForType(typeIdentifier)
+	return ok && string(data) == text
+}
+
+// filesOnClipboard reports whether the clipboard already holds exactly paths
+// (or, when merging, at least paths)
+func filesOnClipboard(paths []string, merge bool) bool {
+	files := clipboard.GetFiles()
+	if merge {
+		return containsPaths(files, paths)
+	}
+	return samePathSet(files, paths)
+}
+
+// dataOnClipboard reports whether the clipboard holds a single clippy temp file
+// with exactly data, as left by an earlier copy of the same binary input. When
+// name is set the file must also have that name.
+func dataOnClipboard(data []byte, name string, ext string) bool {
+	files := clipboard.GetFiles()
+	if len(files) != 1 || !isTempFile(files[0]) {
+		return false
+	}
+	if name != "" && filepath.Base(files[0]) != tempFileName(name, ext) {
+		return false
+	}
+	info, err := os.Stat(files[0])
+	if err != nil || info.Size() != int64(len(data)) {
+		return false
+	}
+	existing, err := os.ReadFile(files[0])
+	return err == nil && bytes.Equal(existing, data)
+}
+
+// verifyClipboardText checks that the clipboard holds exactly text as typeIdentifier
+func verifyClipboardText(text string, typeIdentifier string) error {
+	got, ok := clipboard.GetClipboardDataForType(typeIdentifier)
+	if !ok {
+		return fmt.Errorf("clipboard verification failed: clipboard has no %s after writing", typeIdentifier)
+	}
+	if string(got) != text {
+		return fmt.Errorf("clipboard verification failed: clipboard holds %d bytes of different text, wrote %d bytes", len(got), len(text))
+	}
+	return nil
+}
+
+// verifyClipboardFiles checks that the clipboard's file references are exactly
+// paths, in any order. After a merge the clipboard only has to include them.
+func verifyClipboardFiles(paths []string, merged bool) error {
+	got := clipboard.GetFiles()
+	ok := samePathSet(got, paths)
+	if merged {
+		ok = containsPaths(got, paths)
+	}
+	if !ok {
+		return fmt.Errorf("clipboard verification failed: clipboard holds %v, wrote %v", got, paths)
+	}
+	return nil
+}
+
+// samePathSet reports whether a and b name the same files, ignoring order
+func samePathSet(a, b []string) bool {
+	return len(a) == len(b) && containsPaths(a, b) && containsPaths(b, a)
+}
+
+// containsPaths reports whether every path in want is in have
+func containsPaths(have, want []string) bool {
+	set := make(map[string]bool, len(have))
+	for _, p := range have {
+		set[filepath.Clean(p)] = true
+	}
+	for _, p := range want {
+		if !set[filepath.Clean(p)] {
+			return false
+		}
+	}
+	return true
+}
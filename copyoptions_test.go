@@ -0,0 +1,306 @@
+package clippy
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/neilberkman/clippy/pkg/clipboard"
+)
+
+// droppingClipboard accepts writes without storing them, like a pasteboard that
+// didn't take the write
+type droppingClipboard struct {
+	*clipboard.MemoryManager
+}
+
+func (droppingClipboard) CopyText(string) error    { return nil }
+func (droppingClipboard) CopyFiles([]string) error { return nil }
+func (droppingClipboard) CopyFile(string) error    { return nil }
+
+func TestCopyWithOptionsVerify(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.txt")
+	b := filepath.Join(dir, "b.txt")
+	for _, path := range []string{a, b} {
+		if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+			t.Fatalf("Failed to create file: %v", err)
+		}
+	}
+
+	t.Run("text matches", func(t *testing.T) {
+		useMemoryClipboard(t)
+		if err := CopyTextWithOptions("hello", CopyOptions{Verify: true}); err != nil {
+			t.Errorf("CopyTextWithOptions returned error: %v", err)
+		}
+	})
+
+	t.Run("files match", func(t *testing.T) {
+		useMemoryClipboard(t)
+		if err := CopyMultipleWithOptions([]string{b, a}, CopyOptions{Verify: true}); err != nil {
+			t.Errorf("CopyMultipleWithOptions returned error: %v", err)
+		}
+	})
+
+	t.Run("dropped writes are reported", func(t *testing.T) {
+		mem := useMemoryClipboard(t)
+		if err := mem.CopyText("stale"); err != nil {
+			t.Fatalf("Failed to seed clipboard: %v", err)
+		}
+		clipboard.SetManager(droppingClipboard{mem})
+
+		if err := CopyTextWithOptions("hello", CopyOptions{Verify: true}); err == nil {
+			t.Error("CopyTextWithOptions should fail when the clipboard keeps old text")
+		}
+		if err := CopyFileWithOptions(a, CopyOptions{Verify: true}); err == nil {
+			t.Error("CopyFileWithOptions should fail when the clipboard has no files")
+		}
+		if err := CopyTextWithOptions("hello", CopyOptions{}); err != nil {
+			t.Errorf("CopyTextWithOptions without Verify returned error: %v", err)
+		}
+	})
+}
+
+func TestCopyWithOptionsMerge(t *testing.T) {
+	mem := useMemoryClipboard(t)
+	path := filepath.Join(t.TempDir(), "report.pdf")
+	if err := os.WriteFile(path, []byte("%PDF-1.4"), 0644); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+
+	if err := CopyTextWithOptions("<p>hello</p>", CopyOptions{}); err != nil {
+		t.Fatalf("CopyTextWithOptions returned error: %v", err)
+	}
+	if err := CopyFileWithOptions(path, CopyOptions{Merge: true, Verify: true}); err != nil {
+		t.Fatalf("CopyFileWithOptions with Merge returned error: %v", err)
+	}
+
+	if files := mem.GetFiles(); len(files) != 1 || files[0] != path {
+		t.Errorf("clipboard files = %v, want [%s]", files, path)
+	}
+	if text, ok := mem.GetText(); !ok || text != "<p>hello</p>" {
+		t.Errorf("clipboard text = %q, %v; merge should keep the earlier text", text, ok)
+	}
+	if !mem.ContainsType("public.html") {
+		t.Error("merge should keep the earlier public.html representation")
+	}
+
+	// Without Merge the clipboard is replaced as before
+	if err := CopyTextWithOptions("plain", CopyOptions{}); err != nil {
+		t.Fatalf("CopyTextWithOptions returned error: %v", err)
+	}
+	if files := mem.GetFiles(); len(files) != 0 {
+		t.Errorf("clipboard files = %v after a replacing copy, want none", files)
+	}
+}
+
+func TestCopyWithOptionsSkipIfSame(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.txt")
+	if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+	skip := CopyOptions{SkipIfSame: true}
+
+	t.Run("text", func(t *testing.T) {
+		useMemoryClipboard(t)
+		if err := CopyTextWithOptions("hello", skip); err != nil {
+			t.Fatalf("first copy returned error: %v", err)
+		}
+		if err := CopyTextWithOptions("hello", skip); !errors.Is(err, ErrAlreadyOnClipboard) {
+			t.Errorf("second copy returned %v, want ErrAlreadyOnClipboard", err)
+		}
+		if err := CopyTextWithOptions("changed", skip); err != nil {
+			t.Errorf("copy of new text returned error: %v", err)
+		}
+	})
+
+	t.Run("files", func(t *testing.T) {
+		useMemoryClipboard(t)
+		if err := CopyFileWithOptions(path, skip); err != nil {
+			t.Fatalf("first copy returned error: %v", err)
+		}
+		if err := CopyFileWithOptions(path, skip); !errors.Is(err, ErrAlreadyOnClipboard) {
+			t.Errorf("second copy returned %v, want ErrAlreadyOnClipboard", err)
+		}
+	})
+
+	t.Run("binary data", func(t *testing.T) {
+		useMemoryClipboard(t)
+		dir := t.TempDir()
+		png, err := os.ReadFile("test-files/minimal.png")
+		if err != nil {
+			t.Fatalf("Failed to read test image: %v", err)
+		}
+		if err := CopyDataWithOptions(bytes.NewReader(png), dir, skip); err != nil {
+			t.Fatalf("first copy returned error: %v", err)
+		}
+		if err := CopyDataWithOptions(bytes.NewReader(png), dir, skip); !errors.Is(err, ErrAlreadyOnClipboard) {
+			t.Errorf("second copy returned %v, want ErrAlreadyOnClipboard", err)
+		}
+		if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+			t.Errorf("skipped copy left %d temp files, want 1", len(entries))
+		}
+	})
+}
+
+func TestCopyFileAs(t *testing.T) {
+	mem := useMemoryClipboard(t)
+	src := filepath.Join(t.TempDir(), "IMG_4032.jpg")
+	if err := os.WriteFile(src, []byte("jpeg bytes"), 0644); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+	tmpDir := t.TempDir()
+
+	alias, err := CopyFileAs(src, "beach", tmpDir, CopyOptions{})
+	if err != nil {
+		t.Fatalf("CopyFileAs returned error: %v", err)
+	}
+	if filepath.Base(alias) != "beach.jpg" || filepath.Dir(filepath.Dir(alias)) != tmpDir {
+		t.Errorf("CopyFileAs path = %s, want beach.jpg in a clippy-named-* directory in %s", alias, tmpDir)
+	}
+	if files := mem.GetFiles(); len(files) != 1 || files[0] != alias {
+		t.Errorf("clipboard files = %v, want [%s]", files, alias)
+	}
+	if data, err := os.ReadFile(alias); err != nil || string(data) != "jpeg bytes" {
+		t.Errorf("alias content = %q, %v, want the original's", data, err)
+	}
+	if _, err := os.Stat(src); err != nil {
+		t.Errorf("original is gone: %v", err)
+	}
+
+	// Apps may edit the pasted file in place; that must not reach the original
+	if err := os.WriteFile(alias, []byte("edited"), 0644); err != nil {
+		t.Fatalf("Failed to edit alias: %v", err)
+	}
+	if data, _ := os.ReadFile(src); string(data) != "jpeg bytes" {
+		t.Errorf("original content = %q after editing the alias, want it unchanged", data)
+	}
+
+	if _, err := CopyFileAs(filepath.Join(tmpDir, "missing.txt"), "x", tmpDir, CopyOptions{}); !errors.Is(err, ErrFileNotFound) {
+		t.Errorf("CopyFileAs(missing) error = %v, want ErrFileNotFound", err)
+	}
+}
+
+func TestCopyWithOptionsTextExtensions(t *testing.T) {
+	dir := t.TempDir()
+	notes := filepath.Join(dir, "notes.md")
+	if err := os.WriteFile(notes, []byte("# Notes\n"), 0644); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+	image, err := filepath.Abs("test-files/minimal.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := CopyOptions{TextExtensions: []string{"md", "png"}}
+
+	t.Run("listed text file copies as text", func(t *testing.T) {
+		mem := useMemoryClipboard(t)
+		result, err := CopyWithResultAndOptions(notes, false, opts)
+		if err != nil {
+			t.Fatalf("CopyWithResultAndOptions returned error: %v", err)
+		}
+		if !result.AsText {
+			t.Errorf("AsText = false, want true for a listed extension")
+		}
+		if text, ok := mem.GetText(); !ok || text != "# Notes\n" {
+			t.Errorf("clipboard text = %q, want the file content", text)
+		}
+	})
+
+	t.Run("listed binary file stays a reference", func(t *testing.T) {
+		mem := useMemoryClipboard(t)
+		result, err := CopyWithResultAndOptions(image, false, opts)
+		if err != nil {
+			t.Fatalf("CopyWithResultAndOptions returned error: %v", err)
+		}
+		if result.AsText {
+			t.Errorf("AsText = true, want a file reference for binary content")
+		}
+		if files := mem.GetFiles(); len(files) != 1 || files[0] != image {
+			t.Errorf("clipboard files = %v, want [%s]", files, image)
+		}
+	})
+
+	t.Run("unlisted file stays a reference", func(t *testing.T) {
+		useMemoryClipboard(t)
+		result, err := CopyWithResultAndOptions(notes, false, CopyOptions{TextExtensions: []string{"txt"}})
+		if err != nil {
+			t.Fatalf("CopyWithResultAndOptions returned error: %v", err)
+		}
+		if result.AsText {
+			t.Errorf("AsText = true, want a file reference for an unlisted extension")
+		}
+	})
+}
+
+func TestCopyWithOptionsReferenceExtensions(t *testing.T) {
+	dir := t.TempDir()
+	notes := filepath.Join(dir, "notes.md")
+	if err := os.WriteFile(notes, []byte("# Notes\n"), 0644); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		forceText bool
+		opts      CopyOptions
+	}{
+		{"wins over forced text mode", true, CopyOptions{ReferenceExtensions: []string{"md"}}},
+		{"wins over text extensions", false, CopyOptions{TextExtensions: []string{"md"}, ReferenceExtensions: []string{".MD"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mem := useMemoryClipboard(t)
+			result, err := CopyWithResultAndOptions(notes, tt.forceText, tt.opts)
+			if err != nil {
+				t.Fatalf("CopyWithResultAndOptions returned error: %v", err)
+			}
+			if result.AsText {
+				t.Errorf("AsText = true, want a file reference")
+			}
+			if files := mem.GetFiles(); len(files) != 1 || files[0] != notes {
+				t.Errorf("clipboard files = %v, want [%s]", files, notes)
+			}
+		})
+	}
+}
+
+func TestExplainCopy(t *testing.T) {
+	dir := t.TempDir()
+	notes := filepath.Join(dir, "notes.md")
+	if err := os.WriteFile(notes, []byte("# Notes\n"), 0644); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+	mem := useMemoryClipboard(t)
+
+	d, err := ExplainCopy("test-files/minimal.png", true, CopyOptions{})
+	if err != nil {
+		t.Fatalf("ExplainCopy returned error: %v", err)
+	}
+	if d.AsText || !d.TextRequested {
+		t.Errorf("AsText = %v, TextRequested = %v; want a reference despite -t", d.AsText, d.TextRequested)
+	}
+	if d.MIME != "image/png" || d.MIMEIsText {
+		t.Errorf("MIME = %q (text %v), want image/png (not text)", d.MIME, d.MIMEIsText)
+	}
+	if d.Rule == "" {
+		t.Error("Rule is empty")
+	}
+
+	d, err = ExplainCopy(notes, false, CopyOptions{TextExtensions: []string{"md"}})
+	if err != nil {
+		t.Fatalf("ExplainCopy returned error: %v", err)
+	}
+	if !d.AsText || !d.TextRequested || d.MIME == "" {
+		t.Errorf("AsText = %v, TextRequested = %v, MIME = %q; want text with the MIME type detected", d.AsText, d.TextRequested, d.MIME)
+	}
+
+	if _, err := ExplainCopy(filepath.Join(dir, "missing.txt"), false, CopyOptions{}); !errors.Is(err, ErrFileNotFound) {
+		t.Errorf("ExplainCopy on a missing file returned %v, want ErrFileNotFound", err)
+	}
+	if mem.ChangeCount() != 0 {
+		t.Errorf("ExplainCopy changed the clipboard (change count %d)", mem.ChangeCount())
+	}
+}
@@ -0,0 +1,135 @@
+package clippy
+
+import (
+	"fmt"
+
+	"github.com/neilberkman/clippy/pkg/clipboard"
+)
+
+var (
+	dryRun       bool
+	dryRunReport func(action string)
+)
+
+// SetDryRun enables or disables dry-run mode. In dry-run mode detection and path
+// resolution run as usual, but nothing is written to the clipboard, no temp files
+// are created and no old temp files are removed. If report is non-nil it is called
+// with a description of each action that was skipped.
+func SetDryRun(enabled bool, report func(action string)) {
+	dryRun = enabled
+	dryRunReport = report
+}
+
+// IsDryRun reports whether dry-run mode is enabled
+func IsDryRun() bool {
+	return dryRun
+}
+
+// skipForDryRun reports the action and returns true if dry-run mode is enabled
+func skipForDryRun(format string, args ...interface{}) bool {
+	if !dryRun {
+		return false
+	}
+	if dryRunReport != nil {
+		dryRunReport(fmt.Sprintf(format, args...))
+	}
+	return true
+}
+
+// The helpers below wrap every clipboard write so dry-run mode can skip them,
+// transient failures are retried (see SetRetryOptions), concurrent processes
+// can take turns (see SetLockOptions) and the time of the change is recorded
+// for LastChanged.
+
+// writeAndRecord runs a clipboard write with retries, holding the clipboard lock
+// if locking is enabled, and records the change
+func writeAndRecord(write func() error) error {
+	unlock, err := LockClipboard()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if err := withRetry(write); err != nil {
+		return err
+	}
+	recordChange()
+	return nil
+}
+
+func writeClipboardFile(path string) error {
+	if skipForDryRun("copy file reference %s", path) {
+		return nil
+	}
+	return writeAndRecord(func() error {
+		return clipboard.CopyFile(path)
+	})
+}
+
+func writeClipboardFiles(paths []string) error {
+	if skipForDryRun("copy %d file references: %v", len(paths), paths) {
+		return nil
+	}
+	return writeAndRecord(func() error {
+		return clipboard.CopyFiles(paths)
+	})
+}
+
+func writeClipboardText(text string) error {
+	if skipForDryRun("copy %d bytes of text as public.plain-text", len(text)) {
+		return nil
+	}
+	return writeAndRecord(func() error {
+		return clipboard.CopyText(text)
+	})
+}
+
+func writeClipboardTextWithType(text string, typeIdentifier string) error {
+	typeIdentifier = textType(typeIdentifier)
+	if skipForDryRun("copy %d bytes of text as %s", len(text), typeIdentifier) {
+		return nil
+	}
+	return writeAndRecord(func() error {
+		return clipboard.CopyTextWithType(text, typeIdentifier)
+	})
+}
+
+func writeClipboardFlavors(flavors []clipboard.Flavor) error {
+	types := make([]string, len(flavors))
+	for i, flavor := range flavors {
+		types[i] = flavor.Type
+	}
+	if skipForDryRun("copy %d representations: %v", len(flavors), types) {
+		return nil
+	}
+	return writeAndRecord(func() error {
+		return clipboard.CopyFlavors(flavors)
+	})
+}
+
+func addClipboardFiles(paths []string) error {
+	if skipForDryRun("add %d file references without clearing: %v", len(paths), paths) {
+		return nil
+	}
+	return writeAndRecord(func() error {
+		return clipboard.AddFiles(paths)
+	})
+}
+
+func addClipboardText(text string, typeIdentifier string) error {
+	typeIdentifier = textType(typeIdentifier)
+	if skipForDryRun("add %d bytes of text as %s without clearing", len(text), typeIdentifier) {
+		return nil
+	}
+	return writeAndRecord(func() error {
+		return clipboard.AddTextWithType(text, typeIdentifier)
+	})
+}
+
+func clearClipboard() error {
+	if skipForDryRun("clear the clipboard") {
+		return nil
+	}
+	return writeAndRecord(func() error {
+		return clipboard.Clear()
+	})
+}
@@ -0,0 +1,45 @@
+package clippy
+
+import "github.com/gabriel-vasile/mimetype"
+
+// tempFileExtensionOverrides replaces extensions the mimetype library gets wrong
+// or leaves empty for types that commonly end up in temp files
+var tempFileExtensionOverrides = map[string]string{
+	"application/octet-stream":  ".bin",
+	"application/x-ole-storage": ".bin",
+	"audio/mp4":                 ".m4a",
+	"video/quicktime":           ".mov",
+}
+
+// binaryMimeUTIs maps binary MIME types to UTIs so macOS can supply the canonical
+// extension. Text types are handled by mimeToUTI.
+var binaryMimeUTIs = map[string]string{
+	"application/pdf": "com.adobe.pdf",
+	"application/zip": "public.zip-archive",
+	"image/gif":       "com.compuserve.gif",
+	"image/heic":      "public.heic",
+	"image/jpeg":      "public.jpeg",
+	"image/png":       "public.png",
+	"image/tiff":      "public.tiff",
+	"image/webp":      "org.webmproject.webp",
+}
+
+// tempFileExtension picks the extension for a temp file holding data of type mtype:
+// an explicit override first, then the system's preferred extension for the
+// type's UTI, then the mimetype library's extension, and ".bin" if all are empty.
+// Apps that sniff by extension misbehave on extensionless temp files.
+func tempFileExtension(mtype *mimetype.MIME) string {
+	mimeStr := mtype.String()
+	if ext, ok := tempFileExtensionOverrides[mimeStr]; ok {
+		return ext
+	}
+	if uti, ok := binaryMimeUTIs[mimeStr]; ok {
+		if ext := getFileExtensionFromUTI(uti); ext != "" {
+			return ext
+		}
+	}
+	if ext := mtype.Extension(); ext != "" {
+		return ext
+	}
+	return ".bin"
+}
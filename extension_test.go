@@ -0,0 +1,34 @@
+package clippy
+
+import (
+	"testing"
+
+	"github.com/gabriel-vasile/mimetype"
+)
+
+func TestTempFileExtension(t *testing.T) {
+	// The memory backend's static UTI table keeps results independent of the OS type database
+	useMemoryClipboard(t)
+
+	tests := []struct {
+		mime string
+		want string
+	}{
+		{"application/octet-stream", ".bin"}, // library extension is empty
+		{"application/x-ole-storage", ".bin"},
+		{"audio/mp4", ".m4a"},        // library says .mp4
+		{"video/x-matroska", ".mkv"}, // library extension is fine
+		{"image/png", ".png"},        // system preferred extension
+		{"application/pdf", ".pdf"},
+	}
+
+	for _, tt := range tests {
+		mtype := mimetype.Lookup(tt.mime)
+		if mtype == nil {
+			t.Fatalf("mimetype.Lookup(%q) returned nil", tt.mime)
+		}
+		if got := tempFileExtension(mtype); got != tt.want {
+			t.Errorf("tempFileExtension(%q) = %q, want %q", tt.mime, got, tt.want)
+		}
+	}
+}
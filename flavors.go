@@ -0,0 +1,122 @@
+package clippy
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/neilberkman/clippy/internal/fsutil"
+	"github.com/neilberkman/clippy/pkg/clipboard"
+)
+
+// ErrFlavorNotFound is returned (wrapped) when PasteFlavor is asked for a type
+// the clipboard doesn't hold
+var ErrFlavorNotFound = errors.New("type not on clipboard")
+
+// FlavorFile names a file whose content becomes one clipboard representation
+type FlavorFile struct {
+	Type string // UTI or MIME type, e.g. "public.html" or "text/html"
+	Path string
+}
+
+// CopyFlavorFiles reads each file and puts all of them on the clipboard as
+// representations of the same content, in one pasteboard transaction. This is
+// how browsers copy a selection: rich targets take the HTML, plain ones the text.
+// MIME types are mapped to UTIs; plain text ("text/plain") is stored as
+// public.utf8-plain-text so every app can read it.
+func CopyFlavorFiles(files []FlavorFile) error {
+	if len(files) == 0 {
+		return fmt.Errorf("no flavors provided")
+	}
+
+	flavors := make([]clipboard.Flavor, 0, len(files))
+	seen := map[string]bool{}
+	for _, file := range files {
+		typeIdentifier := flavorType(file.Type)
+		if seen[typeIdentifier] {
+			return fmt.Errorf("%s is given more than once", typeIdentifier)
+		}
+		seen[typeIdentifier] = true
+
+		absPath, err := filepath.Abs(file.Path)
+		if err != nil {
+			return fmt.Errorf("invalid path %s: %w", file.Path, err)
+		}
+		data, err := os.ReadFile(absPath)
+		if os.IsNotExist(err) {
+			return fmt.Errorf("%w: %s", ErrFileNotFound, absPath)
+		}
+		if err != nil {
+			return fmt.Errorf("could not read file %s: %w", absPath, err)
+		}
+		flavors = append(flavors, clipboard.Flavor{Type: typeIdentifier, Data: data})
+	}
+
+	if err := writeClipboardFlavors(flavors); err != nil {
+		return fmt.Errorf("could not copy to clipboard: %w", err)
+	}
+	return nil
+}
+
+// flavorType maps a MIME type to its UTI and any plain text type to the one
+// GetText reads
+func flavorType(typeIdentifier string) string {
+	typeIdentifier = strings.TrimSpace(typeIdentifier)
+	if strings.Contains(typeIdentifier, "/") {
+		typeIdentifier = mimeToUTI(strings.ToLower(typeIdentifier))
+	}
+	if typeIdentifier == "public.plain-text" {
+		return clipboard.PlainTextType
+	}
+	return typeIdentifier
+}
+
+// PasteFlavor writes exactly one representation of the clipboard content, such
+// as public.html or public.rtf, with no conversion or fallback. MIME types are
+// mapped to UTIs like CopyFlavorFiles does. With an empty destination the bytes
+// go to stdout; a directory gets a clipboard-<time> file with the type's
+// extension. Returns ErrFlavorNotFound (wrapped) if the type isn't on the clipboard.
+func PasteFlavor(typeIdentifier string, destination string, opts PasteOptions) (*PasteResult, error) {
+	typeIdentifier = flavorType(typeIdentifier)
+	types := clipboard.GetClipboardTypes()
+	found := false
+	for _, t := range types {
+		if t == typeIdentifier {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("%w: %s (available: %s)", ErrFlavorNotFound, typeIdentifier, strings.Join(types, ", "))
+	}
+
+	data, ok := clipboard.GetClipboardDataForType(typeIdentifier)
+	if !ok {
+		return nil, fmt.Errorf("could not read %s from clipboard", typeIdentifier)
+	}
+
+	if destination == "" {
+		if _, err := os.Stdout.Write(data); err != nil {
+			return nil, fmt.Errorf("could not write to stdout: %w", err)
+		}
+		return &PasteResult{Type: "data", Content: string(data)}, nil
+	}
+
+	ext := getFileExtensionFromUTI(typeIdentifier)
+	if ext == "" {
+		ext = ".dat"
+	}
+	defaultFilename := fmt.Sprintf("clipboard-%s%s", time.Now().Format("2006-01-02-150405"), ext)
+	destPath := resolveDestinationPath(destination, defaultFilename, true, opts.Force)
+
+	if err := fsutil.WriteFileAtomic(destPath, data, 0644); err != nil {
+		return nil, fmt.Errorf("could not write to file %s: %w", destPath, err)
+	}
+	return &PasteResult{
+		Type:  "data",
+		Files: []string{destPath},
+	}, nil
+}
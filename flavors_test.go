@@ -0,0 +1,68 @@
+package clippy
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/neilberkman/clippy/pkg/clipboard"
+)
+
+func TestCopyFlavorFiles(t *testing.T) {
+	mem := useMemoryClipboard(t)
+	dir := t.TempDir()
+	html := filepath.Join(dir, "body.html")
+	text := filepath.Join(dir, "body.txt")
+	if err := os.WriteFile(html, []byte("<b>Hi</b>"), 0644); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+	if err := os.WriteFile(text, []byte("Hi"), 0644); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+
+	err := CopyFlavorFiles([]FlavorFile{{Type: "text/html", Path: html}, {Type: "public.plain-text", Path: text}})
+	if err != nil {
+		t.Fatalf("CopyFlavorFiles returned error: %v", err)
+	}
+	if got, ok := mem.GetClipboardDataForType("public.html"); !ok || string(got) != "<b>Hi</b>" {
+		t.Errorf("public.html = %q, %v, want the HTML file", got, ok)
+	}
+	if got, ok := mem.GetText(); !ok || got != "Hi" {
+		t.Errorf("plain text = %q, %v, want the text file", got, ok)
+	}
+
+	if err := CopyFlavorFiles([]FlavorFile{{Type: "public.html", Path: filepath.Join(dir, "missing.html")}}); !errors.Is(err, ErrFileNotFound) {
+		t.Errorf("missing file error = %v, want ErrFileNotFound", err)
+	}
+	if err := CopyFlavorFiles([]FlavorFile{{Type: "text/html", Path: html}, {Type: "public.html", Path: html}}); err == nil {
+		t.Error("duplicate type succeeded, want error")
+	}
+}
+
+func TestPasteFlavor(t *testing.T) {
+	mem := useMemoryClipboard(t)
+	if err := mem.CopyFlavors([]clipboard.Flavor{
+		{Type: "public.html", Data: []byte("<b>Hi</b>")},
+		{Type: clipboard.PlainTextType, Data: []byte("Hi")},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+
+	dest := filepath.Join(dir, "out.html")
+	result, err := PasteFlavor("text/html", dest, PasteOptions{})
+	if err != nil {
+		t.Fatalf("PasteFlavor returned error: %v", err)
+	}
+	if got, err := os.ReadFile(dest); err != nil || string(got) != "<b>Hi</b>" {
+		t.Errorf("%s = %q, %v, want the HTML flavor", dest, got, err)
+	}
+	if result.Type != "data" || len(result.Files) != 1 || result.Files[0] != dest {
+		t.Errorf("result = %+v, want data written to %s", result, dest)
+	}
+
+	if _, err := PasteFlavor("public.rtf", dest, PasteOptions{}); !errors.Is(err, ErrFlavorNotFound) {
+		t.Errorf("missing type error = %v, want ErrFlavorNotFound", err)
+	}
+}
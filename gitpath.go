@@ -0,0 +1,54 @@
+package clippy
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ErrNotInRepo is returned (wrapped) by RepoRelativePath for a path outside any git repository
+var ErrNotInRepo = errors.New("not in a git repository")
+
+// RepoRoot returns the root of the git repository containing path: the nearest
+// directory at or above it with a .git entry (a directory, or a file for
+// worktrees and submodules).
+func RepoRoot(path string) (string, error) {
+	absPath, err := existingPath(path)
+	if err != nil {
+		return "", err
+	}
+	dir := absPath
+	if info, err := os.Stat(absPath); err == nil && !info.IsDir() {
+		dir = filepath.Dir(absPath)
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("%w: %s", ErrNotInRepo, absPath)
+		}
+		dir = parent
+	}
+}
+
+// RepoRelativePath returns path relative to the root of its git repository, with
+// forward slashes, e.g. "cmd/clippy/main.go". The repository root itself is ".".
+// Paths outside a repository return ErrNotInRepo.
+func RepoRelativePath(path string) (string, error) {
+	root, err := RepoRoot(path)
+	if err != nil {
+		return "", err
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("invalid path %s: %w", path, err)
+	}
+	rel, err := filepath.Rel(root, absPath)
+	if err != nil {
+		return "", err
+	}
+	return filepath.ToSlash(rel), nil
+}
@@ -0,0 +1,62 @@
+package clippy
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRepoRelativePath(t *testing.T) {
+	repo := t.TempDir()
+	if err := os.Mkdir(filepath.Join(repo, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// A submodule marks its root with a .git file instead of a directory
+	sub := filepath.Join(repo, "vendor", "lib")
+	if err := os.MkdirAll(filepath.Join(repo, "cmd", "tool"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(sub, ".git"), []byte("gitdir: ../../.git/modules/lib\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"cmd/tool/main.go", "vendor/lib/lib.go"} {
+		if err := os.WriteFile(filepath.Join(repo, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{filepath.Join(repo, "cmd", "tool", "main.go"), "cmd/tool/main.go"},
+		{filepath.Join(repo, "cmd"), "cmd"},
+		{repo, "."},
+		{filepath.Join(sub, "lib.go"), "lib.go"},
+	}
+	for _, tt := range tests {
+		got, err := RepoRelativePath(tt.path)
+		if err != nil {
+			t.Errorf("RepoRelativePath(%s) error = %v", tt.path, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("RepoRelativePath(%s) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+
+	outside := filepath.Join(t.TempDir(), "notes.txt")
+	if err := os.WriteFile(outside, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := RepoRelativePath(outside); !errors.Is(err, ErrNotInRepo) {
+		t.Errorf("RepoRelativePath(outside) error = %v, want ErrNotInRepo", err)
+	}
+	if _, err := RepoRelativePath(filepath.Join(repo, "missing.go")); !errors.Is(err, ErrFileNotFound) {
+		t.Errorf("RepoRelativePath(missing) error = %v, want ErrFileNotFound", err)
+	}
+}
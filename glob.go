@@ -0,0 +1,92 @@
+package clippy
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/neilberkman/clippy/pkg/recent"
+)
+
+// GlobOptions controls which matches glob expansion keeps
+type GlobOptions struct {
+	IncludeHidden bool // Keep dotfiles and files inside hidden directories
+	IncludeTemp   bool // Keep partial downloads and temp files (.part, .crdownload, ...)
+}
+
+// isGlobPattern reports whether arg contains glob metacharacters
+func isGlobPattern(arg string) bool {
+	return strings.ContainsAny(arg, "*?[{")
+}
+
+// ExpandGlobs expands file arguments that look like glob patterns, including
+// recursive "**" patterns (e.g. "**/*.png"). Arguments without metacharacters,
+// and patterns that name an existing path literally, are returned unchanged.
+// Hidden and temporary files are skipped unless the pattern names them explicitly.
+// Returns an error if a pattern is malformed or matches nothing.
+func ExpandGlobs(args []string) ([]string, error) {
+	return ExpandGlobsWithOptions(args, GlobOptions{})
+}
+
+// ExpandGlobsWithOptions is like ExpandGlobs but allows keeping hidden and temp files
+func ExpandGlobsWithOptions(args []string, opts GlobOptions) ([]string, error) {
+	expanded := make([]string, 0, len(args))
+	for _, arg := range args {
+		if !isGlobPattern(arg) {
+			expanded = append(expanded, arg)
+			continue
+		}
+
+		// A file literally named "photo[1].png" wins over pattern matching
+		if _, err := os.Stat(arg); err == nil {
+			expanded = append(expanded, arg)
+			continue
+		}
+
+		matches, err := doublestar.FilepathGlob(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid glob pattern %q: %w", arg, err)
+		}
+
+		kept := 0
+		for _, match := range matches {
+			if keepGlobMatch(arg, match, opts) {
+				expanded = append(expanded, match)
+				kept++
+			}
+		}
+		if kept == 0 {
+			return nil, fmt.Errorf("no files match pattern %q: %w", arg, ErrFileNotFound)
+		}
+	}
+	return expanded, nil
+}
+
+// keepGlobMatch applies the hidden and temp file filters to a single match
+func keepGlobMatch(pattern, match string, opts GlobOptions) bool {
+	if !opts.IncludeTemp && recent.IsTemporaryFile(filepath.Base(match)) {
+		return false
+	}
+	if opts.IncludeHidden {
+		return true
+	}
+
+	// Only the part matched by wildcards is checked, and a pattern that asks
+	// for dotfiles (".*", "**/.env") gets them, like a shell glob
+	base, patternPart := doublestar.SplitPattern(filepath.ToSlash(filepath.Clean(pattern)))
+	if strings.HasPrefix(patternPart, ".") || strings.Contains(patternPart, "/.") {
+		return true
+	}
+	rel, err := filepath.Rel(filepath.FromSlash(base), match)
+	if err != nil {
+		return true
+	}
+	for _, part := range strings.Split(filepath.ToSlash(rel), "/") {
+		if strings.HasPrefix(part, ".") {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,97 @@
+package clippy
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+func TestExpandGlobs(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.png", "sub/b.png", "sub/deep/c.png", "sub/notes.txt", "photo[1].png", ".env", ".git/config", "sub/big.zip.part"} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+			t.Fatalf("Failed to create file: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    []string
+		wantErr bool
+	}{
+		{"plain path untouched", []string{"missing.txt"}, []string{"missing.txt"}, false},
+		{"recursive pattern", []string{dir + "/**/*.png"}, []string{dir + "/a.png", dir + "/photo[1].png", dir + "/sub/b.png", dir + "/sub/deep/c.png"}, false},
+		{"single level pattern", []string{dir + "/sub/*"}, []string{dir + "/sub/b.png", dir + "/sub/deep", dir + "/sub/notes.txt"}, false},
+		{"literal path with metacharacters", []string{dir + "/photo[1].png"}, []string{dir + "/photo[1].png"}, false},
+		{"no matches", []string{dir + "/**/*.gif"}, nil, true},
+		{"explicit dotfile pattern", []string{dir + "/.e*"}, []string{dir + "/.env"}, false},
+		{"temp files skipped", []string{dir + "/sub/*.part"}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExpandGlobs(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ExpandGlobs(%v) error = %v, wantErr %v", tt.args, err, tt.wantErr)
+			}
+			sort.Strings(got)
+			if len(got) != len(tt.want) {
+				t.Fatalf("ExpandGlobs(%v) = %v, want %v", tt.args, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("ExpandGlobs(%v) = %v, want %v", tt.args, got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestExpandGlobsWithOptions(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.txt", ".env", ".git/config", "big.zip.part"} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+			t.Fatalf("Failed to create file: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		opts GlobOptions
+		want int
+	}{
+		{"defaults", GlobOptions{}, 1},
+		{"include hidden", GlobOptions{IncludeHidden: true}, 3},
+		{"include temp", GlobOptions{IncludeTemp: true}, 2},
+		{"include both", GlobOptions{IncludeHidden: true, IncludeTemp: true}, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExpandGlobsWithOptions([]string{dir + "/**/*"}, tt.opts)
+			if err != nil {
+				t.Fatalf("ExpandGlobsWithOptions returned error: %v", err)
+			}
+			files := 0
+			for _, path := range got {
+				if info, err := os.Stat(path); err == nil && !info.IsDir() {
+					files++
+				}
+			}
+			if files != tt.want {
+				t.Errorf("ExpandGlobsWithOptions(%+v) matched %d files, want %d: %v", tt.opts, files, tt.want, got)
+			}
+		})
+	}
+}
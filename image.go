@@ -0,0 +1,54 @@
+package clippy
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+
+	"github.com/neilberkman/clippy/pkg/clipboard"
+)
+
+// CopyImage puts img on the clipboard as PNG image data (not a file reference),
+// like copying an image in a browser
+func CopyImage(img image.Image) error {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return fmt.Errorf("could not encode PNG: %w", err)
+	}
+	if err := writeClipboardFlavors([]clipboard.Flavor{{Type: "public.png", Data: buf.Bytes()}}); err != nil {
+		return fmt.Errorf("could not copy image to clipboard: %w", err)
+	}
+	return nil
+}
+
+// CopyRawImage reads raw RGBA pixels (4 bytes per pixel, not premultiplied, rows
+// top to bottom, no padding) for a width x height image and copies them as a PNG.
+// It's meant for graphics tools that emit raw frames rather than encoded images.
+// The input must be exactly width*height*4 bytes.
+func CopyRawImage(r io.Reader, width, height int) error {
+	if width <= 0 || height <= 0 {
+		return fmt.Errorf("invalid image size %dx%d", width, height)
+	}
+	want := int64(width) * int64(height) * 4
+
+	// Read one byte past the expected size so oversized input is caught too
+	pixels, err := io.ReadAll(io.LimitReader(r, want+1))
+	if err != nil {
+		return fmt.Errorf("could not read pixel data: %w", err)
+	}
+	if int64(len(pixels)) != want {
+		if int64(len(pixels)) > want {
+			return fmt.Errorf("got more than %d bytes of pixel data, want exactly %d for %dx%d RGBA", want, want, width, height)
+		}
+		return fmt.Errorf("got %d bytes of pixel data, want %d for %dx%d RGBA (width*height*4)", len(pixels), want, width, height)
+	}
+
+	img := &image.NRGBA{
+		Pix:    pixels,
+		Stride: width * 4,
+		Rect:   image.Rect(0, 0, width, height),
+	}
+	return CopyImage(img)
+}
@@ -0,0 +1,49 @@
+package clippy
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestCopyRawImage(t *testing.T) {
+	mem := useMemoryClipboard(t)
+
+	// 2x1: one red pixel, one half-transparent blue pixel
+	pixels := []byte{255, 0, 0, 255, 0, 0, 255, 128}
+	if err := CopyRawImage(bytes.NewReader(pixels), 2, 1); err != nil {
+		t.Fatalf("CopyRawImage returned error: %v", err)
+	}
+
+	data, ok := mem.GetClipboardDataForType("public.png")
+	if !ok {
+		t.Fatalf("clipboard types = %v, want public.png", mem.GetClipboardTypes())
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("clipboard PNG doesn't decode: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != 2 || b.Dy() != 1 {
+		t.Errorf("image size = %dx%d, want 2x1", b.Dx(), b.Dy())
+	}
+	if r, g, b, _ := img.At(0, 0).RGBA(); r>>8 != 255 || g != 0 || b != 0 {
+		t.Errorf("pixel (0,0) = %d,%d,%d, want red", r>>8, g>>8, b>>8)
+	}
+
+	for _, tt := range []struct {
+		name   string
+		pixels []byte
+		want   string
+	}{
+		{"too short", pixels[:7], "got 7 bytes"},
+		{"too long", append(pixels, 0), "more than 8 bytes"},
+	} {
+		if err := CopyRawImage(bytes.NewReader(tt.pixels), 2, 1); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: CopyRawImage error = %v, want %q", tt.name, err, tt.want)
+		}
+	}
+	if err := CopyRawImage(bytes.NewReader(nil), 0, 1); err == nil {
+		t.Error("CopyRawImage(0x1) succeeded, want error")
+	}
+}
@@ -0,0 +1,60 @@
+// Package fsutil provides crash-safe file writing helpers.
+package fsutil
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// WriteFileAtomic writes data to path by writing a temp file in the same
+// directory and renaming it into place, so readers never see a partial file.
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	return WriteReaderAtomic(path, bytes.NewReader(data), perm)
+}
+
+// WriteReaderAtomic is like WriteFileAtomic but streams the content from r.
+// If path is a symlink, the link target is replaced rather than the link itself.
+func WriteReaderAtomic(path string, r io.Reader, perm os.FileMode) error {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+
+	// Temp file must live in the same directory for rename to be atomic
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("could not create temporary file: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+
+	// Remove the temp file on any failure before the rename
+	committed := false
+	defer func() {
+		if !committed {
+			_ = tmpFile.Close()
+			_ = os.Remove(tmpPath)
+		}
+	}()
+
+	if _, err := io.Copy(tmpFile, r); err != nil {
+		return fmt.Errorf("could not write temporary file: %w", err)
+	}
+	if err := tmpFile.Sync(); err != nil {
+		return fmt.Errorf("could not sync temporary file: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("could not close temporary file: %w", err)
+	}
+	// CreateTemp uses 0600, apply the requested permissions before exposing the file
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		return fmt.Errorf("could not set permissions on temporary file: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("could not move temporary file into place: %w", err)
+	}
+
+	committed = true
+	return nil
+}
@@ -0,0 +1,86 @@
+package fsutil
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "file.txt")
+
+	if err := os.WriteFile(path, []byte("old"), 0644); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+
+	if err := WriteFileAtomic(path, []byte("new content"), 0755); err != nil {
+		t.Fatalf("WriteFileAtomic returned error: %v", err)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	if string(got) != "new content" {
+		t.Errorf("content = %q, want %q", string(got), "new content")
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Failed to stat file: %v", err)
+	}
+	if info.Mode().Perm() != 0755 {
+		t.Errorf("mode = %v, want %v", info.Mode().Perm(), os.FileMode(0755))
+	}
+
+	// No temp files should be left behind
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("Failed to read dir: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected only the target file in %s, found %d entries", dir, len(entries))
+	}
+}
+
+func TestWriteReaderAtomicFollowsSymlink(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "target.txt")
+	link := filepath.Join(dir, "link.txt")
+
+	if err := os.WriteFile(target, []byte("old"), 0644); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+	if err := os.Symlink(target, link); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	if err := WriteReaderAtomic(link, strings.NewReader("via link"), 0644); err != nil {
+		t.Fatalf("WriteReaderAtomic returned error: %v", err)
+	}
+
+	info, err := os.Lstat(link)
+	if err != nil {
+		t.Fatalf("Failed to lstat link: %v", err)
+	}
+	if info.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("expected %s to remain a symlink", link)
+	}
+
+	got, err := os.ReadFile(target)
+	if err != nil {
+		t.Fatalf("Failed to read target: %v", err)
+	}
+	if string(got) != "via link" {
+		t.Errorf("target content = %q, want %q", string(got), "via link")
+	}
+}
+
+func TestWriteFileAtomicMissingDirectory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing", "file.txt")
+	if err := WriteFileAtomic(path, []byte("data"), 0644); err == nil {
+		t.Error("expected error when parent directory does not exist")
+	}
+}
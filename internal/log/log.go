@@ -1,14 +1,34 @@
 package log
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"strings"
+	"time"
+)
+
+// Format selects how log lines are written
+type Format int
+
+const (
+	// FormatText writes plain lines with "DEBUG:", "Warning:" and "Error:" prefixes
+	FormatText Format = iota
+	// FormatJSON writes one JSON object per line with time, level and msg fields
+	FormatJSON
 )
 
 // Config holds logging configuration
 type Config struct {
 	Verbose bool
 	Debug   bool
+
+	Output    io.Writer // Verbose, Debug and Print output (nil = stdout)
+	ErrOutput io.Writer // Error, Warn, Warning and PrintErr output (nil = stderr)
+	Format    Format    // FormatText (default) or FormatJSON
+	OnError   func()    // Called after every Error, e.g. to sound an alert (optional)
+	Plain     bool      // Drop leading status emoji such as "✅ " from messages
 }
 
 // Logger provides logging functionality
@@ -18,42 +38,109 @@ type Logger struct {
 
 // New creates a new logger with the given configuration
 func New(config Config) *Logger {
+	if config.Output == nil {
+		config.Output = os.Stdout
+	}
+	if config.ErrOutput == nil {
+		config.ErrOutput = os.Stderr
+	}
 	return &Logger{config: config}
 }
 
-// Error prints an error message and exits
+// jsonLine is the shape of a FormatJSON log line
+type jsonLine struct {
+	Time  string `json:"time"`
+	Level string `json:"level"`
+	Msg   string `json:"msg"`
+}
+
+// statusMarks are the emoji messages may start with; Plain mode drops them
+var statusMarks = []string{"✅", "⚠️", "❌"}
+
+// stripStatusMark removes a leading status emoji and the spaces after it
+func stripStatusMark(msg string) string {
+	for _, mark := range statusMarks {
+		if rest, ok := strings.CutPrefix(msg, mark); ok {
+			return strings.TrimLeft(rest, " ")
+		}
+	}
+	return msg
+}
+
+// write formats one message. Text lines get prefix; JSON lines record level instead.
+func (l *Logger) write(w io.Writer, level, prefix, format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	if l.config.Plain {
+		msg = stripStatusMark(msg)
+	}
+	if l.config.Format == FormatJSON {
+		line, err := json.Marshal(jsonLine{
+			Time:  time.Now().Format(time.RFC3339Nano),
+			Level: level,
+			Msg:   msg,
+		})
+		if err == nil {
+			fmt.Fprintln(w, string(line))
+			return
+		}
+	}
+	fmt.Fprintln(w, prefix+msg)
+}
+
+// Error prints an error message. It doesn't exit; callers that can't continue
+// exit themselves, so the logger is safe to use inside long-running processes.
 func (l *Logger) Error(format string, args ...interface{}) {
-	fmt.Fprintf(os.Stderr, "Error: "+format+"\n", args...)
-	os.Exit(1)
+	l.write(l.config.ErrOutput, "error", "Error: ", format, args...)
+	if l.config.OnError != nil {
+		l.config.OnError()
+	}
 }
 
 // Verbose prints a message if verbose mode is enabled
 func (l *Logger) Verbose(format string, args ...interface{}) {
 	if l.config.Verbose {
-		fmt.Printf(format+"\n", args...)
+		l.write(l.config.Output, "info", "", format, args...)
 	}
 }
 
 // Debug prints a message if debug mode is enabled
 func (l *Logger) Debug(format string, args ...interface{}) {
 	if l.config.Debug {
-		fmt.Printf("DEBUG: "+format+"\n", args...)
+		l.write(l.config.Output, "debug", "DEBUG: ", format, args...)
 	}
 }
 
+// Timer starts timing a phase and returns a function that logs its duration in
+// debug mode. Typical use: defer logger.Timer("Spotlight query")().
+// When debug is off the returned function does nothing.
+func (l *Logger) Timer(phase string) func() {
+	if !l.config.Debug {
+		return func() {}
+	}
+	start := time.Now()
+	return func() {
+		l.Debug("%s took %v", phase, time.Since(start).Round(time.Microsecond))
+	}
+}
+
+// Warn always prints a warning, for problems the user should see even without -v
+func (l *Logger) Warn(format string, args ...interface{}) {
+	l.write(l.config.ErrOutput, "warn", "Warning: ", format, args...)
+}
+
 // Warning prints a warning message to stderr if verbose mode is enabled
 func (l *Logger) Warning(format string, args ...interface{}) {
 	if l.config.Verbose {
-		fmt.Fprintf(os.Stderr, "Warning: "+format+"\n", args...)
+		l.write(l.config.ErrOutput, "warn", "Warning: ", format, args...)
 	}
 }
 
 // Print always prints a message (used for required output)
 func (l *Logger) Print(format string, args ...interface{}) {
-	fmt.Printf(format+"\n", args...)
+	l.write(l.config.Output, "info", "", format, args...)
 }
 
 // PrintErr always prints to stderr (used for required errors/warnings)
 func (l *Logger) PrintErr(format string, args ...interface{}) {
-	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	l.write(l.config.ErrOutput, "error", "", format, args...)
 }
@@ -0,0 +1,77 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestLoggerText(t *testing.T) {
+	var out, errOut bytes.Buffer
+	l := New(Config{Verbose: true, Output: &out, ErrOutput: &errOut})
+
+	l.Verbose("copied %d files", 2)
+	l.Debug("hidden without debug")
+	l.Warn("disk %s", "full")
+
+	if got := out.String(); got != "copied 2 files\n" {
+		t.Errorf("Output = %q, want %q", got, "copied 2 files\n")
+	}
+	if got := errOut.String(); got != "Warning: disk full\n" {
+		t.Errorf("ErrOutput = %q, want %q", got, "Warning: disk full\n")
+	}
+}
+
+func TestLoggerOnError(t *testing.T) {
+	var errOut bytes.Buffer
+	calls := 0
+	l := New(Config{ErrOutput: &errOut, OnError: func() { calls++ }})
+
+	l.Warn("not an error")
+	l.Error("failed")
+
+	if calls != 1 {
+		t.Errorf("OnError called %d times, want 1", calls)
+	}
+}
+
+func TestLoggerJSON(t *testing.T) {
+	var out, errOut bytes.Buffer
+	l := New(Config{Debug: true, Output: &out, ErrOutput: &errOut, Format: FormatJSON})
+
+	l.Debug("walk took %v", "3ms")
+	l.Warn("careful")
+
+	tests := []struct {
+		buf   *bytes.Buffer
+		level string
+		msg   string
+	}{
+		{&out, "debug", "walk took 3ms"},
+		{&errOut, "warn", "careful"},
+	}
+	for _, tt := range tests {
+		var line jsonLine
+		if err := json.Unmarshal([]byte(strings.TrimSpace(tt.buf.String())), &line); err != nil {
+			t.Fatalf("output %q is not a JSON line: %v", tt.buf.String(), err)
+		}
+		if line.Level != tt.level || line.Msg != tt.msg || line.Time == "" {
+			t.Errorf("got %+v, want level %q msg %q with a time", line, tt.level, tt.msg)
+		}
+	}
+}
+
+func TestLoggerPlain(t *testing.T) {
+	var out bytes.Buffer
+	l := New(Config{Verbose: true, Output: &out, Plain: true})
+
+	l.Verbose("✅ Copied %s", "report.pdf")
+	l.Verbose("⚠️  WARN")
+	l.Verbose("Copied ✅ inside")
+
+	want := "Copied report.pdf\nWARN\nCopied ✅ inside\n"
+	if got := out.String(); got != want {
+		t.Errorf("Output = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,104 @@
+package clippy
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/neilberkman/clippy/internal/fsutil"
+)
+
+// weblocURLPattern finds the URL in an XML .webloc property list
+var weblocURLPattern = regexp.MustCompile(`<key>URL</key>\s*<string>([^<]*)</string>`)
+
+// isLinkFile reports whether path is a bookmark file: a macOS .webloc or a
+// Windows .url internet shortcut
+func isLinkFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".webloc", ".url":
+		return true
+	}
+	return false
+}
+
+// SaveLinkFile writes a bookmark file pointing at rawURL and returns its path.
+// A name ending in .url gets a Windows internet shortcut; anything else a macOS
+// .webloc, adding the extension if missing. An existing file is never
+// overwritten: Finder-style numbering ("link 2.webloc") picks a free name.
+func SaveLinkFile(rawURL string, name string) (string, error) {
+	if strings.TrimSpace(rawURL) == "" {
+		return "", fmt.Errorf("no URL to save")
+	}
+
+	var data []byte
+	if strings.EqualFold(filepath.Ext(name), ".url") {
+		data = []byte("[InternetShortcut]\r\nURL=" + rawURL + "\r\n")
+	} else {
+		if !strings.EqualFold(filepath.Ext(name), ".webloc") {
+			name += ".webloc"
+		}
+		var escaped strings.Builder
+		if err := xml.EscapeText(&escaped, []byte(rawURL)); err != nil {
+			return "", fmt.Errorf("could not encode URL: %w", err)
+		}
+		data = []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>URL</key>
+	<string>` + escaped.String() + `</string>
+</dict>
+</plist>
+`)
+	}
+
+	path := findAvailableFilename(name, false)
+	if skipForDryRun("save a link to %s as %s", rawURL, path) {
+		return path, nil
+	}
+	if err := fsutil.WriteFileAtomic(path, data, 0644); err != nil {
+		return "", fmt.Errorf("could not write link file %s: %w", path, err)
+	}
+	return path, nil
+}
+
+// ReadLinkFile returns the URL a .webloc (XML property list) or .url file points at
+func ReadLinkFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("could not read link file %s: %w", path, err)
+	}
+	link := parseLinkFile(data, path)
+	if link == "" {
+		return "", fmt.Errorf("no URL found in %s", path)
+	}
+	return link, nil
+}
+
+// parseLinkFile extracts the URL from bookmark file content, or returns ""
+func parseLinkFile(data []byte, path string) string {
+	if strings.EqualFold(filepath.Ext(path), ".url") {
+		for _, line := range strings.Split(string(data), "\n") {
+			line = strings.TrimSpace(line)
+			if len(line) > 4 && strings.EqualFold(line[:4], "URL=") {
+				return strings.TrimSpace(line[4:])
+			}
+		}
+		return ""
+	}
+
+	match := weblocURLPattern.FindSubmatch(data)
+	if match == nil {
+		return ""
+	}
+	var link struct {
+		Value string `xml:",chardata"`
+	}
+	if err := xml.Unmarshal([]byte("<s>"+string(match[1])+"</s>"), &link); err != nil {
+		return ""
+	}
+	return strings.TrimSpace(link.Value)
+}
@@ -0,0 +1,72 @@
+package clippy
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSaveAndReadLinkFile(t *testing.T) {
+	dir := t.TempDir()
+	link := "https://example.com/search?q=a&b=<c>"
+
+	webloc, err := SaveLinkFile(link, filepath.Join(dir, "Example"))
+	if err != nil {
+		t.Fatalf("SaveLinkFile returned error: %v", err)
+	}
+	if filepath.Base(webloc) != "Example.webloc" {
+		t.Errorf("SaveLinkFile path = %s, want Example.webloc", webloc)
+	}
+	if got, err := ReadLinkFile(webloc); err != nil || got != link {
+		t.Errorf("ReadLinkFile(.webloc) = %q, %v, want %q", got, err, link)
+	}
+
+	// A second save doesn't overwrite the first
+	again, err := SaveLinkFile("https://example.com/other", filepath.Join(dir, "Example.webloc"))
+	if err != nil {
+		t.Fatalf("SaveLinkFile returned error: %v", err)
+	}
+	if filepath.Base(again) != "Example 2.webloc" {
+		t.Errorf("second save path = %s, want Example 2.webloc", again)
+	}
+
+	shortcut, err := SaveLinkFile(link, filepath.Join(dir, "Example.url"))
+	if err != nil {
+		t.Fatalf("SaveLinkFile returned error: %v", err)
+	}
+	data, _ := os.ReadFile(shortcut)
+	if !strings.HasPrefix(string(data), "[InternetShortcut]") {
+		t.Errorf(".url content = %q, want an [InternetShortcut] section", data)
+	}
+	if got, err := ReadLinkFile(shortcut); err != nil || got != link {
+		t.Errorf("ReadLinkFile(.url) = %q, %v, want %q", got, err, link)
+	}
+
+	empty := filepath.Join(dir, "empty.webloc")
+	if err := os.WriteFile(empty, []byte("<plist></plist>"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadLinkFile(empty); err == nil {
+		t.Error("ReadLinkFile on a file without a URL succeeded, want error")
+	}
+}
+
+func TestCopyLinkFileAsText(t *testing.T) {
+	mem := useMemoryClipboard(t)
+	path, err := SaveLinkFile("https://example.com/page", filepath.Join(t.TempDir(), "Page"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := CopyWithResultAndOptions(path, true, CopyOptions{})
+	if err != nil {
+		t.Fatalf("CopyWithResultAndOptions returned error: %v", err)
+	}
+	if result.Method != "link" || !result.AsText {
+		t.Errorf("result = %+v, want the link copied as text", result)
+	}
+	if text, ok := mem.GetText(); !ok || text != "https://example.com/page" {
+		t.Errorf("clipboard text = %q, want the URL", text)
+	}
+}
@@ -0,0 +1,102 @@
+package clippy
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// ErrLockTimeout is returned when another process holds the clipboard lock for
+// longer than LockOptions.Timeout
+var ErrLockTimeout = errors.New("timed out waiting for the clipboard lock")
+
+// LockOptions controls the optional cross-process clipboard lock. When enabled,
+// every clipboard write (and temp file cleanup, which reads the clipboard before
+// deleting) holds an exclusive flock on Path, so parallel clippy and pasty runs
+// take turns instead of interleaving pasteboard operations.
+type LockOptions struct {
+	Path    string        // Lock file; empty disables locking
+	Timeout time.Duration // How long to wait for another process (values below 1 use DefaultLockTimeout)
+}
+
+// DefaultLockTimeout is how long to wait for the lock when no timeout is set
+const DefaultLockTimeout = 10 * time.Second
+
+// lockPollInterval is how often a held lock is retried
+const lockPollInterval = 25 * time.Millisecond
+
+var (
+	lockOptions LockOptions
+	// processLock serializes goroutines, which all share one flock
+	processLock sync.Mutex
+)
+
+// DefaultLockPath returns the lock file in tempDir (os.TempDir() if empty)
+func DefaultLockPath(tempDir string) string {
+	if tempDir == "" {
+		tempDir = os.TempDir()
+	}
+	return filepath.Join(tempDir, "clippy.lock")
+}
+
+// SetLockOptions changes the clipboard lock and returns the previous setting.
+// Locking is off by default.
+func SetLockOptions(opts LockOptions) LockOptions {
+	previous := lockOptions
+	lockOptions = opts
+	return previous
+}
+
+// LockClipboard takes the clipboard lock, waiting up to the timeout for other
+// processes, and returns a function that releases it. It does nothing when
+// locking is disabled.
+func LockClipboard() (unlock func(), err error) {
+	opts := lockOptions
+	if opts.Path == "" {
+		return func() {}, nil
+	}
+	timeout := opts.Timeout
+	if timeout < 1 {
+		timeout = DefaultLockTimeout
+	}
+
+	processLock.Lock()
+	f, err := acquireFileLock(opts.Path, timeout)
+	if err != nil {
+		processLock.Unlock()
+		return nil, err
+	}
+	return func() {
+		_ = syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+		_ = f.Close()
+		processLock.Unlock()
+	}, nil
+}
+
+// acquireFileLock opens path and polls for an exclusive flock until timeout
+func acquireFileLock(path string, timeout time.Duration) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("could not open lock file: %w", err)
+	}
+	deadline := time.Now().Add(timeout)
+	for {
+		err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+		if err == nil {
+			return f, nil
+		}
+		if !errors.Is(err, syscall.EWOULDBLOCK) {
+			_ = f.Close()
+			return nil, fmt.Errorf("could not lock %s: %w", path, err)
+		}
+		if time.Now().After(deadline) {
+			_ = f.Close()
+			return nil, fmt.Errorf("%w after %s (held by another clippy or pasty; lock file %s)", ErrLockTimeout, timeout, path)
+		}
+		time.Sleep(lockPollInterval)
+	}
+}
@@ -0,0 +1,59 @@
+package clippy
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestLockClipboard(t *testing.T) {
+	mem := useMemoryClipboard(t)
+	path := filepath.Join(t.TempDir(), "clippy.lock")
+	previous := SetLockOptions(LockOptions{Path: path, Timeout: 50 * time.Millisecond})
+	defer SetLockOptions(previous)
+
+	// Another process holding the lock makes writes wait, then fail
+	other, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = other.Close() }()
+	if err := syscall.Flock(int(other.Fd()), syscall.LOCK_EX); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	if err := CopyText("blocked"); !errors.Is(err, ErrLockTimeout) {
+		t.Errorf("CopyText while locked = %v, want ErrLockTimeout", err)
+	}
+	if waited := time.Since(start); waited < 50*time.Millisecond {
+		t.Errorf("CopyText gave up after %s, want it to wait for the timeout", waited)
+	}
+
+	// Once it is released, writes go through and release the lock again
+	if err := syscall.Flock(int(other.Fd()), syscall.LOCK_UN); err != nil {
+		t.Fatal(err)
+	}
+	if err := CopyText("unblocked"); err != nil {
+		t.Fatalf("CopyText after unlock returned error: %v", err)
+	}
+	if text, _ := mem.GetText(); text != "unblocked" {
+		t.Errorf("clipboard text = %q, want %q", text, "unblocked")
+	}
+	if err := syscall.Flock(int(other.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		t.Errorf("lock still held after the write: %v", err)
+	}
+}
+
+func TestLockClipboardDisabled(t *testing.T) {
+	previous := SetLockOptions(LockOptions{})
+	defer SetLockOptions(previous)
+
+	unlock, err := LockClipboard()
+	if err != nil {
+		t.Fatalf("LockClipboard with locking disabled returned error: %v", err)
+	}
+	unlock()
+}
@@ -0,0 +1,59 @@
+package clippy
+
+import (
+	"fmt"
+	"time"
+
+	htmltomarkdown "github.com/JohannesKaufmann/html-to-markdown/v2"
+	"github.com/neilberkman/clippy/internal/fsutil"
+	"github.com/neilberkman/clippy/pkg/clipboard"
+)
+
+// HTMLToMarkdown converts HTML to CommonMark. Headings, lists, links, emphasis
+// and code survive; scripts, styles and tags with no Markdown equivalent are
+// dropped, keeping their text.
+func HTMLToMarkdown(html string) (string, error) {
+	markdown, err := htmltomarkdown.ConvertString(html)
+	if err != nil {
+		return "", fmt.Errorf("could not convert HTML to Markdown: %w", err)
+	}
+	return markdown, nil
+}
+
+// ClipboardMarkdown returns the clipboard's public.html representation converted
+// to Markdown, or its plain text when there is no HTML (plain text is already
+// valid Markdown)
+func ClipboardMarkdown() (string, error) {
+	if data, ok := clipboard.GetClipboardDataForType("public.html"); ok && len(data) > 0 {
+		return HTMLToMarkdown(string(data))
+	}
+	if text, ok := GetText(); ok {
+		return text, nil
+	}
+	return "", fmt.Errorf("no HTML or text found on clipboard")
+}
+
+// PasteMarkdown writes the clipboard content as Markdown (see ClipboardMarkdown)
+// to stdout, or to destination; a directory gets a clipboard-<time>.md file
+func PasteMarkdown(destination string, opts PasteOptions) (*PasteResult, error) {
+	markdown, err := ClipboardMarkdown()
+	if err != nil {
+		return nil, err
+	}
+
+	if destination == "" {
+		fmt.Print(markdown)
+		return &PasteResult{Type: "text", Content: markdown}, nil
+	}
+
+	defaultFilename := fmt.Sprintf("clipboard-%s.md", time.Now().Format("2006-01-02-150405"))
+	destPath := resolveDestinationPath(destination, defaultFilename, false, opts.Force)
+	if err := fsutil.WriteFileAtomic(destPath, []byte(markdown), 0644); err != nil {
+		return nil, fmt.Errorf("could not write to file %s: %w", destPath, err)
+	}
+	return &PasteResult{
+		Type:    "text",
+		Content: markdown,
+		Files:   []string{destPath},
+	}, nil
+}
@@ -0,0 +1,65 @@
+package clippy
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/neilberkman/clippy/pkg/clipboard"
+)
+
+func TestHTMLToMarkdown(t *testing.T) {
+	html := `<h1>Title</h1><p>See <a href="https://example.com">the docs</a> and <code>go test</code>.</p>` +
+		`<ul><li>one</li><li>two</li></ul><script>alert(1)</script><font color="red">kept</font>`
+	got, err := HTMLToMarkdown(html)
+	if err != nil {
+		t.Fatalf("HTMLToMarkdown returned error: %v", err)
+	}
+	for _, want := range []string{"# Title", "[the docs](https://example.com)", "`go test`", "- one", "- two", "kept"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Markdown is missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "alert") || strings.Contains(got, "<font") {
+		t.Errorf("Markdown kept unsupported tags:\n%s", got)
+	}
+}
+
+func TestPasteMarkdown(t *testing.T) {
+	mem := useMemoryClipboard(t)
+	dir := t.TempDir()
+
+	if err := mem.CopyFlavors([]clipboard.Flavor{
+		{Type: "public.html", Data: []byte("<h2>Notes</h2><p><strong>Bold</strong></p>")},
+		{Type: clipboard.PlainTextType, Data: []byte("Notes Bold")},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	result, err := PasteMarkdown(dir, PasteOptions{})
+	if err != nil {
+		t.Fatalf("PasteMarkdown returned error: %v", err)
+	}
+	if len(result.Files) != 1 || filepath.Ext(result.Files[0]) != ".md" {
+		t.Fatalf("Files = %v, want one .md file in %s", result.Files, dir)
+	}
+	data, err := os.ReadFile(result.Files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(data); !strings.Contains(got, "## Notes") || !strings.Contains(got, "**Bold**") {
+		t.Errorf("pasted Markdown = %q, want the converted HTML", got)
+	}
+
+	// Without HTML the plain text is used as is
+	if err := mem.CopyText("just *text*"); err != nil {
+		t.Fatal(err)
+	}
+	dest := filepath.Join(dir, "plain.md")
+	if _, err := PasteMarkdown(dest, PasteOptions{}); err != nil {
+		t.Fatalf("PasteMarkdown returned error: %v", err)
+	}
+	if data, _ := os.ReadFile(dest); string(data) != "just *text*" {
+		t.Errorf("plain text fallback = %q, want %q", data, "just *text*")
+	}
+}
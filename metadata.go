@@ -0,0 +1,38 @@
+package clippy
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// preserveMetadata gives dst (a copy of src) the source's access and modification
+// times and, if requested, its extended attributes. Folders are walked so every
+// copied entry matches its source. Symlinks are skipped.
+func preserveMetadata(src, dst string, opts PasteOptions) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return nil
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		if opts.PreserveXattrs {
+			if err := copyXattrs(path, target); err != nil {
+				return fmt.Errorf("could not copy extended attributes to %s: %w", target, err)
+			}
+		}
+		if opts.PreserveTimes {
+			if err := os.Chtimes(target, accessTime(info), info.ModTime()); err != nil {
+				return fmt.Errorf("could not set times on %s: %w", target, err)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,65 @@
+//go:build darwin
+
+package clippy
+
+import (
+	"os"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// accessTime returns the last access time recorded in info
+func accessTime(info os.FileInfo) time.Time {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		return time.Unix(stat.Atimespec.Sec, stat.Atimespec.Nsec)
+	}
+	return info.ModTime()
+}
+
+// copyXattrs copies every extended attribute of src (Finder tags, the
+// quarantine flag, "Where from" download info, ...) to dst
+func copyXattrs(src, dst string) error {
+	size, err := unix.Listxattr(src, nil)
+	if err != nil || size == 0 {
+		return err
+	}
+	buf := make([]byte, size)
+	size, err = unix.Listxattr(src, buf)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range splitXattrNames(buf[:size]) {
+		valueSize, err := unix.Getxattr(src, name, nil)
+		if err != nil {
+			return err
+		}
+		value := make([]byte, valueSize)
+		if valueSize > 0 {
+			if valueSize, err = unix.Getxattr(src, name, value); err != nil {
+				return err
+			}
+		}
+		if err := unix.Setxattr(dst, name, value[:valueSize], 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// splitXattrNames splits the NUL-separated list returned by listxattr
+func splitXattrNames(buf []byte) []string {
+	var names []string
+	start := 0
+	for i, b := range buf {
+		if b == 0 {
+			if i > start {
+				names = append(names, string(buf[start:i]))
+			}
+			start = i + 1
+		}
+	}
+	return names
+}
@@ -0,0 +1,18 @@
+//go:build !darwin
+
+package clippy
+
+import (
+	"os"
+	"time"
+)
+
+// accessTime falls back to the modification time outside macOS
+func accessTime(info os.FileInfo) time.Time {
+	return info.ModTime()
+}
+
+// copyXattrs does nothing outside macOS
+func copyXattrs(src, dst string) error {
+	return nil
+}
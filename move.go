@@ -0,0 +1,102 @@
+package clippy
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// protectedDirs are system locations pasty refuses to move files out of unless
+// PasteOptions.AllowProtected is set
+var protectedDirs = []string{
+	"/Applications",
+	"/Library",
+	"/System",
+	"/bin",
+	"/cores",
+	"/etc",
+	"/private/etc",
+	"/sbin",
+	"/usr",
+}
+
+// IsProtectedPath reports whether path is a system location that shouldn't be
+// deleted after a move: the filesystem root, anything under the system folders,
+// the home directory itself, or anything under ~/Library.
+func IsProtectedPath(path string) bool {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return true
+	}
+	if absPath == "/" {
+		return true
+	}
+	for _, dir := range protectedDirs {
+		if isWithinDir(absPath, dir) {
+			return true
+		}
+	}
+	if homeDir, err := os.UserHomeDir(); err == nil {
+		if absPath == homeDir || isWithinDir(absPath, filepath.Join(homeDir, "Library")) {
+			return true
+		}
+	}
+	return false
+}
+
+// removeMovedFiles deletes each source after checking its copy matches in size.
+// Every copy is verified before anything is deleted, so a bad copy leaves all
+// originals in place.
+func removeMovedFiles(sources, copies []string) error {
+	if len(sources) != len(copies) {
+		return fmt.Errorf("not moving: only %d of %d files were copied", len(copies), len(sources))
+	}
+	for i, src := range sources {
+		if sameFile(src, copies[i]) {
+			return fmt.Errorf("not moving: %s is its own copy", src)
+		}
+		srcSize, err := pathSize(src)
+		if err != nil {
+			return fmt.Errorf("not moving: could not read %s: %w", src, err)
+		}
+		copySize, err := pathSize(copies[i])
+		if err != nil {
+			return fmt.Errorf("not moving: could not verify copy %s: %w", copies[i], err)
+		}
+		if srcSize != copySize {
+			return fmt.Errorf("not moving: copy %s is %d bytes but %s is %d bytes", copies[i], copySize, src, srcSize)
+		}
+	}
+
+	for _, src := range sources {
+		if skipForDryRun("remove moved file %s", src) {
+			continue
+		}
+		if err := os.RemoveAll(src); err != nil {
+			return fmt.Errorf("could not remove %s after copying: %w", src, err)
+		}
+	}
+	return nil
+}
+
+// sameFile reports whether a and b are the same existing file, following symlinks
+func sameFile(a, b string) bool {
+	aInfo, errA := os.Stat(a)
+	bInfo, errB := os.Stat(b)
+	return errA == nil && errB == nil && os.SameFile(aInfo, bInfo)
+}
+
+// pathSize returns the size of a file, or the total size of the files in a directory
+func pathSize(path string) (int64, error) {
+	var total int64
+	err := filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			total += info.Size()
+		}
+		return nil
+	})
+	return total, err
+}
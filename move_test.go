@@ -0,0 +1,113 @@
+package clippy
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestIsProtectedPath(t *testing.T) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/", true},
+		{"/System/Library/CoreServices", true},
+		{"/usr/local/bin/clippy", true},
+		{"/Applications/Safari.app", true},
+		{homeDir, true},
+		{filepath.Join(homeDir, "Library", "Preferences", "x.plist"), true},
+		{filepath.Join(homeDir, "Downloads", "report.pdf"), false},
+		{"/Users/Shared/LibraryBooks/a.txt", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsProtectedPath(tt.path); got != tt.want {
+			t.Errorf("IsProtectedPath(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestPasteToFileMove(t *testing.T) {
+	mem := useMemoryClipboard(t)
+	srcDir := t.TempDir()
+	var files []string
+	for _, name := range []string{"a.txt", "b.txt"} {
+		path := filepath.Join(srcDir, name)
+		if err := os.WriteFile(path, []byte(name), 0644); err != nil {
+			t.Fatalf("Failed to create file: %v", err)
+		}
+		files = append(files, path)
+	}
+	_ = mem.CopyFiles(files)
+
+	destDir := t.TempDir()
+	result, err := PasteToFileWithOptions(destDir, PasteOptions{Move: true})
+	if err != nil {
+		t.Fatalf("PasteToFileWithOptions returned error: %v", err)
+	}
+	if !result.Moved || result.FilesRead != 2 {
+		t.Errorf("PasteToFileWithOptions result = %+v, want 2 moved files", result)
+	}
+	want := []string{filepath.Join(destDir, "a.txt"), filepath.Join(destDir, "b.txt")}
+	if !reflect.DeepEqual(result.Copies, want) {
+		t.Errorf("PasteResult.Copies = %v, want %v", result.Copies, want)
+	}
+	for _, src := range files {
+		if _, err := os.Stat(src); !os.IsNotExist(err) {
+			t.Errorf("source %s still exists after move", src)
+		}
+		if got, _ := os.ReadFile(filepath.Join(destDir, filepath.Base(src))); string(got) != filepath.Base(src) {
+			t.Errorf("moved %s content = %q", src, got)
+		}
+	}
+}
+
+func TestRemoveMovedFilesRefusesMismatchedCopy(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src.txt")
+	dst := filepath.Join(dir, "dst.txt")
+	if err := os.WriteFile(src, []byte("original"), 0644); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+	if err := os.WriteFile(dst, []byte("short"), 0644); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+
+	if err := removeMovedFiles([]string{src}, []string{dst}); err == nil {
+		t.Error("removeMovedFiles succeeded with a mismatched copy, want error")
+	}
+	if _, err := os.Stat(src); err != nil {
+		t.Errorf("source was removed despite a mismatched copy: %v", err)
+	}
+}
+
+func TestPasteMoveOntoItselfKeepsFile(t *testing.T) {
+	mem := useMemoryClipboard(t)
+	dir := t.TempDir()
+	src := filepath.Join(dir, "report.txt")
+	if err := os.WriteFile(src, []byte("only copy"), 0644); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+	_ = mem.CopyFiles([]string{src})
+
+	if _, err := PasteToFileWithOptions(dir, PasteOptions{Move: true, Force: true}); err == nil {
+		t.Error("moving a file into its own folder succeeded, want error")
+	}
+	if data, err := os.ReadFile(src); err != nil || string(data) != "only copy" {
+		t.Errorf("source after refused move = %q, %v, want it intact", data, err)
+	}
+
+	if err := removeMovedFiles([]string{src}, []string{src}); err == nil {
+		t.Error("removeMovedFiles accepted a source as its own copy, want error")
+	}
+	if _, err := os.Stat(src); err != nil {
+		t.Errorf("source was removed as its own copy: %v", err)
+	}
+}
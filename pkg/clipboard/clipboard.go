@@ -1,3 +1,5 @@
+//go:build darwin && cgo
+
 package clipboard
 
 /*
@@ -10,6 +12,14 @@ package clipboard
 #import <CoreServices/CoreServices.h>
 #import <UniformTypeIdentifiers/UniformTypeIdentifiers.h>
 
+// Returns the named pasteboard, or the general pasteboard when name is NULL or empty
+static NSPasteboard *pasteboardNamed(const char *name) {
+    if (name == NULL || name[0] == '\0') {
+        return [NSPasteboard generalPasteboard];
+    }
+    return [NSPasteboard pasteboardWithName:[NSString stringWithUTF8String:name]];
+}
+
 // Helper function to wait for pasteboard to complete write operation
 static int waitForPasteboardChange(NSPasteboard *pasteboard, NSInteger initialChangeCount) {
     NSDate *timeoutDate = [NSDate dateWithTimeIntervalSinceNow:2.0]; // 2-second timeout
@@ -27,11 +37,11 @@ static int waitForPasteboardChange(NSPasteboard *pasteboard, NSInteger initialCh
 }
 
 // Function to copy a file reference to the clipboard
-int copyFile(const char *path) {
+int copyFile(const char *name, const char *path) {
     @autoreleasepool {
         [NSApplication sharedApplication]; // Initialize the app context
         NSURL *fileURL = [NSURL fileURLWithPath:[NSString stringWithUTF8String:path]];
-        NSPasteboard *pasteboard = [NSPasteboard generalPasteboard];
+        NSPasteboard *pasteboard = pasteboardNamed(name);
 
         // Get the current changeCount before operation
         NSInteger initialChangeCount = [pasteboard changeCount];
@@ -54,7 +64,7 @@ int copyFile(const char *path) {
 }
 
 // Function to copy multiple file references to the clipboard
-int copyFiles(const char **paths, int count) {
+int copyFiles(const char *name, const char **paths, int count) {
     @autoreleasepool {
         [NSApplication sharedApplication]; // Initialize the app context
         NSMutableArray *fileURLs = [NSMutableArray arrayWithCapacity:count];
@@ -64,7 +74,7 @@ int copyFiles(const char **paths, int count) {
             [fileURLs addObject:fileURL];
         }
 
-        NSPasteboard *pasteboard = [NSPasteboard generalPasteboard];
+        NSPasteboard *pasteboard = pasteboardNamed(name);
 
         // Get the current changeCount before operation
         NSInteger initialChangeCount = [pasteboard changeCount];
@@ -87,11 +97,11 @@ int copyFiles(const char **paths, int count) {
 }
 
 // Function to copy plain text content to the clipboard
-int copyText(const char *text) {
+int copyText(const char *name, const char *text) {
     @autoreleasepool {
         [NSApplication sharedApplication]; // Initialize the app context
         NSString *nsText = [NSString stringWithUTF8String:text];
-        NSPasteboard *pasteboard = [NSPasteboard generalPasteboard];
+        NSPasteboard *pasteboard = pasteboardNamed(name);
 
         // Get the current changeCount before operation
         NSInteger initialChangeCount = [pasteboard changeCount];
@@ -114,12 +124,12 @@ int copyText(const char *text) {
 }
 
 // Function to copy text with a specific UTI/type to the clipboard
-int copyTextWithType(const char *text, const char *typeIdentifier) {
+int copyTextWithType(const char *name, const char *text, const char *typeIdentifier) {
     @autoreleasepool {
         [NSApplication sharedApplication]; // Initialize the app context
         NSString *nsText = [NSString stringWithUTF8String:text];
         NSString *nsType = [NSString stringWithUTF8String:typeIdentifier];
-        NSPasteboard *pasteboard = [NSPasteboard generalPasteboard];
+        NSPasteboard *pasteboard = pasteboardNamed(name);
 
         // Get the current changeCount before operation
         NSInteger initialChangeCount = [pasteboard changeCount];
@@ -148,11 +158,77 @@ int copyTextWithType(const char *text, const char *typeIdentifier) {
     }
 }
 
+// Replace the clipboard contents with several representations of the same content
+int copyFlavors(const char *name, const char **types, const void **data, const int *lengths, int count) {
+    @autoreleasepool {
+        [NSApplication sharedApplication]; // Initialize the app context
+        NSPasteboard *pasteboard = pasteboardNamed(name);
+
+        // Get the current changeCount before operation
+        NSInteger initialChangeCount = [pasteboard changeCount];
+
+        // Declare every type up front so they land in a single transaction
+        NSMutableArray *nsTypes = [NSMutableArray arrayWithCapacity:count];
+        for (int i = 0; i < count; i++) {
+            [nsTypes addObject:[NSString stringWithUTF8String:types[i]]];
+        }
+        [pasteboard declareTypes:nsTypes owner:nil];
+
+        for (int i = 0; i < count; i++) {
+            NSData *nsData = [NSData dataWithBytes:data[i] length:lengths[i]];
+            if (![pasteboard setData:nsData forType:nsTypes[i]]) {
+                return -1; // Write operation failed to start
+            }
+        }
+
+        // Wait for pasteboard to complete
+        if (waitForPasteboardChange(pasteboard, initialChangeCount) != 0) {
+            return -2; // Timed out
+        }
+
+        return 0; // Success
+    }
+}
+
+// Add file references to the clipboard without clearing existing content
+int addFiles(const char *name, const char **paths, int count) {
+    @autoreleasepool {
+        [NSApplication sharedApplication]; // Initialize the app context
+        NSMutableArray *fileURLs = [NSMutableArray arrayWithCapacity:count];
+
+        for (int i = 0; i < count; i++) {
+            NSURL *fileURL = [NSURL fileURLWithPath:[NSString stringWithUTF8String:paths[i]]];
+            [fileURLs addObject:fileURL];
+        }
+
+        NSPasteboard *pasteboard = pasteboardNamed(name);
+
+        // No clearContents: the URLs become additional pasteboard items. The
+        // changeCount only moves when the contents are cleared, so there is
+        // nothing to wait for.
+        return [pasteboard writeObjects:fileURLs] ? 0 : -1;
+    }
+}
+
+// Add a text representation of the given type without clearing existing content
+int addText(const char *name, const char *text, const char *typeIdentifier) {
+    @autoreleasepool {
+        [NSApplication sharedApplication]; // Initialize the app context
+        NSString *nsText = [NSString stringWithUTF8String:text];
+        NSString *nsType = [NSString stringWithUTF8String:typeIdentifier];
+        NSPasteboard *pasteboard = pasteboardNamed(name);
+
+        // addTypes keeps the types already declared, unlike clearContents
+        [pasteboard addTypes:@[nsType] owner:nil];
+        return [pasteboard setString:nsText forType:nsType] ? 0 : -1;
+    }
+}
+
 // Get current clipboard file paths if any
-char** getClipboardFiles(int *count) {
+char** getClipboardFiles(const char *name, int *count) {
     @autoreleasepool {
         [NSApplication sharedApplication]; // Initialize the app context
-        NSPasteboard *pasteboard = [NSPasteboard generalPasteboard];
+        NSPasteboard *pasteboard = pasteboardNamed(name);
 
         NSArray *files = [pasteboard readObjectsForClasses:@[[NSURL class]]
                                                    options:@{NSPasteboardURLReadingFileURLsOnlyKey: @YES}];
@@ -173,10 +249,10 @@ char** getClipboardFiles(int *count) {
 }
 
 // Get clipboard text content if any
-char* getClipboardText() {
+char* getClipboardText(const char *name) {
     @autoreleasepool {
         [NSApplication sharedApplication]; // Initialize the app context
-        NSPasteboard *pasteboard = [NSPasteboard generalPasteboard];
+        NSPasteboard *pasteboard = pasteboardNamed(name);
         NSString *text = [pasteboard stringForType:NSPasteboardTypeString];
 
         if (text == nil) return NULL;
@@ -201,10 +277,10 @@ void freeString(char *str) {
 }
 
 // Clear the clipboard
-int clearClipboard() {
+int clearClipboard(const char *name) {
     @autoreleasepool {
         [NSApplication sharedApplication]; // Initialize the app context
-        NSPasteboard *pasteboard = [NSPasteboard generalPasteboard];
+        NSPasteboard *pasteboard = pasteboardNamed(name);
 
         // Get the current changeCount before operation
         NSInteger initialChangeCount = [pasteboard changeCount];
@@ -261,10 +337,10 @@ char* getUTIForFile(const char* path) {
 }
 
 // Get available types on clipboard
-char** getClipboardTypes(int *count) {
+char** getClipboardTypes(const char *name, int *count) {
     @autoreleasepool {
         [NSApplication sharedApplication]; // Initialize the app context
-        NSPasteboard *pasteboard = [NSPasteboard generalPasteboard];
+        NSPasteboard *pasteboard = pasteboardNamed(name);
         NSArray *types = [pasteboard types];
 
         *count = (int)[types count];
@@ -282,10 +358,10 @@ char** getClipboardTypes(int *count) {
 }
 
 // Get clipboard data for a specific type
-char* getClipboardDataForType(const char* type, int *length) {
+char* getClipboardDataForType(const char *name, const char* type, int *length) {
     @autoreleasepool {
         [NSApplication sharedApplication]; // Initialize the app context
-        NSPasteboard *pasteboard = [NSPasteboard generalPasteboard];
+        NSPasteboard *pasteboard = pasteboardNamed(name);
         NSString *typeString = [NSString stringWithUTF8String:type];
         NSData *data = [pasteboard dataForType:typeString];
 
@@ -302,16 +378,25 @@ char* getClipboardDataForType(const char* type, int *length) {
 }
 
 // Check if clipboard contains a specific type
-int clipboardContainsType(const char* type) {
+int clipboardContainsType(const char *name, const char* type) {
     @autoreleasepool {
         [NSApplication sharedApplication]; // Initialize the app context
-        NSPasteboard *pasteboard = [NSPasteboard generalPasteboard];
+        NSPasteboard *pasteboard = pasteboardNamed(name);
         NSString *typeString = [NSString stringWithUTF8String:type];
         NSArray *types = [pasteboard types];
         return [types containsObject:typeString] ? 1 : 0;
     }
 }
 
+// Get the pasteboard's change count, which increases each time its contents are replaced
+long getChangeCount(const char *name) {
+    @autoreleasepool {
+        [NSApplication sharedApplication]; // Initialize the app context
+        NSPasteboard *pasteboard = pasteboardNamed(name);
+        return (long)[pasteboard changeCount];
+    }
+}
+
 // Check if a UTI conforms to a parent type (e.g., check if UTI is text)
 int utiConformsTo(const char* uti, const char* parentType) {
     @autoreleasepool {
@@ -440,102 +525,190 @@ import (
 	"unsafe"
 )
 
-// CopyFile copies a single file reference to clipboard
-func CopyFile(path string) error {
+// systemManager is the ClipboardManager backed by a macOS pasteboard
+type systemManager struct {
+	name string // Pasteboard name; empty means the general pasteboard
+}
+
+// cName returns the pasteboard name for the C functions, or nil for the general
+// pasteboard. The caller frees it; C.free(nil) is a no-op.
+func (s systemManager) cName() *C.char {
+	if s.name == "" {
+		return nil
+	}
+	return C.CString(s.name)
+}
+
+// CopyFile implements ClipboardManager using NSPasteboard
+func (s systemManager) CopyFile(path string) error {
+	cName := s.cName()
+	defer C.free(unsafe.Pointer(cName))
 	cPath := C.CString(path)
 	defer C.free(unsafe.Pointer(cPath))
-	result := C.copyFile(cPath)
+	result := C.copyFile(cName, cPath)
 
 	switch result {
 	case 0:
 		return nil
 	case -1:
-		return fmt.Errorf("failed to write to clipboard")
+		return ErrWriteFailed
 	case -2:
-		return fmt.Errorf("clipboard operation timed out")
+		return ErrTimeout
 	default:
 		return fmt.Errorf("unknown clipboard error: %d", result)
 	}
 }
 
-// CopyFiles copies multiple file references to clipboard
-func CopyFiles(paths []string) error {
+// CopyFiles implements ClipboardManager using NSPasteboard
+func (s systemManager) CopyFiles(paths []string) error {
+	cName := s.cName()
+	defer C.free(unsafe.Pointer(cName))
 	cPaths := make([]*C.char, len(paths))
 	for i, path := range paths {
 		cPaths[i] = C.CString(path)
 		defer C.free(unsafe.Pointer(cPaths[i]))
 	}
-	result := C.copyFiles(&cPaths[0], C.int(len(cPaths)))
+	result := C.copyFiles(cName, &cPaths[0], C.int(len(cPaths)))
 
 	switch result {
 	case 0:
 		return nil
 	case -1:
-		return fmt.Errorf("failed to write to clipboard")
+		return ErrWriteFailed
 	case -2:
-		return fmt.Errorf("clipboard operation timed out")
+		return ErrTimeout
 	default:
 		return fmt.Errorf("unknown clipboard error: %d", result)
 	}
 }
 
-// CopyText copies text content to clipboard
-func CopyText(text string) error {
+// CopyText implements ClipboardManager using NSPasteboard
+func (s systemManager) CopyText(text string) error {
+	cName := s.cName()
+	defer C.free(unsafe.Pointer(cName))
 	cText := C.CString(text)
 	defer C.free(unsafe.Pointer(cText))
-	result := C.copyText(cText)
+	result := C.copyText(cName, cText)
 
 	switch result {
 	case 0:
 		return nil
 	case -1:
-		return fmt.Errorf("failed to write to clipboard")
+		return ErrWriteFailed
 	case -2:
-		return fmt.Errorf("clipboard operation timed out")
+		return ErrTimeout
 	default:
 		return fmt.Errorf("unknown clipboard error: %d", result)
 	}
 }
 
-// CopyTextWithType copies text with a specific UTI type to clipboard
-// Common types: "public.html", "public.json", "public.xml", "public.plain-text"
-func CopyTextWithType(text string, typeIdentifier string) error {
+// CopyTextWithType implements ClipboardManager using NSPasteboard
+func (s systemManager) CopyTextWithType(text string, typeIdentifier string) error {
+	cName := s.cName()
+	defer C.free(unsafe.Pointer(cName))
 	cText := C.CString(text)
 	defer C.free(unsafe.Pointer(cText))
 	cType := C.CString(typeIdentifier)
 	defer C.free(unsafe.Pointer(cType))
-	result := C.copyTextWithType(cText, cType)
+	result := C.copyTextWithType(cName, cText, cType)
+
+	switch result {
+	case 0:
+		return nil
+	case -1:
+		return ErrWriteFailed
+	case -2:
+		return ErrTimeout
+	default:
+		return fmt.Errorf("unknown clipboard error: %d", result)
+	}
+}
+
+// CopyFlavors implements ClipboardManager using NSPasteboard
+func (s systemManager) CopyFlavors(flavors []Flavor) error {
+	if len(flavors) == 0 {
+		return s.Clear()
+	}
+	cName := s.cName()
+	defer C.free(unsafe.Pointer(cName))
+	cTypes := make([]*C.char, len(flavors))
+	cData := make([]unsafe.Pointer, len(flavors))
+	cLengths := make([]C.int, len(flavors))
+	for i, flavor := range flavors {
+		cTypes[i] = C.CString(flavor.Type)
+		defer C.free(unsafe.Pointer(cTypes[i]))
+		cData[i] = C.CBytes(flavor.Data)
+		defer C.free(cData[i])
+		cLengths[i] = C.int(len(flavor.Data))
+	}
+	result := C.copyFlavors(cName, &cTypes[0], &cData[0], &cLengths[0], C.int(len(flavors)))
 
 	switch result {
 	case 0:
 		return nil
 	case -1:
-		return fmt.Errorf("failed to write to clipboard")
+		return ErrWriteFailed
 	case -2:
-		return fmt.Errorf("clipboard operation timed out")
+		return ErrTimeout
 	default:
 		return fmt.Errorf("unknown clipboard error: %d", result)
 	}
 }
 
-// Clear clears the clipboard
-func Clear() error {
-	result := C.clearClipboard()
+// AddFiles implements ClipboardManager using NSPasteboard
+func (s systemManager) AddFiles(paths []string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+	cName := s.cName()
+	defer C.free(unsafe.Pointer(cName))
+	cPaths := make([]*C.char, len(paths))
+	for i, path := range paths {
+		cPaths[i] = C.CString(path)
+		defer C.free(unsafe.Pointer(cPaths[i]))
+	}
+	if C.addFiles(cName, &cPaths[0], C.int(len(cPaths))) != 0 {
+		return ErrWriteFailed
+	}
+	return nil
+}
+
+// AddTextWithType implements ClipboardManager using NSPasteboard
+func (s systemManager) AddTextWithType(text string, typeIdentifier string) error {
+	cName := s.cName()
+	defer C.free(unsafe.Pointer(cName))
+	cText := C.CString(text)
+	defer C.free(unsafe.Pointer(cText))
+	cType := C.CString(typeIdentifier)
+	defer C.free(unsafe.Pointer(cType))
+	if C.addText(cName, cText, cType) != 0 {
+		return ErrWriteFailed
+	}
+	return nil
+}
+
+// Clear implements ClipboardManager using NSPasteboard
+func (s systemManager) Clear() error {
+	cName := s.cName()
+	defer C.free(unsafe.Pointer(cName))
+	result := C.clearClipboard(cName)
 
 	switch result {
 	case 0:
 		return nil
 	case -2:
-		return fmt.Errorf("clipboard operation timed out")
+		return ErrTimeout
 	default:
 		return fmt.Errorf("unknown clipboard error: %d", result)
 	}
 }
 
-// GetFiles returns file paths currently on clipboard
-func GetFiles() []string {
+// GetFiles implements ClipboardManager using NSPasteboard
+func (s systemManager) GetFiles() []string {
+	cName := s.cName()
+	defer C.free(unsafe.Pointer(cName))
 	var count C.int
-	cPaths := C.getClipboardFiles(&count)
+	cPaths := C.getClipboardFiles(cName, &count)
 	if cPaths == nil {
 		return nil
 	}
@@ -553,9 +726,11 @@ func GetFiles() []string {
 	return files
 }
 
-// GetText returns text content from clipboard
-func GetText() (string, bool) {
-	cText := C.getClipboardText()
+// GetText implements ClipboardManager using NSPasteboard
+func (s systemManager) GetText() (string, bool) {
+	cName := s.cName()
+	defer C.free(unsafe.Pointer(cName))
+	cText := C.getClipboardText(cName)
 	if cText == nil {
 		return "", false
 	}
@@ -577,10 +752,12 @@ func GetUTIForFile(path string) (string, bool) {
 	return C.GoString(cUTI), true
 }
 
-// GetClipboardTypes returns all available types on clipboard
-func GetClipboardTypes() []string {
+// GetClipboardTypes implements ClipboardManager using NSPasteboard
+func (s systemManager) GetClipboardTypes() []string {
+	cName := s.cName()
+	defer C.free(unsafe.Pointer(cName))
 	var count C.int
-	cTypes := C.getClipboardTypes(&count)
+	cTypes := C.getClipboardTypes(cName, &count)
 	if cTypes == nil {
 		return nil
 	}
@@ -598,13 +775,15 @@ func GetClipboardTypes() []string {
 	return types
 }
 
-// GetClipboardDataForType returns data for a specific type from clipboard
-func GetClipboardDataForType(typeStr string) ([]byte, bool) {
+// GetClipboardDataForType implements ClipboardManager using NSPasteboard
+func (s systemManager) GetClipboardDataForType(typeStr string) ([]byte, bool) {
+	cName := s.cName()
+	defer C.free(unsafe.Pointer(cName))
 	cType := C.CString(typeStr)
 	defer C.free(unsafe.Pointer(cType))
 
 	var length C.int
-	cData := C.getClipboardDataForType(cType, &length)
+	cData := C.getClipboardDataForType(cName, cType, &length)
 	if cData == nil {
 		return nil, false
 	}
@@ -615,16 +794,25 @@ func GetClipboardDataForType(typeStr string) ([]byte, bool) {
 	return data, true
 }
 
-// ContainsType checks if clipboard contains a specific type
-func ContainsType(typeStr string) bool {
+// ContainsType implements ClipboardManager using NSPasteboard
+func (s systemManager) ContainsType(typeStr string) bool {
+	cName := s.cName()
+	defer C.free(unsafe.Pointer(cName))
 	cType := C.CString(typeStr)
 	defer C.free(unsafe.Pointer(cType))
 
-	return C.clipboardContainsType(cType) == 1
+	return C.clipboardContainsType(cName, cType) == 1
+}
+
+// ChangeCount implements ClipboardManager using NSPasteboard
+func (s systemManager) ChangeCount() int {
+	cName := s.cName()
+	defer C.free(unsafe.Pointer(cName))
+	return int(C.getChangeCount(cName))
 }
 
-// UTIConformsTo checks if a UTI conforms to a parent type using macOS UTI system
-func UTIConformsTo(uti, parentType string) bool {
+// UTIConformsTo implements ClipboardManager using the macOS UTI system
+func (systemManager) UTIConformsTo(uti, parentType string) bool {
 	cUTI := C.CString(uti)
 	defer C.free(unsafe.Pointer(cUTI))
 
@@ -634,10 +822,9 @@ func UTIConformsTo(uti, parentType string) bool {
 	return C.utiConformsTo(cUTI, cParent) == 1
 }
 
-// GetPreferredExtensionForUTI returns the preferred file extension for a UTI
-// using macOS's canonical type database. Returns empty string if not found.
-// Example: "public.png" -> "png", "public.jpeg" -> "jpg"
-func GetPreferredExtensionForUTI(uti string) string {
+// GetPreferredExtensionForUTI implements ClipboardManager using macOS's canonical
+// type database (UTType, or UTTypeCopyPreferredTagWithClass before macOS 11)
+func (systemManager) GetPreferredExtensionForUTI(uti string) string {
 	cUTI := C.CString(uti)
 	defer C.free(unsafe.Pointer(cUTI))
 
@@ -671,131 +858,3 @@ func SaveRTFDToPath(data []byte, path string) error {
 
 	return nil
 }
-
-// ClipboardContent represents the content and type information from clipboard
-type ClipboardContent struct {
-	Type     string // UTI or MIME type
-	Data     []byte // Raw data
-	IsText   bool   // Whether this is text content
-	IsFile   bool   // Whether this is file reference
-	FilePath string // File path if IsFile is true
-}
-
-// GetClipboardContent returns clipboard content with smart type detection
-// Uses hybrid approach: UTI -> MIME -> mimetype fallback
-func GetClipboardContent() (*ClipboardContent, error) {
-	// Priority 1: Check for file URLs (highest reliability)
-	if files := GetFiles(); len(files) > 0 {
-		// For multiple files, just return info about the first one
-		filePath := files[0]
-		uti, _ := GetUTIForFile(filePath)
-		return &ClipboardContent{
-			Type:     uti,
-			IsFile:   true,
-			FilePath: filePath,
-		}, nil
-	}
-
-	// Priority 2: Check for rich UTI types on clipboard (images, etc.)
-	// This must come BEFORE text check because browsers put both image data
-	// and URL text on clipboard - we want the image data
-	types := GetClipboardTypes()
-	for _, typeStr := range types {
-		// Look for specific image types first
-		if isImageUTI(typeStr) {
-			if data, ok := GetClipboardDataForType(typeStr); ok {
-				return &ClipboardContent{
-					Type:   typeStr,
-					Data:   data,
-					IsText: false,
-				}, nil
-			}
-		}
-
-		// Look for other rich content types
-		if isRichContentUTI(typeStr) {
-			if data, ok := GetClipboardDataForType(typeStr); ok {
-				return &ClipboardContent{
-					Type:   typeStr,
-					Data:   data,
-					IsText: false,
-				}, nil
-			}
-		}
-	}
-
-	// Priority 3: Check for text content (fallback)
-	// This comes last so image data takes precedence over accompanying URLs
-	if text, ok := GetText(); ok {
-		return &ClipboardContent{
-			Type:   "public.utf8-plain-text",
-			Data:   []byte(text),
-			IsText: true,
-		}, nil
-	}
-
-	// Priority 4: Check for generic types like public.data
-	for _, typeStr := range types {
-		if typeStr == "public.data" || typeStr == "public.content" {
-			if data, ok := GetClipboardDataForType(typeStr); ok {
-				// Use mimetype detection as fallback
-				return &ClipboardContent{
-					Type:   typeStr,
-					Data:   data,
-					IsText: false,
-				}, nil
-			}
-		}
-	}
-
-	return nil, fmt.Errorf("no supported content found on clipboard")
-}
-
-// isImageUTI checks if a UTI represents an image type
-func isImageUTI(uti string) bool {
-	imageUTIs := []string{
-		"public.png",
-		"public.jpeg",
-		"public.tiff",
-		"public.gif",
-		"public.bmp",
-		"public.webp",
-		"public.heic",
-		"public.svg-image",
-	}
-
-	for _, imageUTI := range imageUTIs {
-		if uti == imageUTI {
-			return true
-		}
-	}
-
-	return false
-}
-
-// isRichContentUTI checks if a UTI represents rich content
-func isRichContentUTI(uti string) bool {
-	richUTIs := []string{
-		"com.apple.flat-rtfd",          // RTF with embedded images/attachments (priority)
-		"public.rtf",                   // Plain RTF formatting
-		"com.apple.rtfd",               // RTFD bundle
-		"public.pdf",
-		"public.html",
-		"public.xml",
-		"public.json",
-		"public.zip-archive",
-		"public.tar-archive",
-		"public.mp3",
-		"public.mp4",
-		"public.mpeg-4",
-		"public.quicktime-movie",
-	}
-
-	for _, richUTI := range richUTIs {
-		if uti == richUTI {
-			return true
-		}
-	}
-
-	return false
-}
@@ -0,0 +1,256 @@
+package clipboard
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"sync"
+
+	"github.com/neilberkman/clippy/internal/fsutil"
+)
+
+// MemoryFileEnvVar names a file the memory backend keeps its contents in, so
+// separate processes (clippy, then pasty) share one clipboard. Unset means the
+// contents live only in process memory.
+const MemoryFileEnvVar = "CLIPPY_MEMORY_FILE"
+
+// MemoryManager is a ClipboardManager that keeps clipboard content in process memory.
+// It is selected with CLIPPY_BACKEND=memory for headless or CI use where no window
+// server is available.
+//
+// Limitations: content is not shared with the OS clipboard. Without a backing
+// file (NewFileMemoryManager, or CLIPPY_MEMORY_FILE) it is also not shared with
+// other processes and is lost when the process exits, so a copy and paste must
+// happen in the same invocation (or the same long-running process, such as the
+// MCP server).
+type MemoryManager struct {
+	mu    sync.Mutex
+	path  string // Backing file; empty keeps the contents in memory only
+	files []string
+	types []string
+	data  map[string][]byte
+	count int // change count, increased each time the contents are replaced
+}
+
+// NewMemoryManager returns an empty in-memory clipboard
+func NewMemoryManager() *MemoryManager {
+	return &MemoryManager{data: map[string][]byte{}}
+}
+
+// NewFileMemoryManager returns a memory clipboard kept in the file at path. The
+// file is read before every operation and rewritten after every change, so
+// processes using the same path see each other's copies. A missing file is an
+// empty clipboard.
+func NewFileMemoryManager(path string) *MemoryManager {
+	return &MemoryManager{path: path, data: map[string][]byte{}}
+}
+
+// memoryState is the backing file format of a file-backed MemoryManager
+type memoryState struct {
+	Files []string          `json:"files,omitempty"`
+	Types []string          `json:"types,omitempty"`
+	Data  map[string][]byte `json:"data,omitempty"`
+	Count int               `json:"count"`
+}
+
+// lock takes the mutex and, for a file-backed clipboard, picks up changes
+// other processes made
+func (m *MemoryManager) lock() {
+	m.mu.Lock()
+	if m.path == "" {
+		return
+	}
+	raw, err := os.ReadFile(m.path)
+	if err != nil {
+		return // Missing or unreadable: keep what this process has
+	}
+	var state memoryState
+	if json.Unmarshal(raw, &state) != nil {
+		return
+	}
+	m.files, m.types, m.count = state.Files, state.Types, state.Count
+	m.data = state.Data
+	if m.data == nil {
+		m.data = map[string][]byte{}
+	}
+}
+
+// save writes the contents to the backing file, if there is one. The caller holds the mutex.
+func (m *MemoryManager) save() error {
+	if m.path == "" {
+		return nil
+	}
+	raw, err := json.Marshal(memoryState{Files: m.files, Types: m.types, Data: m.data, Count: m.count})
+	if err != nil {
+		return err
+	}
+	if err := fsutil.WriteFileAtomic(m.path, raw, 0600); err != nil {
+		return fmt.Errorf("%w: %v", ErrWriteFailed, err)
+	}
+	return nil
+}
+
+// SetData adds a representation for typeStr without clearing the others,
+// like writing an additional flavor to the pasteboard
+func (m *MemoryManager) SetData(typeStr string, data []byte) {
+	m.lock()
+	defer m.mu.Unlock()
+	m.setLocked(typeStr, data)
+	_ = m.save()
+}
+
+func (m *MemoryManager) setLocked(typeStr string, data []byte) {
+	if _, ok := m.data[typeStr]; !ok {
+		m.types = append(m.types, typeStr)
+	}
+	m.data[typeStr] = append([]byte(nil), data...)
+}
+
+func (m *MemoryManager) clearLocked() {
+	m.count++
+	m.files = nil
+	m.types = nil
+	m.data = map[string][]byte{}
+}
+
+// CopyFile implements ClipboardManager
+func (m *MemoryManager) CopyFile(path string) error {
+	return m.CopyFiles([]string{path})
+}
+
+// CopyFiles implements ClipboardManager
+func (m *MemoryManager) CopyFiles(paths []string) error {
+	m.lock()
+	defer m.mu.Unlock()
+	m.clearLocked()
+	m.files = append([]string{}, paths...)
+	if len(paths) > 0 {
+		m.setLocked("public.file-url", []byte("file://"+paths[0]))
+	}
+	return m.save()
+}
+
+// CopyText implements ClipboardManager
+func (m *MemoryManager) CopyText(text string) error {
+	return m.CopyTextWithType(text, PlainTextType)
+}
+
+// CopyTextWithType implements ClipboardManager. Like the system backend it also
+// stores a plain text representation for compatibility.
+func (m *MemoryManager) CopyTextWithType(text string, typeIdentifier string) error {
+	m.lock()
+	defer m.mu.Unlock()
+	m.clearLocked()
+	m.setLocked(typeIdentifier, []byte(text))
+	m.setLocked(PlainTextType, []byte(text))
+	return m.save()
+}
+
+// CopyFlavors implements ClipboardManager. A public.file-url flavor is read
+// back by GetFiles, as on the system pasteboard.
+func (m *MemoryManager) CopyFlavors(flavors []Flavor) error {
+	m.lock()
+	defer m.mu.Unlock()
+	m.clearLocked()
+	for _, flavor := range flavors {
+		m.setLocked(flavor.Type, flavor.Data)
+		if flavor.Type == "public.file-url" {
+			if u, err := url.Parse(string(flavor.Data)); err == nil && u.Scheme == "file" {
+				m.files = []string{u.Path}
+			}
+		}
+	}
+	return m.save()
+}
+
+// AddFiles implements ClipboardManager
+func (m *MemoryManager) AddFiles(paths []string) error {
+	m.lock()
+	defer m.mu.Unlock()
+	m.files = append(m.files, paths...)
+	if _, ok := m.data["public.file-url"]; !ok && len(paths) > 0 {
+		m.setLocked("public.file-url", []byte("file://"+paths[0]))
+	}
+	return m.save()
+}
+
+// AddTextWithType implements ClipboardManager
+func (m *MemoryManager) AddTextWithType(text string, typeIdentifier string) error {
+	m.lock()
+	defer m.mu.Unlock()
+	m.setLocked(typeIdentifier, []byte(text))
+	return m.save()
+}
+
+// Clear implements ClipboardManager
+func (m *MemoryManager) Clear() error {
+	m.lock()
+	defer m.mu.Unlock()
+	m.clearLocked()
+	return m.save()
+}
+
+// GetFiles implements ClipboardManager
+func (m *MemoryManager) GetFiles() []string {
+	m.lock()
+	defer m.mu.Unlock()
+	if len(m.files) == 0 {
+		return nil
+	}
+	return append([]string{}, m.files...)
+}
+
+// GetText implements ClipboardManager
+func (m *MemoryManager) GetText() (string, bool) {
+	m.lock()
+	defer m.mu.Unlock()
+	data, ok := m.data[PlainTextType]
+	return string(data), ok
+}
+
+// GetClipboardTypes implements ClipboardManager
+func (m *MemoryManager) GetClipboardTypes() []string {
+	m.lock()
+	defer m.mu.Unlock()
+	if len(m.types) == 0 {
+		return nil
+	}
+	return append([]string{}, m.types...)
+}
+
+// GetClipboardDataForType implements ClipboardManager
+func (m *MemoryManager) GetClipboardDataForType(typeStr string) ([]byte, bool) {
+	m.lock()
+	defer m.mu.Unlock()
+	data, ok := m.data[typeStr]
+	if !ok {
+		return nil, false
+	}
+	return append([]byte(nil), data...), true
+}
+
+// ContainsType implements ClipboardManager
+func (m *MemoryManager) ContainsType(typeStr string) bool {
+	m.lock()
+	defer m.mu.Unlock()
+	_, ok := m.data[typeStr]
+	return ok
+}
+
+// ChangeCount implements ClipboardManager
+func (m *MemoryManager) ChangeCount() int {
+	m.lock()
+	defer m.mu.Unlock()
+	return m.count
+}
+
+// UTIConformsTo implements ClipboardManager using a static table of common types
+func (m *MemoryManager) UTIConformsTo(uti, parentType string) bool {
+	return staticConformsTo(uti, parentType)
+}
+
+// GetPreferredExtensionForUTI implements ClipboardManager using a static table of common types
+func (m *MemoryManager) GetPreferredExtensionForUTI(uti string) string {
+	return staticPreferredExtension(uti)
+}
@@ -0,0 +1,109 @@
+//go:build !darwin || !cgo
+
+package clipboard
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// systemManager stands in for the macOS pasteboard in builds without it. Writes
+// return ErrUnsupported and reads find an empty clipboard, so CLIPPY_BACKEND=memory
+// (or SetManager) is needed for anything useful.
+type systemManager struct {
+	name string // Pasteboard name, kept so NewPasteboardManager behaves the same everywhere
+}
+
+// CopyFile returns ErrUnsupported
+func (systemManager) CopyFile(path string) error {
+	return ErrUnsupported
+}
+
+// CopyFiles returns ErrUnsupported
+func (systemManager) CopyFiles(paths []string) error {
+	return ErrUnsupported
+}
+
+// CopyText returns ErrUnsupported
+func (systemManager) CopyText(text string) error {
+	return ErrUnsupported
+}
+
+// CopyTextWithType returns ErrUnsupported
+func (systemManager) CopyTextWithType(text string, typeIdentifier string) error {
+	return ErrUnsupported
+}
+
+// CopyFlavors returns ErrUnsupported
+func (systemManager) CopyFlavors(flavors []Flavor) error {
+	return ErrUnsupported
+}
+
+// AddFiles returns ErrUnsupported
+func (systemManager) AddFiles(paths []string) error {
+	return ErrUnsupported
+}
+
+// AddTextWithType returns ErrUnsupported
+func (systemManager) AddTextWithType(text string, typeIdentifier string) error {
+	return ErrUnsupported
+}
+
+// Clear returns ErrUnsupported
+func (systemManager) Clear() error {
+	return ErrUnsupported
+}
+
+// GetFiles always finds no files
+func (systemManager) GetFiles() []string {
+	return nil
+}
+
+// GetText always finds no text
+func (systemManager) GetText() (string, bool) {
+	return "", false
+}
+
+// GetClipboardTypes always finds no types
+func (systemManager) GetClipboardTypes() []string {
+	return nil
+}
+
+// GetClipboardDataForType always finds no data
+func (systemManager) GetClipboardDataForType(typeStr string) ([]byte, bool) {
+	return nil, false
+}
+
+// ContainsType always reports false
+func (systemManager) ContainsType(typeStr string) bool {
+	return false
+}
+
+// ChangeCount is always 0 because the contents never change
+func (systemManager) ChangeCount() int {
+	return 0
+}
+
+// UTIConformsTo checks conformance against the static UTI table
+func (systemManager) UTIConformsTo(uti, parentType string) bool {
+	return staticConformsTo(uti, parentType)
+}
+
+// GetPreferredExtensionForUTI looks the extension up in the static UTI table
+func (systemManager) GetPreferredExtensionForUTI(uti string) string {
+	return staticPreferredExtension(uti)
+}
+
+// GetUTIForFile returns the UTI the static table has for the file's extension
+func GetUTIForFile(path string) (string, bool) {
+	ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	if ext == "" {
+		return "", false
+	}
+	return staticUTIForExtension(ext)
+}
+
+// SaveRTFDToPath returns ErrUnsupported; RTFD bundles need AppKit
+func SaveRTFDToPath(data []byte, path string) error {
+	return ErrUnsupported
+}
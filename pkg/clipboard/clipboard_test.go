@@ -1,147 +1,10 @@
 package clipboard
 
 import (
+	"path/filepath"
 	"testing"
 )
 
-func TestGetUTIForFile(t *testing.T) {
-	tests := []struct {
-		name        string
-		filePath    string
-		expectedUTI string
-		shouldExist bool
-	}{
-		{
-			name:        "PNG file",
-			filePath:    "../../test-files/minimal.png",
-			expectedUTI: "public.png",
-			shouldExist: true,
-		},
-		{
-			name:        "PDF file",
-			filePath:    "../../test-files/test.pdf",
-			expectedUTI: "com.adobe.pdf",
-			shouldExist: true,
-		},
-		{
-			name:        "Text file",
-			filePath:    "../../test-files/sample.txt",
-			expectedUTI: "public.plain-text",
-			shouldExist: true,
-		},
-		{
-			name:        "Elixir code file",
-			filePath:    "../../test-files/code.exs",
-			expectedUTI: "public.text", // Generic text type for unknown extensions
-			shouldExist: true,
-		},
-		{
-			name:        "Unknown extension file",
-			filePath:    "/some/path/file.xyz",
-			expectedUTI: "",
-			shouldExist: true, // UTI detection works on extension, not file existence
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			uti, exists := GetUTIForFile(tt.filePath)
-
-			if exists != tt.shouldExist {
-				t.Errorf("GetUTIForFile(%s) existence = %v, want %v", tt.filePath, exists, tt.shouldExist)
-			}
-
-			if tt.shouldExist && uti == "" {
-				t.Errorf("GetUTIForFile(%s) returned empty UTI but should exist", tt.filePath)
-			}
-
-			// Skip UTI comparison for system-dependent types (like .exs files)
-			// These may return dynamic UTIs on different systems
-		})
-	}
-}
-
-func TestClipboardTypes(t *testing.T) {
-	// Put some text on clipboard first
-	if err := CopyText("Test text for clipboard types"); err != nil {
-		t.Fatalf("Failed to copy text: %v", err)
-	}
-
-	types := GetClipboardTypes()
-	if len(types) == 0 {
-		t.Error("Expected clipboard to have types after copying text")
-	}
-
-	// Should contain text type
-	found := false
-	for _, typeStr := range types {
-		if typeStr == "public.utf8-plain-text" || typeStr == "NSStringPboardType" {
-			found = true
-			break
-		}
-	}
-
-	if !found {
-		t.Errorf("Expected clipboard to contain text type, got: %v", types)
-	}
-}
-
-func TestContainsType(t *testing.T) {
-	// Put text on clipboard
-	if err := CopyText("Test text for type checking"); err != nil {
-		t.Fatalf("Failed to copy text: %v", err)
-	}
-
-	// Should contain text type
-	if !ContainsType("public.utf8-plain-text") && !ContainsType("NSStringPboardType") {
-		t.Error("Expected clipboard to contain text type")
-	}
-
-	// Should not contain image type
-	if ContainsType("public.png") {
-		t.Error("Expected clipboard to not contain PNG type")
-	}
-}
-
-func TestGetClipboardContent(t *testing.T) {
-	// Test with text content
-	if err := CopyText("Test text content"); err != nil {
-		t.Fatalf("Failed to copy text: %v", err)
-	}
-
-	content, err := GetClipboardContent()
-	if err != nil {
-		t.Fatalf("GetClipboardContent() error = %v", err)
-	}
-
-	if !content.IsText {
-		t.Error("Expected content to be text")
-	}
-
-	if string(content.Data) != "Test text content" {
-		t.Errorf("Expected content data = 'Test text content', got '%s'", string(content.Data))
-	}
-
-	// Test with file reference - need absolute path
-	absPath := "/Users/neil/xuku/clippy/test-files/minimal.png"
-	if err := CopyFile(absPath); err != nil {
-		t.Fatalf("Failed to copy file: %v", err)
-	}
-
-	content, err = GetClipboardContent()
-	if err != nil {
-		t.Fatalf("GetClipboardContent() error = %v", err)
-	}
-
-	if !content.IsFile {
-		t.Error("Expected content to be file")
-	}
-
-	if content.FilePath == "" {
-		t.Error("Expected file path to be set")
-	}
-}
-
 func TestImageUTIDetection(t *testing.T) {
 	tests := []struct {
 		uti      string
@@ -191,45 +54,188 @@ func TestRichContentUTIDetection(t *testing.T) {
 	}
 }
 
-func TestUTIConformance(t *testing.T) {
-	tests := []struct {
-		name       string
+func TestMemoryManager(t *testing.T) {
+	mem := NewMemoryManager()
+	previous := SetManager(mem)
+	defer SetManager(previous)
+
+	if err := CopyTextWithType(`{"a": 1}`, "public.json"); err != nil {
+		t.Fatalf("CopyTextWithType returned error: %v", err)
+	}
+	if text, ok := GetText(); !ok || text != `{"a": 1}` {
+		t.Errorf("GetText() = %q, %v, want JSON text", text, ok)
+	}
+	if !ContainsType("public.json") {
+		t.Errorf("ContainsType(public.json) = false, want true")
+	}
+
+	if err := CopyFiles([]string{"/tmp/a.png", "/tmp/b.png"}); err != nil {
+		t.Fatalf("CopyFiles returned error: %v", err)
+	}
+	if files := GetFiles(); len(files) != 2 || files[0] != "/tmp/a.png" {
+		t.Errorf("GetFiles() = %v, want both files", files)
+	}
+	if _, ok := GetText(); ok {
+		t.Errorf("GetText() should be empty after copying files")
+	}
+
+	mem.SetData("public.png", []byte("png data"))
+	content, err := GetClipboardContent()
+	if err != nil {
+		t.Fatalf("GetClipboardContent returned error: %v", err)
+	}
+	if !content.IsFile || content.FilePath != "/tmp/a.png" {
+		t.Errorf("GetClipboardContent() = %+v, want file reference first", content)
+	}
+
+	if err := Clear(); err != nil {
+		t.Fatalf("Clear returned error: %v", err)
+	}
+	if types := GetClipboardTypes(); len(types) != 0 {
+		t.Errorf("GetClipboardTypes() after Clear = %v, want none", types)
+	}
+}
+
+func TestMemoryManagerUTIHelpers(t *testing.T) {
+	mem := NewMemoryManager()
+	previous := SetManager(mem)
+	defer SetManager(previous)
+
+	extTests := []struct {
+		uti  string
+		want string
+	}{
+		{"public.png", "png"},
+		{"public.jpeg", "jpeg"},
+		{"com.adobe.pdf", "pdf"},
+		{"public.utf8-plain-text", "txt"},
+		{"com.example.unknown", ""},
+	}
+	for _, tt := range extTests {
+		if got := GetPreferredExtensionForUTI(tt.uti); got != tt.want {
+			t.Errorf("GetPreferredExtensionForUTI(%q) = %q, want %q", tt.uti, got, tt.want)
+		}
+	}
+
+	conformsTests := []struct {
 		uti        string
 		parentType string
-		expected   bool
+		want       bool
+	}{
+		{"public.png", "public.image", true},
+		{"public.png", "public.data", true},
+		{"public.utf8-plain-text", "public.text", true},
+		{"public.html", "public.text", true},
+		{"public.png", "public.text", false},
+		{"com.example.unknown", "public.data", false},
+		{"com.example.unknown", "com.example.unknown", true},
+	}
+	for _, tt := range conformsTests {
+		if got := UTIConformsTo(tt.uti, tt.parentType); got != tt.want {
+			t.Errorf("UTIConformsTo(%q, %q) = %v, want %v", tt.uti, tt.parentType, got, tt.want)
+		}
+	}
+}
+
+func TestStaticUTIForExtension(t *testing.T) {
+	tests := []struct {
+		ext    string
+		want   string
+		wantOK bool
+	}{
+		{"png", "public.png", true},
+		{"jpg", "public.jpeg", true},
+		{"jpeg", "public.jpeg", true},
+		{"txt", "public.plain-text", true},
+		{"md", "net.daringfireball.markdown", true},
+		{"pdf", "com.adobe.pdf", true},
+		{"xyz", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := staticUTIForExtension(tt.ext)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("staticUTIForExtension(%q) = %q, %v, want %q, %v", tt.ext, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestBackendName(t *testing.T) {
+	previous := manager
+	defer SetManager(previous)
+
+	tests := []struct {
+		name    string
+		backend ClipboardManager
+		want    string
 	}{
-		{
-			name:       "Plain text conforms to text",
-			uti:        "public.plain-text",
-			parentType: "public.text",
-			expected:   true,
-		},
-		{
-			name:       "C source conforms to source code",
-			uti:        "public.c-source",
-			parentType: "public.source-code",
-			expected:   true,
-		},
-		{
-			name:       "PNG does not conform to text",
-			uti:        "public.png",
-			parentType: "public.text",
-			expected:   false,
-		},
-		{
-			name:       "JSON should conform to text",
-			uti:        "public.json",
-			parentType: "public.text",
-			expected:   true,
-		},
+		{"general pasteboard", nil, "system"},
+		{"named pasteboard", NewPasteboardManager("com.example.test"), "system"},
+		{"memory", NewMemoryManager(), "memory"},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := UTIConformsTo(tt.uti, tt.parentType)
-			if result != tt.expected {
-				t.Errorf("UTIConformsTo(%s, %s) = %v, want %v", tt.uti, tt.parentType, result, tt.expected)
+			SetManager(tt.backend)
+			if got := BackendName(); got != tt.want {
+				t.Errorf("BackendName() = %q, want %q", got, tt.want)
 			}
 		})
 	}
 }
+
+func TestMemoryManagerAdd(t *testing.T) {
+	m := NewMemoryManager()
+	if err := m.CopyTextWithType(`{"a":1}`, "public.json"); err != nil {
+		t.Fatalf("CopyTextWithType returned error: %v", err)
+	}
+	count := m.ChangeCount()
+	if count != 1 {
+		t.Errorf("ChangeCount() after one copy = %d, want 1", count)
+	}
+	if err := m.AddFiles([]string{"/tmp/a.txt"}); err != nil {
+		t.Fatalf("AddFiles returned error: %v", err)
+	}
+	if err := m.AddTextWithType("<b>a</b>", "public.html"); err != nil {
+		t.Fatalf("AddTextWithType returned error: %v", err)
+	}
+
+	if files := m.GetFiles(); len(files) != 1 || files[0] != "/tmp/a.txt" {
+		t.Errorf("GetFiles() = %v, want [/tmp/a.txt]", files)
+	}
+	for _, typ := range []string{"public.json", PlainTextType, "public.file-url", "public.html"} {
+		if !m.ContainsType(typ) {
+			t.Errorf("clipboard lost %s after adding", typ)
+		}
+	}
+	if text, _ := m.GetText(); text != `{"a":1}` {
+		t.Errorf("GetText() = %q, adding HTML should not replace plain text", text)
+	}
+	if got := m.ChangeCount(); got != count {
+		t.Errorf("ChangeCount() after adding = %d, want %d (adding doesn't replace the contents)", got, count)
+	}
+}
+
+func TestFileMemoryManagerSharesContents(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "clipboard.json")
+	writer := NewFileMemoryManager(path)
+	reader := NewFileMemoryManager(path)
+
+	if got := reader.GetFiles(); got != nil {
+		t.Errorf("GetFiles() on a missing file = %v, want nil", got)
+	}
+	if err := writer.CopyFiles([]string{"/tmp/a.pdf"}); err != nil {
+		t.Fatalf("CopyFiles returned error: %v", err)
+	}
+	if got := reader.GetFiles(); len(got) != 1 || got[0] != "/tmp/a.pdf" {
+		t.Errorf("GetFiles() from another manager = %v, want [/tmp/a.pdf]", got)
+	}
+	if err := reader.CopyText("hello"); err != nil {
+		t.Fatalf("CopyText returned error: %v", err)
+	}
+	if text, ok := writer.GetText(); !ok || text != "hello" {
+		t.Errorf("GetText() from another manager = %q, %v, want hello", text, ok)
+	}
+	if writer.ChangeCount() != 2 {
+		t.Errorf("ChangeCount() = %d, want 2 after two copies", writer.ChangeCount())
+	}
+}
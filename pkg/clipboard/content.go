@@ -0,0 +1,131 @@
+package clipboard
+
+import "fmt"
+
+// ClipboardContent represents the content and type information from clipboard
+type ClipboardContent struct {
+	Type     string // UTI or MIME type
+	Data     []byte // Raw data
+	IsText   bool   // Whether this is text content
+	IsFile   bool   // Whether this is file reference
+	FilePath string // File path if IsFile is true
+}
+
+// GetClipboardContent returns clipboard content with smart type detection
+// Uses hybrid approach: UTI -> MIME -> mimetype fallback
+func GetClipboardContent() (*ClipboardContent, error) {
+	// Priority 1: Check for file URLs (highest reliability)
+	if files := GetFiles(); len(files) > 0 {
+		// For multiple files, just return info about the first one
+		filePath := files[0]
+		uti, _ := GetUTIForFile(filePath)
+		return &ClipboardContent{
+			Type:     uti,
+			IsFile:   true,
+			FilePath: filePath,
+		}, nil
+	}
+
+	// Priority 2: Check for rich UTI types on clipboard (images, etc.)
+	// This must come BEFORE text check because browsers put both image data
+	// and URL text on clipboard - we want the image data
+	types := GetClipboardTypes()
+	for _, typeStr := range types {
+		// Look for specific image types first
+		if isImageUTI(typeStr) {
+			if data, ok := GetClipboardDataForType(typeStr); ok {
+				return &ClipboardContent{
+					Type:   typeStr,
+					Data:   data,
+					IsText: false,
+				}, nil
+			}
+		}
+
+		// Look for other rich content types
+		if isRichContentUTI(typeStr) {
+			if data, ok := GetClipboardDataForType(typeStr); ok {
+				return &ClipboardContent{
+					Type:   typeStr,
+					Data:   data,
+					IsText: false,
+				}, nil
+			}
+		}
+	}
+
+	// Priority 3: Check for text content (fallback)
+	// This comes last so image data takes precedence over accompanying URLs
+	if text, ok := GetText(); ok {
+		return &ClipboardContent{
+			Type:   PlainTextType,
+			Data:   []byte(text),
+			IsText: true,
+		}, nil
+	}
+
+	// Priority 4: Check for generic types like public.data
+	for _, typeStr := range types {
+		if typeStr == "public.data" || typeStr == "public.content" {
+			if data, ok := GetClipboardDataForType(typeStr); ok {
+				// Use mimetype detection as fallback
+				return &ClipboardContent{
+					Type:   typeStr,
+					Data:   data,
+					IsText: false,
+				}, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("no supported content found on clipboard")
+}
+
+// isImageUTI checks if a UTI represents an image type
+func isImageUTI(uti string) bool {
+	imageUTIs := []string{
+		"public.png",
+		"public.jpeg",
+		"public.tiff",
+		"public.gif",
+		"public.bmp",
+		"public.webp",
+		"public.heic",
+		"public.svg-image",
+	}
+
+	for _, imageUTI := range imageUTIs {
+		if uti == imageUTI {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isRichContentUTI checks if a UTI represents rich content
+func isRichContentUTI(uti string) bool {
+	richUTIs := []string{
+		"com.apple.flat-rtfd", // RTF with embedded images/attachments (priority)
+		"public.rtf",          // Plain RTF formatting
+		"com.apple.rtfd",      // RTFD bundle
+		"public.pdf",
+		"public.html",
+		"public.xml",
+		"public.json",
+		"public.zip-archive",
+		"public.tar-archive",
+		"public.mp3",
+		"public.mp4",
+		"public.mpeg-4",
+		"public.quicktime-movie",
+	}
+
+	for _, richUTI := range richUTIs {
+		if uti == richUTI {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,193 @@
+package clipboard
+
+import (
+	"errors"
+	"os"
+	"strings"
+)
+
+// PlainTextType is the plain text representation GetText reads, matching
+// NSPasteboardTypeString
+const PlainTextType = "public.utf8-plain-text"
+
+// Errors returned by the system backend when the pasteboard can't be written,
+// usually because another app is holding it
+var (
+	ErrWriteFailed = errors.New("failed to write to clipboard")
+	ErrTimeout     = errors.New("clipboard operation timed out")
+)
+
+// ErrUnsupported is returned by the system backend in builds without the macOS
+// pasteboard (other platforms, or cgo disabled). CLIPPY_BACKEND=memory works there.
+var ErrUnsupported = errors.New("the system clipboard is only available on macOS; set " + BackendEnvVar + "=memory for an in-process clipboard")
+
+// Flavor is one representation of the clipboard content, such as PNG data or
+// the HTML of a rich text selection
+type Flavor struct {
+	Type string // UTI, e.g. "public.png" or "public.html"
+	Data []byte
+}
+
+// BackendEnvVar selects the clipboard backend: "system" (default) or "memory"
+const BackendEnvVar = "CLIPPY_BACKEND"
+
+// ClipboardManager is the set of pasteboard operations clippy is built on.
+// The default implementation talks to the macOS general pasteboard (see
+// NewPasteboardManager for named ones); tests and headless environments can swap
+// in another implementation with SetManager.
+type ClipboardManager interface {
+	CopyFile(path string) error
+	CopyFiles(paths []string) error
+	CopyText(text string) error
+	CopyTextWithType(text string, typeIdentifier string) error
+	AddFiles(paths []string) error
+	AddTextWithType(text string, typeIdentifier string) error
+	CopyFlavors(flavors []Flavor) error
+	Clear() error
+	GetFiles() []string
+	GetText() (string, bool)
+	GetClipboardTypes() []string
+	GetClipboardDataForType(typeStr string) ([]byte, bool)
+	ContainsType(typeStr string) bool
+	ChangeCount() int
+	UTIConformsTo(uti, parentType string) bool
+	GetPreferredExtensionForUTI(uti string) string
+}
+
+// manager is the backend used by all package-level clipboard functions
+var manager = backendFromEnv()
+
+// backendFromEnv returns the backend named by CLIPPY_BACKEND, defaulting to the system pasteboard
+func backendFromEnv() ClipboardManager {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv(BackendEnvVar))) {
+	case "memory":
+		if path := os.Getenv(MemoryFileEnvVar); path != "" {
+			return NewFileMemoryManager(path)
+		}
+		return NewMemoryManager()
+	default:
+		return systemManager{}
+	}
+}
+
+// NewPasteboardManager returns a backend for the macOS pasteboard with the given
+// name, like NSPasteboard(name:). Use it for the find pasteboard or an app-private
+// name to keep automation off the user's general clipboard. An empty name means
+// the general pasteboard.
+func NewPasteboardManager(name string) ClipboardManager {
+	return systemManager{name: name}
+}
+
+// SetManager replaces the clipboard backend and returns the previous one.
+// Passing nil restores the system pasteboard backend.
+func SetManager(m ClipboardManager) ClipboardManager {
+	previous := manager
+	if m == nil {
+		m = systemManager{}
+	}
+	manager = m
+	return previous
+}
+
+// BackendName describes the active backend: "system", "memory", or "custom"
+// for an implementation installed with SetManager
+func BackendName() string {
+	switch manager.(type) {
+	case systemManager:
+		return "system"
+	case *MemoryManager:
+		return "memory"
+	default:
+		return "custom"
+	}
+}
+
+// CopyFile copies a single file reference to clipboard
+func CopyFile(path string) error {
+	return manager.CopyFile(path)
+}
+
+// CopyFiles copies multiple file references to clipboard
+func CopyFiles(paths []string) error {
+	return manager.CopyFiles(paths)
+}
+
+// CopyText copies text content to clipboard
+func CopyText(text string) error {
+	return manager.CopyText(text)
+}
+
+// CopyTextWithType copies text with a specific UTI type to clipboard
+// Common types: "public.html", "public.json", "public.xml", "public.plain-text"
+func CopyTextWithType(text string, typeIdentifier string) error {
+	return manager.CopyTextWithType(text, typeIdentifier)
+}
+
+// AddFiles adds file references to the clipboard without clearing what's
+// already there
+func AddFiles(paths []string) error {
+	return manager.AddFiles(paths)
+}
+
+// AddTextWithType adds a text representation of the given type to the clipboard
+// without clearing the other types already there. Unlike CopyTextWithType it
+// doesn't also write plain text.
+func AddTextWithType(text string, typeIdentifier string) error {
+	return manager.AddTextWithType(text, typeIdentifier)
+}
+
+// CopyFlavors replaces the clipboard contents with the given representations in
+// one pasteboard transaction, the way apps offer rich and plain versions of the
+// same content. Each receiving app picks the flavor it understands best.
+func CopyFlavors(flavors []Flavor) error {
+	return manager.CopyFlavors(flavors)
+}
+
+// Clear clears the clipboard
+func Clear() error {
+	return manager.Clear()
+}
+
+// GetFiles returns file paths currently on clipboard
+func GetFiles() []string {
+	return manager.GetFiles()
+}
+
+// GetText returns text content from clipboard
+func GetText() (string, bool) {
+	return manager.GetText()
+}
+
+// GetClipboardTypes returns all available types on clipboard
+func GetClipboardTypes() []string {
+	return manager.GetClipboardTypes()
+}
+
+// GetClipboardDataForType returns data for a specific type from clipboard
+func GetClipboardDataForType(typeStr string) ([]byte, bool) {
+	return manager.GetClipboardDataForType(typeStr)
+}
+
+// ContainsType checks if clipboard contains a specific type
+func ContainsType(typeStr string) bool {
+	return manager.ContainsType(typeStr)
+}
+
+// ChangeCount returns the pasteboard's change count. It increases each time the
+// clipboard contents are replaced (by any app), so an unchanged count means the
+// content is the same as when it was last read.
+func ChangeCount() int {
+	return manager.ChangeCount()
+}
+
+// UTIConformsTo checks if a UTI conforms to a parent type
+func UTIConformsTo(uti, parentType string) bool {
+	return manager.UTIConformsTo(uti, parentType)
+}
+
+// GetPreferredExtensionForUTI returns the preferred file extension for a UTI,
+// without the leading dot. Returns empty string if not found.
+// Example: "public.png" -> "png", "public.jpeg" -> "jpeg"
+func GetPreferredExtensionForUTI(uti string) string {
+	return manager.GetPreferredExtensionForUTI(uti)
+}
@@ -0,0 +1,86 @@
+package clipboard
+
+// utiInfo describes a type in the static UTI table used by backends without
+// access to the macOS type database
+type utiInfo struct {
+	ext     string   // Preferred filename extension, without the dot
+	parents []string // Types this one directly conforms to
+}
+
+// staticUTIs covers the types clippy reads and writes. It mirrors the macOS
+// type database for these entries closely enough for extension lookup and
+// image/text classification.
+var staticUTIs = map[string]utiInfo{
+	"public.data":                 {},
+	"public.content":              {},
+	"public.text":                 {parents: []string{"public.data", "public.content"}},
+	"public.plain-text":           {ext: "txt", parents: []string{"public.text"}},
+	"public.utf8-plain-text":      {ext: "txt", parents: []string{"public.plain-text"}},
+	"public.source-code":          {parents: []string{"public.plain-text"}},
+	"public.html":                 {ext: "html", parents: []string{"public.text"}},
+	"public.xml":                  {ext: "xml", parents: []string{"public.text"}},
+	"public.json":                 {ext: "json", parents: []string{"public.text"}},
+	"public.rtf":                  {ext: "rtf", parents: []string{"public.text"}},
+	"net.daringfireball.markdown": {ext: "md", parents: []string{"public.plain-text"}},
+	"com.apple.flat-rtfd":         {ext: "rtfd", parents: []string{"public.data", "public.content"}},
+	"public.image":                {parents: []string{"public.data", "public.content"}},
+	"public.png":                  {ext: "png", parents: []string{"public.image"}},
+	"public.jpeg":                 {ext: "jpeg", parents: []string{"public.image"}},
+	"public.tiff":                 {ext: "tiff", parents: []string{"public.image"}},
+	"public.heic":                 {ext: "heic", parents: []string{"public.image"}},
+	"com.compuserve.gif":          {ext: "gif", parents: []string{"public.image"}},
+	"com.microsoft.bmp":           {ext: "bmp", parents: []string{"public.image"}},
+	"org.webmproject.webp":        {ext: "webp", parents: []string{"public.image"}},
+	"public.svg-image":            {ext: "svg", parents: []string{"public.image"}},
+	"com.adobe.pdf":               {ext: "pdf", parents: []string{"public.data", "public.content"}},
+	"public.zip-archive":          {ext: "zip", parents: []string{"public.data"}},
+}
+
+// staticPreferredExtension looks up a UTI's extension in the static table
+func staticPreferredExtension(uti string) string {
+	return staticUTIs[uti].ext
+}
+
+// staticExtensionAliases maps extensions to their table entry when the table's
+// preferred extension is a different spelling
+var staticExtensionAliases = map[string]string{
+	"jpg":      "public.jpeg",
+	"tif":      "public.tiff",
+	"htm":      "public.html",
+	"markdown": "net.daringfireball.markdown",
+	"text":     "public.plain-text",
+}
+
+// staticUTIForExtension finds the UTI for a filename extension (without the dot)
+// in the static table. The most specific type wins, e.g. "txt" is public.plain-text
+// rather than public.utf8-plain-text, matching what macOS reports for files.
+func staticUTIForExtension(ext string) (string, bool) {
+	if uti, ok := staticExtensionAliases[ext]; ok {
+		return uti, true
+	}
+	found := ""
+	for uti, info := range staticUTIs {
+		if info.ext != ext {
+			continue
+		}
+		// Prefer the parent when a subtype shares the extension, and break
+		// remaining ties by name so map order doesn't matter
+		if found == "" || staticConformsTo(found, uti) || (!staticConformsTo(uti, found) && uti < found) {
+			found = uti
+		}
+	}
+	return found, found != ""
+}
+
+// staticConformsTo reports whether uti is parentType or inherits from it in the static table
+func staticConformsTo(uti, parentType string) bool {
+	if uti == parentType {
+		return true
+	}
+	for _, parent := range staticUTIs[uti].parents {
+		if staticConformsTo(parent, parentType) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+//go:build darwin
+
+// Package notify posts desktop notifications for completed clipboard operations.
+package notify
+
+/*
+#cgo CFLAGS: -x objective-c -Wno-deprecated-declarations
+#cgo LDFLAGS: -framework Foundation -framework AppKit
+#import <Foundation/Foundation.h>
+#import <AppKit/NSSound.h>
+
+// postNotification delivers a banner through NSUserNotificationCenter.
+// Returns 0 when there's no notification center, which is the case for
+// command-line tools that aren't inside an app bundle.
+int postNotification(const char *title, const char *message) {
+	@autoreleasepool {
+		NSUserNotificationCenter *center = [NSUserNotificationCenter defaultUserNotificationCenter];
+		if (center == nil) {
+			return 0;
+		}
+		NSUserNotification *notification = [[NSUserNotification alloc] init];
+		notification.title = [NSString stringWithUTF8String:title];
+		notification.informativeText = [NSString stringWithUTF8String:message];
+		[center deliverNotification:notification];
+		return 1;
+	}
+}
+
+// playSound plays a named system sound (from /System/Library/Sounds) and waits
+// up to two seconds for it to finish, so the process can exit right afterwards.
+// Returns 0 if the sound doesn't exist or couldn't be played.
+int playSound(const char *name) {
+	@autoreleasepool {
+		NSSound *sound = [NSSound soundNamed:[NSString stringWithUTF8String:name]];
+		if (sound == nil || ![sound play]) {
+			return 0;
+		}
+		NSDate *timeoutDate = [NSDate dateWithTimeIntervalSinceNow:2.0];
+		while ([sound isPlaying] && [timeoutDate timeIntervalSinceNow] > 0) {
+			[[NSRunLoop currentRunLoop] runUntilDate:[NSDate dateWithTimeIntervalSinceNow:0.01]];
+		}
+		return 1;
+	}
+}
+*/
+import "C"
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"unsafe"
+)
+
+// Post shows a notification banner with the given title and message. Unbundled
+// binaries have no notification center of their own, so in that case the banner
+// is posted through osascript instead.
+func Post(title, message string) error {
+	cTitle := C.CString(title)
+	defer C.free(unsafe.Pointer(cTitle))
+	cMessage := C.CString(message)
+	defer C.free(unsafe.Pointer(cMessage))
+
+	if C.postNotification(cTitle, cMessage) == 1 {
+		return nil
+	}
+
+	script := fmt.Sprintf("display notification %s with title %s", appleScriptString(message), appleScriptString(title))
+	if out, err := exec.Command("osascript", "-e", script).CombinedOutput(); err != nil {
+		return fmt.Errorf("could not post notification: %v: %s", err, out)
+	}
+	return nil
+}
+
+// System sounds used by Beep
+const (
+	successSound = "Glass"
+	errorSound   = "Basso"
+)
+
+// Beep plays a short system sound: one for success and a distinct one for
+// failure. It falls back to the terminal bell if the sound can't be played.
+func Beep(success bool) {
+	name := successSound
+	if !success {
+		name = errorSound
+	}
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	if C.playSound(cName) == 0 {
+		fmt.Fprint(os.Stderr, "\a")
+	}
+}
+
+// appleScriptString quotes s as an AppleScript string literal
+func appleScriptString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
@@ -0,0 +1,20 @@
+//go:build !darwin
+
+// Package notify posts desktop notifications for completed clipboard operations.
+package notify
+
+import (
+	"fmt"
+	"os"
+)
+
+// Post does nothing outside macOS
+func Post(title, message string) error {
+	return nil
+}
+
+// Beep rings the terminal bell; there are no distinct success and error sounds
+// outside macOS
+func Beep(success bool) {
+	fmt.Fprint(os.Stderr, "\a")
+}
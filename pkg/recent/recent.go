@@ -1,7 +1,9 @@
 package recent
 
 import (
+	"errors"
 	"fmt"
+	"hash"
 	"io"
 	"os"
 	"path/filepath"
@@ -11,6 +13,7 @@ import (
 	"time"
 
 	"github.com/gabriel-vasile/mimetype"
+	"github.com/neilberkman/clippy/internal/fsutil"
 	"github.com/olebedev/when"
 	"github.com/olebedev/when/rules/common"
 	"github.com/olebedev/when/rules/en"
@@ -33,12 +36,39 @@ func (f *FileInfo) Age() time.Duration {
 
 // FindOptions controls how recent files are discovered
 type FindOptions struct {
-	MaxAge         time.Duration
+	MaxAge         time.Duration // 0 = no age limit
 	MaxCount       int
 	Directories    []string
 	Extensions     []string
 	ExcludeTemp    bool
-	SmartUnarchive bool // Look inside auto-unarchived folders
+	SmartUnarchive bool   // Look inside auto-unarchived folders
+	IncludeHidden  bool   // Don't skip dotfiles and hidden directories
+	IncludeTemp    bool   // Don't skip partial downloads and temp files (overrides ExcludeTemp)
+	NameQuery      string // Only include files whose name matches (see MatchesName)
+	CaseSensitive  bool   // Match NameQuery with exact case
+
+	// MimeTypes only includes files of these types (see MatchesMimeType). With
+	// Extensions as well, a file may match either.
+	MimeTypes []string
+
+	// NoRecurse only looks at files directly in Directories, not in subfolders
+	NoRecurse bool
+
+	// Timings, if set, receives how long each phase of FindRecentFiles took.
+	// Leave nil to skip the bookkeeping.
+	Timings *FindTimings
+
+	// Found, if set, receives how many files matched before MaxCount was
+	// applied, so callers can tell whether the results were cut off.
+	Found *int
+}
+
+// FindTimings breaks down where FindRecentFiles spent its time
+type FindTimings struct {
+	Walk       time.Duration // Walking directories, including MIME detection
+	MimeDetect time.Duration // MIME detection alone
+	Sort       time.Duration // Sorting results by modification time
+	FilesSeen  int           // Files whose MIME type was detected
 }
 
 // ArchiveInfo represents information about an auto-unarchived download
@@ -96,8 +126,12 @@ func GetBrowserDownloadDir() string {
 func FindRecentFiles(opts FindOptions) ([]FileInfo, error) {
 	var allFiles []FileInfo
 
-	cutoff := time.Now().Add(-opts.MaxAge)
+	var cutoff time.Time
+	if opts.MaxAge > 0 {
+		cutoff = time.Now().Add(-opts.MaxAge)
+	}
 
+	walkStart := time.Now()
 	for _, dir := range opts.Directories {
 		if !dirExists(dir) {
 			continue
@@ -113,10 +147,20 @@ func FindRecentFiles(opts FindOptions) ([]FileInfo, error) {
 	}
 
 	// Sort by modification time, newest first
+	sortStart := time.Now()
 	sort.Slice(allFiles, func(i, j int) bool {
 		return allFiles[i].Modified.After(allFiles[j].Modified)
 	})
 
+	if opts.Timings != nil {
+		opts.Timings.Walk = sortStart.Sub(walkStart)
+		opts.Timings.Sort = time.Since(sortStart)
+	}
+
+	if opts.Found != nil {
+		*opts.Found = len(allFiles)
+	}
+
 	// Limit results
 	if opts.MaxCount > 0 && len(allFiles) > opts.MaxCount {
 		allFiles = allFiles[:opts.MaxCount]
@@ -236,7 +280,7 @@ func findFilesInDir(dir string, cutoff time.Time, opts FindOptions) ([]FileInfo,
 		}
 
 		// Skip hidden files and directories
-		if strings.HasPrefix(info.Name(), ".") {
+		if !opts.IncludeHidden && strings.HasPrefix(info.Name(), ".") {
 			if info.IsDir() {
 				return filepath.SkipDir
 			}
@@ -244,7 +288,7 @@ func findFilesInDir(dir string, cutoff time.Time, opts FindOptions) ([]FileInfo,
 		}
 
 		// Skip temporary files
-		if opts.ExcludeTemp && isTemporaryFile(info.Name()) {
+		if opts.ExcludeTemp && !opts.IncludeTemp && IsTemporaryFile(info.Name()) {
 			return nil
 		}
 
@@ -255,23 +299,41 @@ func findFilesInDir(dir string, cutoff time.Time, opts FindOptions) ([]FileInfo,
 
 		// Skip directories - we only want files
 		if info.IsDir() {
+			if opts.NoRecurse {
+				return filepath.SkipDir
+			}
 			return nil
 		}
 
-		// Check extensions if specified
-		if len(opts.Extensions) > 0 {
-			ext := strings.ToLower(filepath.Ext(path))
-			if !contains(opts.Extensions, ext) {
-				return nil
-			}
+		// Check the name query if specified
+		if opts.NameQuery != "" && !MatchesName(info.Name(), opts.NameQuery, opts.CaseSensitive) {
+			return nil
+		}
+
+		// Check extensions if specified. With MimeTypes as well, a file that
+		// doesn't match an extension can still match by type below.
+		extMatch := len(opts.Extensions) > 0 && contains(opts.Extensions, strings.ToLower(filepath.Ext(path)))
+		if len(opts.Extensions) > 0 && !extMatch && len(opts.MimeTypes) == 0 {
+			return nil
 		}
 
 		// Detect MIME type
+		var detectStart time.Time
+		if opts.Timings != nil {
+			detectStart = time.Now()
+		}
 		mtype, _ := mimetype.DetectFile(path)
+		if opts.Timings != nil {
+			opts.Timings.MimeDetect += time.Since(detectStart)
+			opts.Timings.FilesSeen++
+		}
 		mimeType := ""
 		if mtype != nil {
 			mimeType = mtype.String()
 		}
+		if len(opts.MimeTypes) > 0 && !extMatch && !MatchesMimeType(mimeType, opts.MimeTypes) {
+			return nil
+		}
 
 		files = append(files, FileInfo{
 			Path:     path,
@@ -288,8 +350,38 @@ func findFilesInDir(dir string, cutoff time.Time, opts FindOptions) ([]FileInfo,
 	return files, err
 }
 
-// isTemporaryFile checks if a file appears to be temporary
-func isTemporaryFile(name string) bool {
+// MatchesName reports whether a filename matches a find query, following the
+// same rules as the Spotlight search: ".pdf" matches names ending with .pdf,
+// anything else matches names containing the query. Matching is case-insensitive
+// unless caseSensitive is set. Unlike Spotlight it does not ignore diacritics.
+func MatchesName(name, query string, caseSensitive bool) bool {
+	if !caseSensitive {
+		name = strings.ToLower(name)
+		query = strings.ToLower(query)
+	}
+	if strings.HasPrefix(query, ".") {
+		return strings.HasSuffix(name, query)
+	}
+	return strings.Contains(name, query)
+}
+
+// MatchesMimeType reports whether a detected MIME type is one of types. A type
+// ending in "/" matches the whole family ("image/" matches image/png); anything
+// else must match exactly, ignoring parameters such as "; charset=utf-8".
+func MatchesMimeType(mimeType string, types []string) bool {
+	base, _, _ := strings.Cut(mimeType, ";")
+	base = strings.ToLower(strings.TrimSpace(base))
+	for _, t := range types {
+		t = strings.ToLower(t)
+		if strings.HasSuffix(t, "/") && strings.HasPrefix(base, t) || base == t {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTemporaryFile checks if a file appears to be temporary or a partial download
+func IsTemporaryFile(name string) bool {
 	tempSuffixes := []string{
 		".tmp", ".temp", ".download", ".partial", ".crdownload",
 		".part", ".filepart", ".opdownload",
@@ -305,6 +397,184 @@ func isTemporaryFile(name string) bool {
 	return false
 }
 
+// SortKey is the file attribute a SortOrder compares
+type SortKey string
+
+// Sort keys for SortOrder
+const (
+	SortByAge  SortKey = "age"
+	SortByName SortKey = "name"
+	SortBySize SortKey = "size"
+)
+
+// SortOrder orders a file list. Ascending age is newest first.
+type SortOrder struct {
+	By         SortKey
+	Descending bool
+}
+
+// DefaultSortOrder is newest first, the order FindRecentFiles returns
+var DefaultSortOrder = SortOrder{By: SortByAge}
+
+// sortCycle is the order the picker's sort key steps through
+var sortCycle = []SortOrder{
+	{By: SortByAge},
+	{By: SortByAge, Descending: true},
+	{By: SortByName},
+	{By: SortByName, Descending: true},
+	{By: SortBySize, Descending: true},
+	{By: SortBySize},
+}
+
+// ParseSortOrder parses "age", "name" or "size", optionally followed by ":asc"
+// or ":desc". Without a direction, age is newest first, name is A to Z and size
+// is largest first.
+func ParseSortOrder(s string) (SortOrder, error) {
+	key, direction, hasDirection := strings.Cut(strings.ToLower(strings.TrimSpace(s)), ":")
+	order := SortOrder{By: SortKey(key)}
+	switch order.By {
+	case SortByAge, SortByName:
+	case SortBySize:
+		order.Descending = true
+	default:
+		return SortOrder{}, fmt.Errorf("invalid sort order %q (use age, name or size, optionally with :asc or :desc)", s)
+	}
+	if hasDirection {
+		switch direction {
+		case "asc":
+			order.Descending = false
+		case "desc":
+			order.Descending = true
+		default:
+			return SortOrder{}, fmt.Errorf("invalid sort direction %q (use asc or desc)", direction)
+		}
+	}
+	return order, nil
+}
+
+// String returns the order in the form ParseSortOrder accepts
+func (o SortOrder) String() string {
+	if o.Descending {
+		return string(o.By) + ":desc"
+	}
+	return string(o.By) + ":asc"
+}
+
+// Description describes the order for display, e.g. "largest first"
+func (o SortOrder) Description() string {
+	switch {
+	case o.By == SortByName && !o.Descending:
+		return "A to Z"
+	case o.By == SortByName:
+		return "Z to A"
+	case o.By == SortBySize && o.Descending:
+		return "largest first"
+	case o.By == SortBySize:
+		return "smallest first"
+	case o.Descending:
+		return "oldest first"
+	default:
+		return "newest first"
+	}
+}
+
+// Next returns the order after o in the picker's cycle
+func (o SortOrder) Next() SortOrder {
+	for i, order := range sortCycle {
+		if order == o {
+			return sortCycle[(i+1)%len(sortCycle)]
+		}
+	}
+	return sortCycle[0]
+}
+
+// SortFiles sorts files in place. The sort is stable, so files that compare
+// equal keep their newest-first order.
+func SortFiles(files []FileInfo, order SortOrder) {
+	less := func(a, b FileInfo) bool {
+		switch order.By {
+		case SortByName:
+			return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+		case SortBySize:
+			return a.Size < b.Size
+		default:
+			// Ascending age means newer first
+			return a.Modified.After(b.Modified)
+		}
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		if order.Descending {
+			return less(files[j], files[i])
+		}
+		return less(files[i], files[j])
+	})
+}
+
+// ErrNotStable is returned by WaitForStable when a file is still being written at the timeout
+var ErrNotStable = errors.New("file is still being written")
+
+// WaitForStable waits until path looks finished: no partial download of it
+// (e.g. report.pdf.part) sits next to it, and its size and modification time
+// stay the same for interval. For a folder the total size and newest
+// modification time of its contents are compared. Returns ErrNotStable if the
+// file is still changing after timeout.
+func WaitForStable(path string, interval, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	size, modified, err := fileSignature(path)
+	if err != nil {
+		return err
+	}
+	for {
+		time.Sleep(interval)
+		newSize, newModified, err := fileSignature(path)
+		if err != nil {
+			return err
+		}
+		if newSize == size && newModified.Equal(modified) && !hasPartialDownload(path) {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%s: %w", filepath.Base(path), ErrNotStable)
+		}
+		size, modified = newSize, newModified
+	}
+}
+
+// fileSignature returns the size and modification time of a file, or the total
+// size and newest modification time of a folder's contents
+func fileSignature(path string) (int64, time.Time, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	if !info.IsDir() {
+		return info.Size(), info.ModTime(), nil
+	}
+	size, modified := int64(0), info.ModTime()
+	err = filepath.Walk(path, func(_ string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		size += fi.Size()
+		if fi.ModTime().After(modified) {
+			modified = fi.ModTime()
+		}
+		return nil
+	})
+	return size, modified, err
+}
+
+// hasPartialDownload reports whether a browser is still downloading into path,
+// which some (like Firefox) create empty next to a name.part file
+func hasPartialDownload(path string) bool {
+	for _, suffix := range []string{".part", ".crdownload", ".download", ".partial", ".opdownload"} {
+		if _, err := os.Stat(path + suffix); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
 // dirExists checks if a directory exists
 func dirExists(path string) bool {
 	info, err := os.Stat(path)
@@ -546,9 +816,15 @@ func CopyFileToDestination(srcPath, destPath string) error {
 	return CopyFile(srcPath, destPath)
 }
 
-// copyFile copies a single file
 // CopyFile copies a file from src to dst, preserving permissions and creating directories as needed
 func CopyFile(src, dst string) error {
+	return CopyFileWithHash(src, dst, nil)
+}
+
+// CopyFileWithHash is like CopyFile but also writes the source bytes to h as they
+// are copied, so callers can checksum the source without reading it twice.
+// A nil h copies without hashing.
+func CopyFileWithHash(src, dst string, h hash.Hash) error {
 	srcFile, err := os.Open(src)
 	if err != nil {
 		return err
@@ -557,29 +833,23 @@ func CopyFile(src, dst string) error {
 		_ = srcFile.Close()
 	}()
 
-	// Create destination directory if needed
-	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
-		return err
-	}
-
-	dstFile, err := os.Create(dst)
+	srcInfo, err := srcFile.Stat()
 	if err != nil {
 		return err
 	}
-	defer func() {
-		_ = dstFile.Close()
-	}()
 
-	if _, err := io.Copy(dstFile, srcFile); err != nil {
+	// Create destination directory if needed
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
 		return err
 	}
 
-	// Copy permissions
-	if info, err := os.Stat(src); err == nil {
-		_ = os.Chmod(dst, info.Mode())
+	var reader io.Reader = srcFile
+	if h != nil {
+		reader = io.TeeReader(srcFile, h)
 	}
 
-	return nil
+	// Write via temp file and rename so an interrupted copy never leaves a partial file
+	return fsutil.WriteReaderAtomic(dst, reader, srcInfo.Mode().Perm())
 }
 
 // copyDir copies a directory recursively
@@ -1,8 +1,12 @@
 package recent
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 )
@@ -168,9 +172,305 @@ func TestIsTemporaryFile(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		result := isTemporaryFile(test.filename)
+		result := IsTemporaryFile(test.filename)
 		if result != test.expected {
-			t.Errorf("isTemporaryFile(%q) = %v, expected %v", test.filename, result, test.expected)
+			t.Errorf("IsTemporaryFile(%q) = %v, expected %v", test.filename, result, test.expected)
 		}
 	}
 }
+
+func TestFindFilesInDirIncludeHiddenAndTemp(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"visible.txt", ".env", ".hidden/inner.txt", "movie.mp4.crdownload"} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+			t.Fatalf("Failed to create file: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		opts FindOptions
+		want int
+	}{
+		{"defaults skip hidden and temp", FindOptions{ExcludeTemp: true}, 1},
+		{"include hidden", FindOptions{ExcludeTemp: true, IncludeHidden: true}, 3},
+		{"include temp", FindOptions{ExcludeTemp: true, IncludeTemp: true}, 2},
+		{"include both", FindOptions{ExcludeTemp: true, IncludeHidden: true, IncludeTemp: true}, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, err := findFilesInDir(dir, time.Time{}, tt.opts)
+			if err != nil {
+				t.Fatalf("findFilesInDir returned error: %v", err)
+			}
+			if len(files) != tt.want {
+				t.Errorf("findFilesInDir found %d files, want %d: %v", len(files), tt.want, files)
+			}
+		})
+	}
+}
+
+func TestMatchesName(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		caseSensitive bool
+		want          bool
+	}{
+		{"Invoice-2024.pdf", "invoice", false, true},
+		{"Invoice-2024.pdf", ".pdf", false, true},
+		{"Invoice-2024.PDF", ".pdf", false, true},
+		{"report.pdf.txt", ".pdf", false, false},
+		{"report.xlsx", "report.xlsx", false, true},
+		{"notes.txt", "invoice", false, false},
+		{"Invoice-2024.pdf", "invoice", true, false},
+		{"Invoice-2024.pdf", "Invoice", true, true},
+		{"Invoice-2024.PDF", ".pdf", true, false},
+	}
+
+	for _, tt := range tests {
+		if got := MatchesName(tt.name, tt.query, tt.caseSensitive); got != tt.want {
+			t.Errorf("MatchesName(%q, %q, %v) = %v, want %v", tt.name, tt.query, tt.caseSensitive, got, tt.want)
+		}
+	}
+}
+
+func TestMatchesMimeType(t *testing.T) {
+	tests := []struct {
+		mimeType string
+		types    []string
+		want     bool
+	}{
+		{"image/png", []string{"image/"}, true},
+		{"image/png", []string{"image/png"}, true},
+		{"text/plain; charset=utf-8", []string{"text/plain"}, true},
+		{"text/plain; charset=utf-8", []string{"text/"}, true},
+		{"application/pdf", []string{"image/", "application/pdf"}, true},
+		{"IMAGE/PNG", []string{"image/png"}, true},
+		{"image/png", []string{"image/jpeg"}, false},
+		{"image/png", []string{"image"}, false},
+		{"", []string{"image/"}, false},
+	}
+	for _, tt := range tests {
+		if got := MatchesMimeType(tt.mimeType, tt.types); got != tt.want {
+			t.Errorf("MatchesMimeType(%q, %v) = %v, want %v", tt.mimeType, tt.types, got, tt.want)
+		}
+	}
+}
+
+func TestFindRecentFilesByType(t *testing.T) {
+	dir := t.TempDir()
+	png, err := os.ReadFile("../../test-files/minimal.png")
+	if err != nil {
+		t.Fatalf("Failed to read test image: %v", err)
+	}
+	files := map[string][]byte{
+		"shot.png":          png,
+		"nested/photo.png":  png,
+		"notes.txt":         []byte("hello"),
+		"nested/report.pdf": []byte("%PDF-1.4\n"),
+		"renamed.bin":       png,
+	}
+	for name, data := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatalf("Failed to create file: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		opts FindOptions
+		want []string
+	}{
+		{"images by type", FindOptions{MimeTypes: []string{"image/"}}, []string{"photo.png", "renamed.bin", "shot.png"}},
+		{"top level only", FindOptions{MimeTypes: []string{"image/"}, NoRecurse: true}, []string{"renamed.bin", "shot.png"}},
+		{"extension or type", FindOptions{Extensions: []string{".txt"}, MimeTypes: []string{"application/pdf"}}, []string{"notes.txt", "report.pdf"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.Directories = []string{dir}
+			found, err := FindRecentFiles(tt.opts)
+			if err != nil {
+				t.Fatalf("FindRecentFiles returned error: %v", err)
+			}
+			var names []string
+			for _, f := range found {
+				names = append(names, f.Name)
+			}
+			sort.Strings(names)
+			if !slices.Equal(names, tt.want) {
+				t.Errorf("FindRecentFiles found %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindRecentFilesNameQuery(t *testing.T) {
+	dir := t.TempDir()
+	old := time.Now().Add(-365 * 24 * time.Hour)
+	for _, name := range []string{"invoice.pdf", "nested/Invoice-old.pdf", "notes.txt"} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+			t.Fatalf("Failed to create file: %v", err)
+		}
+		if err := os.Chtimes(path, old, old); err != nil {
+			t.Fatalf("Failed to set file time: %v", err)
+		}
+	}
+
+	// MaxAge 0 means no age limit, so year-old files still match
+	files, err := FindRecentFiles(FindOptions{Directories: []string{dir}, NameQuery: "invoice", ExcludeTemp: true})
+	if err != nil {
+		t.Fatalf("FindRecentFiles returned error: %v", err)
+	}
+	if len(files) != 2 {
+		t.Errorf("FindRecentFiles found %d files, want 2: %v", len(files), files)
+	}
+}
+
+func TestFindRecentFilesTimings(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.txt", "b.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("x"), 0644); err != nil {
+			t.Fatalf("Failed to create file: %v", err)
+		}
+	}
+
+	timings := &FindTimings{}
+	if _, err := FindRecentFiles(FindOptions{Directories: []string{dir}, Timings: timings}); err != nil {
+		t.Fatalf("FindRecentFiles returned error: %v", err)
+	}
+	if timings.FilesSeen != 2 {
+		t.Errorf("FilesSeen = %d, want 2", timings.FilesSeen)
+	}
+	if timings.Walk < timings.MimeDetect {
+		t.Errorf("Walk %v should include MimeDetect %v", timings.Walk, timings.MimeDetect)
+	}
+}
+
+func TestFindRecentFilesFound(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.txt", "b.txt", "c.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("x"), 0644); err != nil {
+			t.Fatalf("Failed to create file: %v", err)
+		}
+	}
+
+	var found int
+	files, err := FindRecentFiles(FindOptions{Directories: []string{dir}, MaxCount: 2, Found: &found})
+	if err != nil {
+		t.Fatalf("FindRecentFiles returned error: %v", err)
+	}
+	if len(files) != 2 || found != 3 {
+		t.Errorf("FindRecentFiles returned %d files with Found = %d, want 2 of 3", len(files), found)
+	}
+}
+
+func TestWaitForStable(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "report.pdf")
+	if err := os.WriteFile(path, []byte("done"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := WaitForStable(path, 10*time.Millisecond, 100*time.Millisecond); err != nil {
+		t.Errorf("WaitForStable on a finished file returned %v", err)
+	}
+
+	// Firefox-style: the final name exists while name.part is still being written
+	if err := os.WriteFile(path+".part", []byte("partial"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := WaitForStable(path, 10*time.Millisecond, 50*time.Millisecond); !errors.Is(err, ErrNotStable) {
+		t.Errorf("WaitForStable with a .part file = %v, want ErrNotStable", err)
+	}
+
+	// A file that keeps growing is reported once the timeout passes
+	growing := filepath.Join(dir, "growing.bin")
+	f, err := os.Create(growing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				_, _ = f.Write([]byte("x"))
+				time.Sleep(2 * time.Millisecond)
+			}
+		}
+	}()
+	if err := WaitForStable(growing, 20*time.Millisecond, 60*time.Millisecond); !errors.Is(err, ErrNotStable) {
+		t.Errorf("WaitForStable on a growing file = %v, want ErrNotStable", err)
+	}
+
+	if err := WaitForStable(filepath.Join(dir, "missing"), 10*time.Millisecond, 50*time.Millisecond); !os.IsNotExist(err) {
+		t.Errorf("WaitForStable on a missing file = %v, want a not-exist error", err)
+	}
+}
+
+func TestSortFiles(t *testing.T) {
+	now := time.Now()
+	files := []FileInfo{
+		{Name: "b.txt", Size: 300, Modified: now},
+		{Name: "C.pdf", Size: 100, Modified: now.Add(-time.Hour)},
+		{Name: "a.png", Size: 200, Modified: now.Add(-2 * time.Hour)},
+	}
+	tests := []struct {
+		order string
+		want  []string
+	}{
+		{"age", []string{"b.txt", "C.pdf", "a.png"}},
+		{"age:desc", []string{"a.png", "C.pdf", "b.txt"}},
+		{"name", []string{"a.png", "b.txt", "C.pdf"}},
+		{"name:desc", []string{"C.pdf", "b.txt", "a.png"}},
+		{"size", []string{"b.txt", "a.png", "C.pdf"}},
+		{"SIZE:asc", []string{"C.pdf", "a.png", "b.txt"}},
+	}
+	for _, tt := range tests {
+		order, err := ParseSortOrder(tt.order)
+		if err != nil {
+			t.Fatalf("ParseSortOrder(%q) returned error: %v", tt.order, err)
+		}
+		sorted := append([]FileInfo(nil), files...)
+		SortFiles(sorted, order)
+		var got []string
+		for _, f := range sorted {
+			got = append(got, f.Name)
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("SortFiles(%s) = %v, want %v", tt.order, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"", "date", "size:up"} {
+		if _, err := ParseSortOrder(bad); err == nil {
+			t.Errorf("ParseSortOrder(%q) succeeded, want error", bad)
+		}
+	}
+
+	// Next visits every order and comes back around
+	order := DefaultSortOrder
+	for i := 0; i < 6; i++ {
+		order = order.Next()
+	}
+	if order != DefaultSortOrder {
+		t.Errorf("six Next() calls ended at %s, want %s", order, DefaultSortOrder)
+	}
+}
@@ -0,0 +1,122 @@
+//go:build darwin
+
+package spotlight
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultDateWindow is the modification window used for screenshot searches and
+// suggested for SearchOptions.ModifiedSince. Restricting the date dramatically
+// reduces the result set at the Spotlight level.
+const DefaultDateWindow = 90 * 24 * time.Hour
+
+// screenshotPredicate matches screenshots by the capture flag or macOS's default name
+const screenshotPredicate = "kMDItemIsScreenCapture == 1 || kMDItemFSName == 'Screenshot *'cd"
+
+// knownAttributes are the metadata attributes accepted in SearchOptions.Attributes.
+// Restricting names keeps user input from producing malformed queries.
+var knownAttributes = map[string]bool{
+	"kMDItemAudioChannelCount": true,
+	"kMDItemAuthors":           true,
+	"kMDItemContentType":       true,
+	"kMDItemContentTypeTree":   true,
+	"kMDItemCreator":           true,
+	"kMDItemDisplayName":       true,
+	"kMDItemDurationSeconds":   true,
+	"kMDItemFinderComment":     true,
+	"kMDItemFSName":            true,
+	"kMDItemIsScreenCapture":   true,
+	"kMDItemKind":              true,
+	"kMDItemPixelHeight":       true,
+	"kMDItemPixelWidth":        true,
+	"kMDItemTitle":             true,
+	"kMDItemUserTags":          true,
+	"kMDItemWhereFroms":        true,
+}
+
+// KnownAttributes returns the attribute names accepted in SearchOptions.Attributes, sorted
+func KnownAttributes() []string {
+	names := make([]string, 0, len(knownAttributes))
+	for name := range knownAttributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// escapeQueryValue escapes quotes and backslashes for use inside a quoted query value
+func escapeQueryValue(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `'`, `\'`)
+	return strings.ReplaceAll(value, `"`, `\"`)
+}
+
+// buildNameQuery builds the filename predicate for a search query.
+// ".pdf" matches names ending with .pdf; anything else ("invoice", "report.xlsx")
+// matches names containing the string. Matching is always diacritic insensitive
+// ("resume" finds "résumé") and case insensitive unless caseSensitive is set.
+// recent.MatchesName applies the same rules when find falls back to walking folders.
+func buildNameQuery(query string, caseSensitive bool) string {
+	value := escapeQueryValue(query)
+	flags := "cd"
+	if caseSensitive {
+		flags = "d"
+	}
+	if strings.HasPrefix(query, ".") {
+		return fmt.Sprintf("kMDItemFSName == '*%s'%s", value, flags)
+	}
+	return fmt.Sprintf("kMDItemFSName == '*%s*'%s", value, flags)
+}
+
+// withModifiedSince restricts a predicate to files modified at or after since.
+// A zero since leaves the predicate unrestricted.
+func withModifiedSince(predicate string, since time.Time) string {
+	if since.IsZero() {
+		return predicate
+	}
+	return fmt.Sprintf("(%s) && kMDItemContentModificationDate >= $time.iso(%s)", predicate, since.UTC().Format("2006-01-02T15:04:05Z"))
+}
+
+// numericValue matches plain integers and decimals, which are left unquoted in queries
+var numericValue = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+
+// attributePredicate builds an equality predicate for a metadata attribute.
+// Numeric values (e.g. kMDItemIsScreenCapture=1) are compared as numbers, anything
+// else as a case and diacritic insensitive string that may contain * wildcards.
+func attributePredicate(name, value string) string {
+	if numericValue.MatchString(value) {
+		return fmt.Sprintf("%s == %s", name, value)
+	}
+	return fmt.Sprintf("%s == '%s'cd", name, escapeQueryValue(value))
+}
+
+// buildQuery combines the filename query and attribute filters into a Spotlight query
+func buildQuery(opts SearchOptions) (string, error) {
+	var predicates []string
+	if opts.Query != "" {
+		predicates = append(predicates, buildNameQuery(opts.Query, opts.CaseSensitive))
+	}
+
+	// Sort attribute names so the query is deterministic
+	names := make([]string, 0, len(opts.Attributes))
+	for name := range opts.Attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !knownAttributes[name] {
+			return "", fmt.Errorf("unknown Spotlight attribute %q (known: %s)", name, strings.Join(KnownAttributes(), ", "))
+		}
+		predicates = append(predicates, attributePredicate(name, opts.Attributes[name]))
+	}
+
+	if len(predicates) == 0 {
+		return "", fmt.Errorf("search query cannot be empty")
+	}
+	return withModifiedSince(strings.Join(predicates, " && "), opts.ModifiedSince), nil
+}
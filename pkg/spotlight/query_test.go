@@ -0,0 +1,94 @@
+//go:build darwin
+
+package spotlight
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBuildNameQuery(t *testing.T) {
+	tests := []struct {
+		query         string
+		caseSensitive bool
+		want          string
+	}{
+		{"invoice", false, "kMDItemFSName == '*invoice*'cd"},
+		{".pdf", false, "kMDItemFSName == '*.pdf'cd"},
+		{"report.xlsx", false, "kMDItemFSName == '*report.xlsx*'cd"},
+		{"it's", false, `kMDItemFSName == '*it\'s*'cd`},
+		{"README", true, "kMDItemFSName == '*README*'d"},
+		{".PDF", true, "kMDItemFSName == '*.PDF'd"},
+	}
+
+	for _, tt := range tests {
+		if got := buildNameQuery(tt.query, tt.caseSensitive); got != tt.want {
+			t.Errorf("buildNameQuery(%q, %v) = %q, want %q", tt.query, tt.caseSensitive, got, tt.want)
+		}
+	}
+}
+
+func TestWithModifiedSince(t *testing.T) {
+	since := time.Date(2025, 3, 1, 12, 30, 0, 0, time.UTC)
+	got := withModifiedSince(screenshotPredicate, since)
+	want := "(kMDItemIsScreenCapture == 1 || kMDItemFSName == 'Screenshot *'cd) && kMDItemContentModificationDate >= $time.iso(2025-03-01T12:30:00Z)"
+	if got != want {
+		t.Errorf("withModifiedSince(screenshotPredicate, %v) = %q, want %q", since, got, want)
+	}
+
+	if got := withModifiedSince(screenshotPredicate, time.Time{}); got != screenshotPredicate {
+		t.Errorf("withModifiedSince with zero time = %q, want %q", got, screenshotPredicate)
+	}
+}
+
+func TestBuildQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    SearchOptions
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "name only",
+			opts: SearchOptions{Query: "invoice"},
+			want: "kMDItemFSName == '*invoice*'cd",
+		},
+		{
+			name: "attributes only",
+			opts: SearchOptions{Attributes: map[string]string{"kMDItemIsScreenCapture": "1"}},
+			want: "kMDItemIsScreenCapture == 1",
+		},
+		{
+			name: "name and string attribute",
+			opts: SearchOptions{Query: ".pdf", Attributes: map[string]string{"kMDItemAuthors": "Neil*"}},
+			want: "kMDItemFSName == '*.pdf'cd && kMDItemAuthors == 'Neil*'cd",
+		},
+		{
+			name: "modified since",
+			opts: SearchOptions{Query: "invoice", ModifiedSince: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+			want: "(kMDItemFSName == '*invoice*'cd) && kMDItemContentModificationDate >= $time.iso(2024-01-02T00:00:00Z)",
+		},
+		{
+			name:    "unknown attribute",
+			opts:    SearchOptions{Query: "x", Attributes: map[string]string{"kMDItemBogus') || (true": "1"}},
+			wantErr: true,
+		},
+		{
+			name:    "empty",
+			opts:    SearchOptions{},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildQuery(tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("buildQuery(%+v) error = %v, wantErr %v", tt.opts, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("buildQuery(%+v) = %q, want %q", tt.opts, got, tt.want)
+			}
+		})
+	}
+}
@@ -8,146 +8,82 @@ package spotlight
 #import <CoreServices/CoreServices.h>
 #import <Foundation/Foundation.h>
 
-// FileItem represents a file with its modification date from Spotlight
-typedef struct {
-	char* path;
-	double modTime; // CFAbsoluteTime
-} FileItem;
-
-// searchFiles performs a Spotlight search and returns matching file paths with mod times
-FileItem* searchFiles(const char* query, int* resultCount, int maxResults) {
+// openQuery creates and synchronously executes a Spotlight query.
+// Results are sorted by modification date (oldest first) so callers can walk
+// them from the end and stop once they have enough of the newest files.
+MDQueryRef openQuery(const char* query, int* resultCount) {
 	@autoreleasepool {
-		NSString *queryStr = [NSString stringWithUTF8String:query];
-
-		// Build base filename query
-		NSString *nameQuery;
-		if ([queryStr hasPrefix:@"."]) {
-			// Extension search: ".pdf" -> files ending with .pdf
-			nameQuery = [NSString stringWithFormat:@"kMDItemFSName == '*%@'cd", queryStr];
-		} else {
-			// Substring search: "invoice" or "report.xlsx" -> files containing the string
-			nameQuery = [NSString stringWithFormat:@"kMDItemFSName == '*%@*'cd", queryStr];
-		}
-
-		// Add date filter: only files modified in last 90 days
-		// This dramatically reduces the result set at the Spotlight level
-		NSString *queryFormat = [NSString stringWithFormat:@"%@ && kMDItemContentModificationDate >= $time.today(-90)", nameQuery];
+		NSString *queryFormat = [NSString stringWithUTF8String:query];
+		*resultCount = 0;
 
-		MDQueryRef mdQuery = MDQueryCreate(kCFAllocatorDefault, (__bridge CFStringRef)queryFormat, NULL, NULL);
+		CFStringRef sortAttrs[] = { kMDItemContentModificationDate };
+		CFArrayRef sorting = CFArrayCreate(kCFAllocatorDefault, (const void **)sortAttrs, 1, &kCFTypeArrayCallBacks);
+		MDQueryRef mdQuery = MDQueryCreate(kCFAllocatorDefault, (__bridge CFStringRef)queryFormat, NULL, sorting);
+		CFRelease(sorting);
 
 		if (!mdQuery) {
-			*resultCount = 0;
 			return NULL;
 		}
 
-		// Note: We sort results in Go after fetching
-		// MDQuery sorting APIs are unreliable
-
 		// Execute the query synchronously
 		Boolean success = MDQueryExecute(mdQuery, kMDQuerySynchronous);
 		if (!success) {
 			CFRelease(mdQuery);
-			*resultCount = 0;
 			return NULL;
 		}
 
-		// Get result count
-		CFIndex count = MDQueryGetResultCount(mdQuery);
-		if (count == 0) {
-			CFRelease(mdQuery);
-			*resultCount = 0;
-			return NULL;
-		}
+		*resultCount = (int)MDQueryGetResultCount(mdQuery);
+		return mdQuery;
+	}
+}
 
-		// Limit results
-		if (maxResults > 0 && count > maxResults) {
-			count = maxResults;
-		}
+// queryResultAt returns the path of result i (caller frees) and stores its modification time
+char* queryResultAt(MDQueryRef mdQuery, int i, double* modTime) {
+	@autoreleasepool {
+		*modTime = 0.0;
 
-		// Allocate array for results
-		FileItem *results = (FileItem *)malloc(sizeof(FileItem) * count);
-		int actualCount = 0;
-
-		// Get file paths and modification times from Spotlight
-		for (CFIndex i = 0; i < count; i++) {
-			MDItemRef item = (MDItemRef)MDQueryGetResultAtIndex(mdQuery, i);
-			if (!item) continue;
-
-			// Get path
-			CFStringRef pathRef = MDItemCopyAttribute(item, kMDItemPath);
-			if (!pathRef) continue;
-
-			const char *pathCStr = CFStringGetCStringPtr(pathRef, kCFStringEncodingUTF8);
-			char buffer[4096];
-			if (!pathCStr) {
-				// If direct pointer fails, use buffer
-				if (CFStringGetCString(pathRef, buffer, sizeof(buffer), kCFStringEncodingUTF8)) {
-					pathCStr = buffer;
-				}
-			}
+		MDItemRef item = (MDItemRef)MDQueryGetResultAtIndex(mdQuery, i);
+		if (!item) return NULL;
 
-			// Get modification date from Spotlight
-			CFDateRef modDateRef = MDItemCopyAttribute(item, kMDItemContentModificationDate);
-			double modTime = 0.0;
-			if (modDateRef) {
-				modTime = CFDateGetAbsoluteTime(modDateRef);
-				CFRelease(modDateRef);
-			}
+		// Get path
+		CFStringRef pathRef = MDItemCopyAttribute(item, kMDItemPath);
+		if (!pathRef) return NULL;
 
-			if (pathCStr) {
-				results[actualCount].path = strdup(pathCStr);
-				results[actualCount].modTime = modTime;
-				actualCount++;
+		const char *pathCStr = CFStringGetCStringPtr(pathRef, kCFStringEncodingUTF8);
+		char buffer[4096];
+		if (!pathCStr) {
+			// If direct pointer fails, use buffer
+			if (CFStringGetCString(pathRef, buffer, sizeof(buffer), kCFStringEncodingUTF8)) {
+				pathCStr = buffer;
 			}
-
-			CFRelease(pathRef);
+		}
+		char *path = pathCStr ? strdup(pathCStr) : NULL;
+		CFRelease(pathRef);
+
+		// Get modification date from Spotlight
+		CFDateRef modDateRef = MDItemCopyAttribute(item, kMDItemContentModificationDate);
+		if (modDateRef) {
+			*modTime = CFDateGetAbsoluteTime(modDateRef);
+			CFRelease(modDateRef);
 		}
 
-		CFRelease(mdQuery);
-		*resultCount = actualCount;
-		return results;
+		return path;
 	}
 }
 
-// freeResults frees the memory allocated by searchFiles
-void freeResults(FileItem* results, int count) {
-	for (int i = 0; i < count; i++) {
-		free(results[i].path);
-	}
-	free(results);
+// closeQuery releases a query created by openQuery
+void closeQuery(MDQueryRef mdQuery) {
+	if (mdQuery) CFRelease(mdQuery);
 }
 */
 import "C"
 import (
-	"fmt"
 	"os"
 	"sort"
 	"time"
 	"unsafe"
 )
 
-// SearchOptions configures Spotlight search behavior
-type SearchOptions struct {
-	Query      string   // Search query (filename pattern)
-	Scope      []string // Optional: limit to specific directories (not implemented yet)
-	MaxResults int      // Optional: limit result count (0 = no limit)
-}
-
-// FileResult represents a file found by Spotlight
-type FileResult struct {
-	Path string // Full path to the file
-	Name string // Filename only (extracted from path)
-}
-
-// FileInfo represents a file with full metadata (compatible with recent.FileInfo)
-type FileInfo struct {
-	Path     string
-	Name     string
-	Size     int64
-	Modified time.Time
-	IsDir    bool
-}
-
 // cfAbsoluteTimeToGoTime converts CFAbsoluteTime to Go time.Time
 // CFAbsoluteTime is seconds since 2001-01-01 00:00:00 UTC
 // Unix epoch is 1970-01-01 00:00:00 UTC
@@ -158,10 +94,46 @@ func cfAbsoluteTimeToGoTime(cfTime float64) time.Time {
 	return time.Unix(unixTime, 0)
 }
 
+// queryResult is a single raw Spotlight match
+type queryResult struct {
+	Path     string
+	Modified time.Time
+}
+
+// streamQuery executes a raw Spotlight query and calls fn with each match,
+// newest first, until fn returns false
+func streamQuery(query string, fn func(queryResult) bool) {
+	cQuery := C.CString(query)
+	defer C.free(unsafe.Pointer(cQuery))
+
+	var resultCount C.int
+	mdQuery := C.openQuery(cQuery, &resultCount)
+	if mdQuery == nil {
+		return
+	}
+	defer C.closeQuery(mdQuery)
+
+	// Results are sorted oldest first, so walk backwards
+	for i := int(resultCount) - 1; i >= 0; i-- {
+		var modTime C.double
+		cPath := C.queryResultAt(mdQuery, C.int(i), &modTime)
+		if cPath == nil {
+			continue
+		}
+		path := C.GoString(cPath)
+		C.free(unsafe.Pointer(cPath))
+
+		if !fn(queryResult{Path: path, Modified: cfAbsoluteTimeToGoTime(float64(modTime))}) {
+			return
+		}
+	}
+}
+
 // Search performs a Spotlight search for files matching the query
 func Search(opts SearchOptions) ([]FileResult, error) {
-	if opts.Query == "" {
-		return nil, fmt.Errorf("search query cannot be empty")
+	query, err := buildQuery(opts)
+	if err != nil {
+		return nil, err
 	}
 
 	maxResults := opts.MaxResults
@@ -169,38 +141,39 @@ func Search(opts SearchOptions) ([]FileResult, error) {
 		maxResults = 100 // Default limit to prevent overwhelming results
 	}
 
-	cQuery := C.CString(opts.Query)
-	defer C.free(unsafe.Pointer(cQuery))
-
-	var resultCount C.int
-	cResults := C.searchFiles(cQuery, &resultCount, C.int(maxResults))
-
-	if cResults == nil || resultCount == 0 {
-		return []FileResult{}, nil // No results found
-	}
-	defer C.freeResults(cResults, resultCount)
+	results := []FileResult{}
+	streamQuery(query, func(match queryResult) bool {
+		results = append(results, FileResult{
+			Path: match.Path,
+			Name: extractFilename(match.Path),
+		})
+		return len(results) < maxResults
+	})
 
-	// Convert C array to Go slice
-	results := make([]FileResult, int(resultCount))
-	cResultsSlice := (*[1 << 28]C.FileItem)(unsafe.Pointer(cResults))[:resultCount:resultCount]
+	return results, nil
+}
 
-	for i := 0; i < int(resultCount); i++ {
-		path := C.GoString(cResultsSlice[i].path)
-		results[i] = FileResult{
-			Path: path,
-			Name: extractFilename(path),
-		}
+// SearchStream performs a Spotlight search and calls fn with each accessible match,
+// newest first, until fn returns false or MaxResults files were delivered (0 = no limit).
+// Files are stat'ed one at a time as they are delivered, so stopping early skips the
+// cost of fetching metadata for the remaining matches.
+func SearchStream(opts SearchOptions, fn func(FileInfo) bool) error {
+	query, err := buildQuery(opts)
+	if err != nil {
+		return err
 	}
 
-	return results, nil
+	streamMetadata(query, opts.MaxResults, fn)
+	return nil
 }
 
 // SearchWithMetadata performs a Spotlight search and enriches results with file metadata
 // This is the high-level business function that returns files ready for use
 // Results are sorted by modification time (most recent first)
 func SearchWithMetadata(opts SearchOptions) ([]FileInfo, error) {
-	if opts.Query == "" {
-		return nil, fmt.Errorf("search query cannot be empty")
+	query, err := buildQuery(opts)
+	if err != nil {
+		return nil, err
 	}
 
 	maxResults := opts.MaxResults
@@ -208,47 +181,61 @@ func SearchWithMetadata(opts SearchOptions) ([]FileInfo, error) {
 		maxResults = 100 // Default limit to prevent overwhelming results
 	}
 
-	cQuery := C.CString(opts.Query)
-	defer C.free(unsafe.Pointer(cQuery))
-
-	var resultCount C.int
-	cResults := C.searchFiles(cQuery, &resultCount, C.int(maxResults))
+	return collectMetadata(query, maxResults), nil
+}
 
-	if cResults == nil || resultCount == 0 {
-		return []FileInfo{}, nil // No results found
+// SearchScreenshots finds screenshots modified in the last 90 days, most recent first.
+// A file counts as a screenshot if Spotlight flags it with kMDItemIsScreenCapture or
+// it has macOS's default "Screenshot " name prefix. This relies on macOS metadata and
+// only finds screenshots in locations Spotlight indexes.
+func SearchScreenshots(maxResults int) ([]FileInfo, error) {
+	if maxResults == 0 {
+		maxResults = 100 // Default limit to prevent overwhelming results
 	}
-	defer C.freeResults(cResults, resultCount)
 
-	// Convert C array to Go slice with full metadata
-	cResultsSlice := (*[1 << 28]C.FileItem)(unsafe.Pointer(cResults))[:resultCount:resultCount]
-	var files []FileInfo
-
-	for i := 0; i < int(resultCount); i++ {
-		path := C.GoString(cResultsSlice[i].path)
-		modTime := cfAbsoluteTimeToGoTime(float64(cResultsSlice[i].modTime))
+	since := time.Now().Add(-DefaultDateWindow)
+	return collectMetadata(withModifiedSince(screenshotPredicate, since), maxResults), nil
+}
 
+// streamMetadata stats each match of a query and passes it to fn, skipping files
+// that can't be accessed, until fn returns false or maxResults files were delivered
+func streamMetadata(query string, maxResults int, fn func(FileInfo) bool) {
+	delivered := 0
+	streamQuery(query, func(match queryResult) bool {
 		// Get size and IsDir from os.Stat (these aren't available from Spotlight)
-		info, err := os.Stat(path)
+		info, err := os.Stat(match.Path)
 		if err != nil {
 			// Skip files that can't be accessed
-			continue
+			return true
 		}
 
-		files = append(files, FileInfo{
-			Path:     path,
-			Name:     extractFilename(path),
+		delivered++
+		keepGoing := fn(FileInfo{
+			Path:     match.Path,
+			Name:     extractFilename(match.Path),
 			Size:     info.Size(),
-			Modified: modTime, // Use modification time from Spotlight
+			Modified: match.Modified, // Use modification time from Spotlight
 			IsDir:    info.IsDir(),
 		})
-	}
+		return keepGoing && (maxResults <= 0 || delivered < maxResults)
+	})
+}
+
+// collectMetadata gathers up to maxResults matches, sorted most recent first
+func collectMetadata(query string, maxResults int) []FileInfo {
+	files := []FileInfo{}
+	streamMetadata(query, maxResults, func(file FileInfo) bool {
+		files = append(files, file)
+		return true
+	})
 
-	// Sort by modification time, most recent first
-	sort.Slice(files, func(i, j int) bool {
+	// Spotlight already returns newest first; sorting here also orders files
+	// whose modification date Spotlight didn't report
+	sort.SliceStable(files, func(i, j int) bool {
 		return files[i].Modified.After(files[j].Modified)
 	})
 
-	return files, nil
+	return files
 }
 
 // extractFilename extracts the filename from a full path
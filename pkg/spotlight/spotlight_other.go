@@ -0,0 +1,33 @@
+//go:build !darwin
+
+package spotlight
+
+import "errors"
+
+// ErrUnsupported is returned outside macOS, where there is no Spotlight index
+var ErrUnsupported = errors.New("spotlight search is only supported on macOS")
+
+// Search returns ErrUnsupported outside macOS
+func Search(opts SearchOptions) ([]FileResult, error) {
+	return nil, ErrUnsupported
+}
+
+// SearchStream returns ErrUnsupported outside macOS
+func SearchStream(opts SearchOptions, fn func(FileInfo) bool) error {
+	return ErrUnsupported
+}
+
+// SearchWithMetadata returns ErrUnsupported outside macOS
+func SearchWithMetadata(opts SearchOptions) ([]FileInfo, error) {
+	return nil, ErrUnsupported
+}
+
+// SearchScreenshots returns ErrUnsupported outside macOS
+func SearchScreenshots(maxResults int) ([]FileInfo, error) {
+	return nil, ErrUnsupported
+}
+
+// IndexingEnabled returns ErrUnsupported outside macOS
+func IndexingEnabled(path string) (bool, error) {
+	return false, ErrUnsupported
+}
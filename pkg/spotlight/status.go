@@ -0,0 +1,34 @@
+//go:build darwin
+
+package spotlight
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// IndexingEnabled reports whether Spotlight indexing is enabled for the volume
+// containing path. When it is off, queries succeed but return no results, so
+// callers can use this to tell "nothing matched" apart from "nothing indexed".
+func IndexingEnabled(path string) (bool, error) {
+	out, err := exec.Command("mdutil", "-s", path).CombinedOutput()
+	if err != nil {
+		return false, fmt.Errorf("could not check Spotlight status for %s: %w", path, err)
+	}
+	return parseIndexingStatus(string(out))
+}
+
+// parseIndexingStatus interprets the output of `mdutil -s`
+func parseIndexingStatus(output string) (bool, error) {
+	lower := strings.ToLower(output)
+	switch {
+	case strings.Contains(lower, "indexing enabled"):
+		return true, nil
+	case strings.Contains(lower, "indexing disabled"),
+		strings.Contains(lower, "indexing and searching disabled"),
+		strings.Contains(lower, "spotlight server is disabled"):
+		return false, nil
+	}
+	return false, fmt.Errorf("unrecognized Spotlight status: %s", strings.TrimSpace(output))
+}
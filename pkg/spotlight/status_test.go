@@ -0,0 +1,30 @@
+//go:build darwin
+
+package spotlight
+
+import "testing"
+
+func TestParseIndexingStatus(t *testing.T) {
+	tests := []struct {
+		output  string
+		want    bool
+		wantErr bool
+	}{
+		{"/:\n\tIndexing enabled. \n", true, false},
+		{"/:\n\tIndexing disabled.\n", false, false},
+		{"/Volumes/NAS:\n\tIndexing and searching disabled.\n", false, false},
+		{"/:\n\tSpotlight server is disabled.\n", false, false},
+		{"/:\n\tError: unknown indexing state.\n", false, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseIndexingStatus(tt.output)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseIndexingStatus(%q) error = %v, wantErr %v", tt.output, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseIndexingStatus(%q) = %v, want %v", tt.output, got, tt.want)
+		}
+	}
+}
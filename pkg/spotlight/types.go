@@ -0,0 +1,33 @@
+package spotlight
+
+import "time"
+
+// SearchOptions configures Spotlight search behavior
+type SearchOptions struct {
+	Query      string            // Search query (filename pattern)
+	Scope      []string          // Optional: limit to specific directories (not implemented yet)
+	MaxResults int               // Optional: limit result count (0 = no limit)
+	Attributes map[string]string // Optional: metadata filters, e.g. {"kMDItemIsScreenCapture": "1"}
+
+	// ModifiedSince only matches files modified at or after this time (zero = no date filter).
+	// Wider windows return more results and may be slower; DefaultDateWindow is a good default.
+	ModifiedSince time.Time
+
+	// CaseSensitive matches Query with exact case. Matching ignores diacritics either way.
+	CaseSensitive bool
+}
+
+// FileResult represents a file found by Spotlight
+type FileResult struct {
+	Path string // Full path to the file
+	Name string // Filename only (extracted from path)
+}
+
+// FileInfo represents a file with full metadata (compatible with recent.FileInfo)
+type FileInfo struct {
+	Path     string
+	Name     string
+	Size     int64
+	Modified time.Time
+	IsDir    bool
+}
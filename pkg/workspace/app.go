@@ -0,0 +1,7 @@
+package workspace
+
+// App identifies a running application
+type App struct {
+	BundleID string // e.g. "com.apple.Terminal"
+	Name     string // Localized name shown in the Dock, e.g. "Terminal"
+}
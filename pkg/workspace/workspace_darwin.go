@@ -0,0 +1,125 @@
+//go:build darwin
+
+// Package workspace hands files to Finder and other apps through NSWorkspace.
+package workspace
+
+/*
+#cgo CFLAGS: -x objective-c
+#cgo LDFLAGS: -framework Foundation -framework AppKit
+#import <Foundation/Foundation.h>
+#import <AppKit/NSWorkspace.h>
+
+// revealFiles selects the files in a Finder window, bringing Finder to the
+// foreground. Files in different folders each get their own window.
+void revealFiles(const char **paths, int count) {
+	@autoreleasepool {
+		NSMutableArray<NSURL *> *urls = [NSMutableArray arrayWithCapacity:count];
+		for (int i = 0; i < count; i++) {
+			[urls addObject:[NSURL fileURLWithPath:[NSString stringWithUTF8String:paths[i]]]];
+		}
+		[[NSWorkspace sharedWorkspace] activateFileViewerSelectingURLs:urls];
+	}
+}
+
+// openFile opens the file with its default application. Returns 0 if no
+// application could open it.
+int openFile(const char *path) {
+	@autoreleasepool {
+		NSURL *url = [NSURL fileURLWithPath:[NSString stringWithUTF8String:path]];
+		return [[NSWorkspace sharedWorkspace] openURL:url] ? 1 : 0;
+	}
+}
+
+// frontmostApp copies the bundle identifier and name of the app that receives
+// key events into the buffers. Returns 0 if there is no frontmost app.
+int frontmostApp(char *bundleID, int bundleIDSize, char *name, int nameSize) {
+	@autoreleasepool {
+		NSRunningApplication *app = [[NSWorkspace sharedWorkspace] frontmostApplication];
+		if (app == nil) {
+			return 0;
+		}
+		bundleID[0] = 0;
+		name[0] = 0;
+		if (app.bundleIdentifier != nil) {
+			[app.bundleIdentifier getCString:bundleID maxLength:bundleIDSize encoding:NSUTF8StringEncoding];
+		}
+		if (app.localizedName != nil) {
+			[app.localizedName getCString:name maxLength:nameSize encoding:NSUTF8StringEncoding];
+		}
+		return 1;
+	}
+}
+*/
+import "C"
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"unsafe"
+)
+
+// Reveal selects the files in Finder and brings Finder to the foreground
+func Reveal(paths []string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+
+	cPaths := make([]*C.char, len(paths))
+	for i, path := range paths {
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return fmt.Errorf("could not resolve %s: %w", path, err)
+		}
+		if _, err := os.Stat(absPath); err != nil {
+			return fmt.Errorf("could not reveal %s: %w", path, err)
+		}
+		cPaths[i] = C.CString(absPath)
+		defer C.free(unsafe.Pointer(cPaths[i]))
+	}
+
+	C.revealFiles((**C.char)(unsafe.Pointer(&cPaths[0])), C.int(len(cPaths)))
+	return nil
+}
+
+// Open opens each file with its default application, as double-clicking it in
+// Finder would
+func Open(paths []string) error {
+	for _, path := range paths {
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return fmt.Errorf("could not resolve %s: %w", path, err)
+		}
+		if _, err := os.Stat(absPath); err != nil {
+			return fmt.Errorf("could not open %s: %w", path, err)
+		}
+		cPath := C.CString(absPath)
+		ok := C.openFile(cPath)
+		C.free(unsafe.Pointer(cPath))
+		if ok == 0 {
+			return fmt.Errorf("no application could open %s", path)
+		}
+	}
+	return nil
+}
+
+// FrontmostApp returns the app in front, the one that will receive a paste
+func FrontmostApp() (App, error) {
+	bundleID := make([]byte, 256)
+	name := make([]byte, 256)
+	if C.frontmostApp((*C.char)(unsafe.Pointer(&bundleID[0])), C.int(len(bundleID)),
+		(*C.char)(unsafe.Pointer(&name[0])), C.int(len(name))) == 0 {
+		return App{}, errors.New("no app is in front")
+	}
+	return App{BundleID: cString(bundleID), Name: cString(name)}, nil
+}
+
+// cString returns the NUL-terminated string at the start of buf
+func cString(buf []byte) string {
+	if i := bytes.IndexByte(buf, 0); i >= 0 {
+		return string(buf[:i])
+	}
+	return string(buf)
+}
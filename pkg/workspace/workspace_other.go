@@ -0,0 +1,24 @@
+//go:build !darwin
+
+// Package workspace hands files to Finder and other apps through NSWorkspace.
+package workspace
+
+import "errors"
+
+// ErrUnsupported is returned outside macOS, where there is no NSWorkspace to use
+var ErrUnsupported = errors.New("revealing and opening files and finding the frontmost app are only supported on macOS")
+
+// Reveal does nothing outside macOS and returns ErrUnsupported
+func Reveal(paths []string) error {
+	return ErrUnsupported
+}
+
+// Open does nothing outside macOS and returns ErrUnsupported
+func Open(paths []string) error {
+	return ErrUnsupported
+}
+
+// FrontmostApp returns ErrUnsupported outside macOS
+func FrontmostApp() (App, error) {
+	return App{}, ErrUnsupported
+}
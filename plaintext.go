@@ -0,0 +1,22 @@
+package clippy
+
+import "github.com/neilberkman/clippy/pkg/clipboard"
+
+var preferPlainText bool
+
+// SetPreferPlainText makes every text copy use plain text instead of the
+// detected or requested type (HTML, RTF, JSON, Markdown, ...), for paste targets
+// such as terminals that only read plain text. Multi-representation copies
+// (CopyFlavors, CopyQuote) are unchanged. Off by default.
+func SetPreferPlainText(enabled bool) {
+	preferPlainText = enabled
+}
+
+// textType returns the type to write text as: typeIdentifier, or plain text if
+// SetPreferPlainText is on
+func textType(typeIdentifier string) string {
+	if preferPlainText {
+		return clipboard.PlainTextType
+	}
+	return typeIdentifier
+}
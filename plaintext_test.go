@@ -0,0 +1,35 @@
+package clippy
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/neilberkman/clippy/pkg/clipboard"
+)
+
+func TestSetPreferPlainText(t *testing.T) {
+	mem := useMemoryClipboard(t)
+
+	const page = "<!DOCTYPE html><html><body><p>hi</p></body></html>"
+	if err := CopyTextWithAutoDetection(page); err != nil {
+		t.Fatalf("CopyTextWithAutoDetection() error = %v", err)
+	}
+	if types := mem.GetClipboardTypes(); !slices.Contains(types, "public.html") {
+		t.Fatalf("clipboard types = %v, want public.html", types)
+	}
+
+	SetPreferPlainText(true)
+	defer SetPreferPlainText(false)
+
+	for name, copyText := range map[string]func() error{
+		"detected":  func() error { return CopyTextWithAutoDetection(page) },
+		"requested": func() error { return CopyTextWithType(`{"a": 1}`, "application/json") },
+	} {
+		if err := copyText(); err != nil {
+			t.Fatalf("%s copy error = %v", name, err)
+		}
+		if types := mem.GetClipboardTypes(); !slices.Equal(types, []string{clipboard.PlainTextType}) {
+			t.Errorf("%s copy: clipboard types = %v, want only %s", name, types, clipboard.PlainTextType)
+		}
+	}
+}
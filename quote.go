@@ -0,0 +1,53 @@
+package clippy
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"strings"
+
+	"github.com/neilberkman/clippy/pkg/clipboard"
+)
+
+// CopyQuote copies text as an attributed quote. Rich editors get HTML: a
+// blockquote with a cite link to source. Everything else gets plain text with the
+// source on its own line. source must be an http or https URL, or empty for a
+// quote without attribution. Both are HTML-escaped.
+func CopyQuote(text string, source string) error {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return fmt.Errorf("no text to quote")
+	}
+	if source != "" {
+		u, err := url.Parse(source)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("quote source must be an http or https URL: %s", source)
+		}
+	}
+
+	if err := writeClipboardFlavors([]clipboard.Flavor{
+		{Type: "public.html", Data: []byte(quoteHTML(text, source))},
+		{Type: clipboard.PlainTextType, Data: []byte(quoteText(text, source))},
+	}); err != nil {
+		return fmt.Errorf("could not copy quote to clipboard: %w", err)
+	}
+	return nil
+}
+
+// quoteHTML renders the quote as a blockquote, keeping line breaks
+func quoteHTML(text string, source string) string {
+	body := strings.ReplaceAll(html.EscapeString(text), "\n", "<br>\n")
+	if source == "" {
+		return "<blockquote><p>" + body + "</p></blockquote>"
+	}
+	src := html.EscapeString(source)
+	return fmt.Sprintf(`<blockquote cite="%s"><p>%s</p><footer>— <cite><a href="%s">%s</a></cite></footer></blockquote>`, src, body, src, src)
+}
+
+// quoteText renders the quote for plain text targets
+func quoteText(text string, source string) string {
+	if source == "" {
+		return "“" + text + "”"
+	}
+	return "“" + text + "”\n— " + source
+}
@@ -0,0 +1,41 @@
+package clippy
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCopyQuote(t *testing.T) {
+	mem := useMemoryClipboard(t)
+
+	if err := CopyQuote("Fish & <chips>\nare \"great\"", "https://example.com/a?b=1&c=2"); err != nil {
+		t.Fatalf("CopyQuote returned error: %v", err)
+	}
+	htmlData, ok := mem.GetClipboardDataForType("public.html")
+	if !ok {
+		t.Fatal("no HTML on the clipboard")
+	}
+	got := string(htmlData)
+	for _, want := range []string{
+		"Fish &amp; &lt;chips&gt;<br>",
+		`<a href="https://example.com/a?b=1&amp;c=2">`,
+		`<blockquote cite="https://example.com/a?b=1&amp;c=2">`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("HTML %q is missing %q", got, want)
+		}
+	}
+	if text, _ := mem.GetText(); text != "“Fish & <chips>\nare \"great\"”\n— https://example.com/a?b=1&c=2" {
+		t.Errorf("plain text = %q", text)
+	}
+
+	if err := CopyQuote("hi", "javascript:alert(1)"); err == nil {
+		t.Error("CopyQuote accepted a javascript: source, want error")
+	}
+	if err := CopyQuote("  ", ""); err == nil {
+		t.Error("CopyQuote accepted empty text, want error")
+	}
+	if err := CopyQuote("no source", ""); err != nil {
+		t.Errorf("CopyQuote without a source returned error: %v", err)
+	}
+}
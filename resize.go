@@ -0,0 +1,55 @@
+package clippy
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+
+	"golang.org/x/image/draw"
+)
+
+// scaleToFit shrinks img to fit within maxWidth x maxHeight (0 means no limit),
+// keeping its aspect ratio. Catmull-Rom resampling keeps text in screenshots
+// sharp. Images that already fit are returned unchanged; nothing is enlarged.
+func scaleToFit(img image.Image, maxWidth, maxHeight int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	scale := 1.0
+	if maxWidth > 0 && width > maxWidth {
+		scale = float64(maxWidth) / float64(width)
+	}
+	if maxHeight > 0 && height > maxHeight {
+		scale = min(scale, float64(maxHeight)/float64(height))
+	}
+	if scale == 1.0 {
+		return img
+	}
+
+	target := image.Rect(0, 0, max(1, int(float64(width)*scale+0.5)), max(1, int(float64(height)*scale+0.5)))
+	scaled := image.NewRGBA(target)
+	draw.CatmullRom.Scale(scaled, target, img, bounds, draw.Src, nil)
+	return scaled
+}
+
+// resizeImageData downscales encoded image data to at most maxWidth pixels wide
+// and re-encodes it in the format named by ext. Data that is already narrow
+// enough is returned as is, without decoding or re-encoding.
+func resizeImageData(data []byte, ext string, maxWidth int) ([]byte, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("could not read image to resize: %w", err)
+	}
+	if config.Width <= maxWidth {
+		return data, nil
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("could not decode image to resize: %w", err)
+	}
+	resized, err := encodeImage(scaleToFit(img, maxWidth, 0), ext)
+	if err != nil {
+		return nil, fmt.Errorf("could not resize %s image (PNG, JPEG and GIF are supported): %w", ext, err)
+	}
+	return resized, nil
+}
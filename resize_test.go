@@ -0,0 +1,63 @@
+package clippy
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"os"
+	"testing"
+)
+
+func TestScaleToFit(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 240, 160))
+	tests := []struct {
+		maxWidth, maxHeight int
+		want                image.Point
+	}{
+		{120, 0, image.Pt(120, 80)},
+		{0, 40, image.Pt(60, 40)},
+		{120, 40, image.Pt(60, 40)},
+		{300, 0, image.Pt(240, 160)},
+		{0, 0, image.Pt(240, 160)},
+	}
+	for _, tt := range tests {
+		if got := scaleToFit(img, tt.maxWidth, tt.maxHeight).Bounds().Size(); got != tt.want {
+			t.Errorf("scaleToFit(%d, %d) = %v, want %v", tt.maxWidth, tt.maxHeight, got, tt.want)
+		}
+	}
+}
+
+func TestPasteMaxWidth(t *testing.T) {
+	mem := useMemoryClipboard(t)
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 400, 300))); err != nil {
+		t.Fatal(err)
+	}
+	mem.SetData("public.png", buf.Bytes())
+	dir := t.TempDir()
+
+	for _, tt := range []struct {
+		maxWidth int
+		want     image.Point
+	}{
+		{100, image.Pt(100, 75)},
+		{1000, image.Pt(400, 300)},
+	} {
+		result, err := PasteToFileWithOptions(dir, PasteOptions{MaxWidth: tt.maxWidth})
+		if err != nil {
+			t.Fatalf("PasteToFileWithOptions returned error: %v", err)
+		}
+		f, err := os.Open(result.Files[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		config, _, err := image.DecodeConfig(f)
+		_ = f.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := image.Pt(config.Width, config.Height); got != tt.want {
+			t.Errorf("MaxWidth %d pasted a %v image, want %v", tt.maxWidth, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package clippy
+
+import "time"
+
+// RetryOptions controls how clipboard writes are retried. Another app holding the
+// pasteboard can make a single write fail, so each write gets a few attempts.
+type RetryOptions struct {
+	Attempts int           // Total attempts per write (values below 1 mean a single attempt)
+	Backoff  time.Duration // Wait before the first retry; doubles after each failed attempt
+}
+
+// DefaultRetryOptions makes a couple of quick retries before giving up
+var DefaultRetryOptions = RetryOptions{Attempts: 3, Backoff: 20 * time.Millisecond}
+
+var retryOptions = DefaultRetryOptions
+
+// SetRetryOptions changes how clipboard writes are retried and returns the previous
+// setting. Use RetryOptions{Attempts: 1} to disable retries.
+func SetRetryOptions(opts RetryOptions) RetryOptions {
+	previous := retryOptions
+	retryOptions = opts
+	return previous
+}
+
+// withRetry runs write until it succeeds or the attempts run out, returning the
+// last error
+func withRetry(write func() error) error {
+	attempts := retryOptions.Attempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	backoff := retryOptions.Backoff
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if err = write(); err == nil {
+			return nil
+		}
+		if attempt < attempts && backoff > 0 {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+	}
+	return err
+}
@@ -0,0 +1,61 @@
+package clippy
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/neilberkman/clippy/pkg/clipboard"
+)
+
+// flakyClipboard fails the first few text writes, like a pasteboard held by another app
+type flakyClipboard struct {
+	*clipboard.MemoryManager
+	failures int
+	calls    int
+}
+
+func (f *flakyClipboard) CopyText(text string) error {
+	f.calls++
+	if f.calls <= f.failures {
+		return errors.New("failed to write to clipboard")
+	}
+	return f.MemoryManager.CopyText(text)
+}
+
+func TestWriteRetries(t *testing.T) {
+	previousRetry := SetRetryOptions(RetryOptions{Attempts: 3, Backoff: time.Millisecond})
+	defer SetRetryOptions(previousRetry)
+
+	tests := []struct {
+		name      string
+		failures  int
+		wantErr   bool
+		wantCalls int
+	}{
+		{"succeeds first time", 0, false, 1},
+		{"recovers after transient failures", 2, false, 3},
+		{"gives up after all attempts", 5, true, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flaky := &flakyClipboard{MemoryManager: clipboard.NewMemoryManager(), failures: tt.failures}
+			previous := clipboard.SetManager(flaky)
+			defer clipboard.SetManager(previous)
+
+			err := CopyText("hello")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CopyText error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if flaky.calls != tt.wantCalls {
+				t.Errorf("CopyText made %d attempts, want %d", flaky.calls, tt.wantCalls)
+			}
+			if !tt.wantErr {
+				if text, ok := flaky.GetText(); !ok || text != "hello" {
+					t.Errorf("clipboard text = %q, want %q", text, "hello")
+				}
+			}
+		})
+	}
+}
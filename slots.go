@@ -0,0 +1,174 @@
+package clippy
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/neilberkman/clippy/internal/fsutil"
+)
+
+// ErrSlotNotFound is returned when restoring or swapping with a slot that was never saved
+var ErrSlotNotFound = errors.New("no such slot")
+
+// slotsDir returns where named slots are kept; tests point it elsewhere
+var slotsDir = func() string {
+	dir := stateDir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, "slots")
+}
+
+// Slot is a named clipboard saved with SaveSlot
+type Slot struct {
+	Name     string
+	Snapshot *Snapshot
+}
+
+// SaveSlot saves the current clipboard, with all its representations, under
+// name, replacing anything saved there before. The clipboard is unchanged.
+func SaveSlot(name string) (*Snapshot, error) {
+	path, err := slotPath(name)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	snapshot, err := ExportClipboard(&buf)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeSlot(path, buf.Bytes()); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// RestoreSlot puts the clipboard saved under name back. The slot is kept.
+func RestoreSlot(name string) (*Snapshot, error) {
+	path, err := slotPath(name)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", ErrSlotNotFound, name)
+		}
+		return nil, fmt.Errorf("could not read slot %s: %w", name, err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	return ImportClipboard(f)
+}
+
+// SwapSlot exchanges the clipboard with the slot: the slot's content goes on
+// the clipboard and the previous clipboard is saved under name. An empty
+// clipboard leaves the slot empty (removed) afterwards. Returns what was restored.
+func SwapSlot(name string) (*Snapshot, error) {
+	path, err := slotPath(name)
+	if err != nil {
+		return nil, err
+	}
+	saved, err := readSnapshotFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", ErrSlotNotFound, name)
+		}
+		return nil, fmt.Errorf("could not read slot %s: %w", name, err)
+	}
+
+	// Take the current clipboard before overwriting it; an empty one is allowed
+	var current bytes.Buffer
+	_, exportErr := ExportClipboard(&current)
+
+	if err := RestoreSnapshot(saved); err != nil {
+		return nil, err
+	}
+	if exportErr != nil {
+		if !skipForDryRun("remove slot %s", name) {
+			_ = os.Remove(path)
+		}
+		return saved, nil
+	}
+	if err := writeSlot(path, current.Bytes()); err != nil {
+		return saved, fmt.Errorf("restored slot %s but could not save the previous clipboard there: %w", name, err)
+	}
+	return saved, nil
+}
+
+// DeleteSlot removes a saved slot
+func DeleteSlot(name string) error {
+	path, err := slotPath(name)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return fmt.Errorf("%w: %s", ErrSlotNotFound, name)
+	}
+	if skipForDryRun("remove slot %s", name) {
+		return nil
+	}
+	return os.Remove(path)
+}
+
+// Slots returns the saved slots sorted by name. Unreadable slots are skipped.
+func Slots() ([]Slot, error) {
+	dir := slotsDir()
+	if dir == "" {
+		return nil, nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("could not read slots: %w", err)
+	}
+	var slots []Slot
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".clip")
+		if entry.IsDir() || !ok {
+			continue
+		}
+		snapshot, err := readSnapshotFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		slots = append(slots, Slot{Name: name, Snapshot: snapshot})
+	}
+	sort.Slice(slots, func(i, j int) bool { return slots[i].Name < slots[j].Name })
+	return slots, nil
+}
+
+// slotPath returns the file for a slot name. Names become file names, so they
+// can't be empty, contain a path separator or start with a dot.
+func slotPath(name string) (string, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return "", fmt.Errorf("invalid slot name %q (use letters, digits, - or _)", name)
+	}
+	dir := slotsDir()
+	if dir == "" {
+		return "", fmt.Errorf("could not find a state directory for slots")
+	}
+	return filepath.Join(dir, name+".clip"), nil
+}
+
+// writeSlot stores snapshot JSON at path
+func writeSlot(path string, data []byte) error {
+	if skipForDryRun("save the clipboard to %s", path) {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("could not create slots directory: %w", err)
+	}
+	if err := fsutil.WriteFileAtomic(path, data, 0600); err != nil {
+		return fmt.Errorf("could not save slot: %w", err)
+	}
+	return nil
+}
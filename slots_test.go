@@ -0,0 +1,73 @@
+package clippy
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/neilberkman/clippy/pkg/clipboard"
+)
+
+func TestSlots(t *testing.T) {
+	mem := useMemoryClipboard(t)
+	rich := []clipboard.Flavor{
+		{Type: "public.html", Data: []byte("<b>signature</b>")},
+		{Type: clipboard.PlainTextType, Data: []byte("signature")},
+	}
+	if err := mem.CopyFlavors(rich); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SaveSlot("sig"); err != nil {
+		t.Fatalf("SaveSlot returned error: %v", err)
+	}
+
+	if err := mem.CopyText("scratch"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := RestoreSlot("sig"); err != nil {
+		t.Fatalf("RestoreSlot returned error: %v", err)
+	}
+	if html, _ := mem.GetClipboardDataForType("public.html"); !bytes.Equal(html, rich[0].Data) {
+		t.Errorf("HTML after restore = %q, want %q", html, rich[0].Data)
+	}
+
+	// Swap puts the slot on the clipboard and the clipboard in the slot
+	if err := mem.CopyText("scratch"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SwapSlot("sig"); err != nil {
+		t.Fatalf("SwapSlot returned error: %v", err)
+	}
+	if text, _ := mem.GetText(); text != "signature" {
+		t.Errorf("clipboard after swap = %q, want %q", text, "signature")
+	}
+	if _, err := SwapSlot("sig"); err != nil {
+		t.Fatalf("second SwapSlot returned error: %v", err)
+	}
+	if text, _ := mem.GetText(); text != "scratch" {
+		t.Errorf("clipboard after swapping back = %q, want %q", text, "scratch")
+	}
+
+	slots, err := Slots()
+	if err != nil || len(slots) != 1 || slots[0].Name != "sig" {
+		t.Errorf("Slots() = %+v, %v; want just sig", slots, err)
+	}
+	if err := DeleteSlot("sig"); err != nil {
+		t.Errorf("DeleteSlot returned error: %v", err)
+	}
+	if _, err := RestoreSlot("sig"); !errors.Is(err, ErrSlotNotFound) {
+		t.Errorf("RestoreSlot after delete = %v, want ErrSlotNotFound", err)
+	}
+}
+
+func TestSlotNames(t *testing.T) {
+	useMemoryClipboard(t)
+	for _, name := range []string{"", "../escape", "a/b", ".hidden"} {
+		if _, err := slotPath(name); err == nil {
+			t.Errorf("slotPath(%q) succeeded, want error", name)
+		}
+	}
+	if _, err := slotPath("work-2"); err != nil {
+		t.Errorf("slotPath(%q) returned error: %v", "work-2", err)
+	}
+}
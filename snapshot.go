@@ -0,0 +1,242 @@
+package clippy
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/neilberkman/clippy/pkg/clipboard"
+)
+
+// Snapshot file format identifiers. The version is bumped whenever a change would
+// make older clippy versions restore a snapshot incorrectly.
+const (
+	SnapshotFormat  = "clippy-clipboard"
+	SnapshotVersion = 2
+)
+
+// Transforms a deduplicated flavor can apply to the flavor it refers to
+const (
+	// TransformUTF16LE re-encodes UTF-8 text as UTF-16 little endian without a
+	// BOM, which is how public.utf16-plain-text holds the same text
+	TransformUTF16LE = "utf-16le"
+)
+
+// Snapshot is a portable copy of the clipboard: every representation of the
+// current item, stored as JSON with the bytes base64-encoded.
+//
+//	{
+//	  "format": "clippy-clipboard",
+//	  "version": 2,
+//	  "created": "2025-01-02T15:04:05Z",
+//	  "files": ["/Users/me/report.pdf"],
+//	  "flavors": [
+//	    {"type": "public.utf8-plain-text", "data": "SGk="},
+//	    {"type": "NSStringPboardType", "same_as": "public.utf8-plain-text"},
+//	    {"type": "public.utf16-plain-text", "same_as": "public.utf8-plain-text", "transform": "utf-16le"}
+//	  ]
+//	}
+//
+// Apps often put the same text on the clipboard under several types, so when
+// exporting, a flavor whose bytes equal an earlier flavor's (or are its UTF-16
+// encoding) is stored as a reference to it: same_as names the earlier flavor's
+// type, which always has data, and transform says how to derive the bytes.
+// Importing expands the references back into full flavors. Version 1 snapshots
+// never contain references.
+//
+// Files lists the file references on the clipboard. They only mean something on
+// the machine they came from, so RestoreSnapshot uses them when they all exist
+// there and falls back to the flavors otherwise.
+type Snapshot struct {
+	Format  string           `json:"format"`
+	Version int              `json:"version"`
+	Created time.Time        `json:"created"`
+	Files   []string         `json:"files,omitempty"`
+	Flavors []SnapshotFlavor `json:"flavors"`
+}
+
+// SnapshotFlavor is one representation in a Snapshot
+type SnapshotFlavor struct {
+	Type      string `json:"type"`                // UTI, e.g. "public.utf8-plain-text"
+	Data      []byte `json:"data,omitempty"`      // base64 in JSON
+	SameAs    string `json:"same_as,omitempty"`   // type of an earlier flavor holding the data
+	Transform string `json:"transform,omitempty"` // how to derive the data from SameAs
+}
+
+// Age returns how long ago the snapshot was taken
+func (s *Snapshot) Age() time.Duration {
+	return time.Since(s.Created)
+}
+
+// TakeSnapshot reads every representation of the current clipboard item
+func TakeSnapshot() (*Snapshot, error) {
+	snapshot := &Snapshot{
+		Format:  SnapshotFormat,
+		Version: SnapshotVersion,
+		Created: time.Now().UTC(),
+		Files:   clipboard.GetFiles(),
+	}
+	for _, t := range clipboard.GetClipboardTypes() {
+		if data, ok := clipboard.GetClipboardDataForType(t); ok {
+			snapshot.Flavors = append(snapshot.Flavors, SnapshotFlavor{Type: t, Data: data})
+		}
+	}
+	if len(snapshot.Flavors) == 0 && len(snapshot.Files) == 0 {
+		return nil, fmt.Errorf("clipboard is empty")
+	}
+	return snapshot, nil
+}
+
+// RestoreSnapshot puts a snapshot back on the clipboard: its file references if
+// every file exists on this machine, otherwise all its flavors in one write
+func RestoreSnapshot(snapshot *Snapshot) error {
+	if snapshot.Format != SnapshotFormat {
+		return fmt.Errorf("not a clippy clipboard snapshot (format %q)", snapshot.Format)
+	}
+	if snapshot.Version > SnapshotVersion {
+		return fmt.Errorf("snapshot version %d is newer than this clippy supports (%d); upgrade clippy", snapshot.Version, SnapshotVersion)
+	}
+	if err := snapshot.expand(); err != nil {
+		return err
+	}
+
+	if len(snapshot.Files) > 0 && allExist(snapshot.Files) {
+		if err := writeClipboardFiles(snapshot.Files); err != nil {
+			return fmt.Errorf("could not copy files to clipboard: %w", err)
+		}
+		return nil
+	}
+	if len(snapshot.Flavors) == 0 {
+		return fmt.Errorf("snapshot has no clipboard content to restore here")
+	}
+
+	flavors := make([]clipboard.Flavor, len(snapshot.Flavors))
+	for i, flavor := range snapshot.Flavors {
+		flavors[i] = clipboard.Flavor{Type: flavor.Type, Data: flavor.Data}
+	}
+	if err := writeClipboardFlavors(flavors); err != nil {
+		return fmt.Errorf("could not copy to clipboard: %w", err)
+	}
+	return nil
+}
+
+// ExportClipboard writes a snapshot of the clipboard to w as indented JSON, with
+// duplicate flavors stored as references, and returns it with every flavor's data
+func ExportClipboard(w io.Writer) (*Snapshot, error) {
+	snapshot, err := TakeSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(snapshot.deduplicated()); err != nil {
+		return nil, fmt.Errorf("could not write snapshot: %w", err)
+	}
+	return snapshot, nil
+}
+
+// ImportClipboard reads a snapshot written by ExportClipboard from r, restores
+// it and returns it
+func ImportClipboard(r io.Reader) (*Snapshot, error) {
+	snapshot, err := decodeSnapshot(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := RestoreSnapshot(snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// decodeSnapshot reads a snapshot's JSON without checking or expanding it
+func decodeSnapshot(r io.Reader) (*Snapshot, error) {
+	var snapshot Snapshot
+	if err := json.NewDecoder(r).Decode(&snapshot); err != nil {
+		return nil, fmt.Errorf("could not read snapshot: %w", err)
+	}
+	return &snapshot, nil
+}
+
+// deduplicated returns a copy of the snapshot in which each flavor that repeats
+// an earlier flavor's bytes, or is their UTF-16 encoding, refers to it instead of
+// holding the data
+func (s *Snapshot) deduplicated() *Snapshot {
+	out := *s
+	out.Flavors = make([]SnapshotFlavor, len(s.Flavors))
+	var stored []SnapshotFlavor // flavors written with their data, in order
+	for i, flavor := range s.Flavors {
+		out.Flavors[i] = flavor
+		if len(flavor.Data) == 0 {
+			continue
+		}
+		for _, earlier := range stored {
+			if bytes.Equal(flavor.Data, earlier.Data) {
+				out.Flavors[i] = SnapshotFlavor{Type: flavor.Type, SameAs: earlier.Type}
+				break
+			}
+			if utf8.Valid(earlier.Data) && bytes.Equal(flavor.Data, encodeUTF16LE(earlier.Data)) {
+				out.Flavors[i] = SnapshotFlavor{Type: flavor.Type, SameAs: earlier.Type, Transform: TransformUTF16LE}
+				break
+			}
+		}
+		if out.Flavors[i].SameAs == "" {
+			stored = append(stored, flavor)
+		}
+	}
+	return &out
+}
+
+// expand replaces references left by deduplicated with the data they stand for
+func (s *Snapshot) expand() error {
+	for i, flavor := range s.Flavors {
+		if flavor.SameAs == "" {
+			continue
+		}
+		var source *SnapshotFlavor
+		for j := 0; j < i; j++ {
+			if s.Flavors[j].Type == flavor.SameAs {
+				source = &s.Flavors[j]
+				break
+			}
+		}
+		if source == nil {
+			return fmt.Errorf("snapshot flavor %s refers to %s, which doesn't come before it", flavor.Type, flavor.SameAs)
+		}
+		switch flavor.Transform {
+		case "":
+			s.Flavors[i].Data = source.Data
+		case TransformUTF16LE:
+			s.Flavors[i].Data = encodeUTF16LE(source.Data)
+		default:
+			return fmt.Errorf("snapshot flavor %s uses unknown transform %q", flavor.Type, flavor.Transform)
+		}
+		s.Flavors[i].SameAs, s.Flavors[i].Transform = "", ""
+	}
+	return nil
+}
+
+// encodeUTF16LE encodes UTF-8 text as UTF-16 little endian without a BOM
+func encodeUTF16LE(text []byte) []byte {
+	units := utf16.Encode([]rune(string(text)))
+	out := make([]byte, 2*len(units))
+	for i, u := range units {
+		binary.LittleEndian.PutUint16(out[2*i:], u)
+	}
+	return out
+}
+
+// allExist reports whether every path exists
+func allExist(paths []string) bool {
+	for _, path := range paths {
+		if _, err := os.Stat(path); err != nil {
+			return false
+		}
+	}
+	return true
+}
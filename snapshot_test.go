@@ -0,0 +1,121 @@
+package clippy
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/neilberkman/clippy/pkg/clipboard"
+)
+
+func TestExportImportClipboard(t *testing.T) {
+	mem := useMemoryClipboard(t)
+	flavors := []clipboard.Flavor{
+		{Type: "public.html", Data: []byte("<b>Hi</b>")},
+		{Type: "public.png", Data: []byte{0x89, 'P', 'N', 'G', 0, 0xFF}},
+		{Type: clipboard.PlainTextType, Data: []byte("Hi")},
+	}
+	if err := mem.CopyFlavors(flavors); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	snapshot, err := ExportClipboard(&buf)
+	if err != nil {
+		t.Fatalf("ExportClipboard returned error: %v", err)
+	}
+	if len(snapshot.Flavors) != len(flavors) {
+		t.Errorf("exported %d flavors, want %d", len(snapshot.Flavors), len(flavors))
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &raw); err != nil || raw["format"] != SnapshotFormat {
+		t.Errorf("export isn't a %s JSON document: %v", SnapshotFormat, err)
+	}
+
+	if err := mem.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ImportClipboard(bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatalf("ImportClipboard returned error: %v", err)
+	}
+	for _, flavor := range flavors {
+		if got, ok := mem.GetClipboardDataForType(flavor.Type); !ok || !bytes.Equal(got, flavor.Data) {
+			t.Errorf("%s after import = %q, want %q", flavor.Type, got, flavor.Data)
+		}
+	}
+}
+
+func TestImportClipboardRejectsUnknownFormats(t *testing.T) {
+	useMemoryClipboard(t)
+	for _, doc := range []string{
+		`{"format": "something-else", "version": 1, "flavors": []}`,
+		`{"format": "clippy-clipboard", "version": 99, "flavors": []}`,
+		`{"format": "clippy-clipboard", "version": 2, "flavors": [{"type": "a", "same_as": "b"}]}`,
+		`{"format": "clippy-clipboard", "version": 2, "flavors": [{"type": "a", "data": "SGk="}, {"type": "b", "same_as": "a", "transform": "rot13"}]}`,
+		`not json`,
+	} {
+		if _, err := ImportClipboard(strings.NewReader(doc)); err == nil {
+			t.Errorf("ImportClipboard(%s) succeeded, want error", doc)
+		}
+	}
+}
+
+func TestExportDeduplicatesFlavors(t *testing.T) {
+	mem := useMemoryClipboard(t)
+	text := strings.Repeat("Grüße, clipboard. ", 100)
+	flavors := []clipboard.Flavor{
+		{Type: clipboard.PlainTextType, Data: []byte(text)},
+		{Type: "NSStringPboardType", Data: []byte(text)},
+		{Type: "public.utf16-plain-text", Data: encodeUTF16LE([]byte(text))},
+		{Type: "public.html", Data: []byte("<p>" + text + "</p>")},
+	}
+	if err := mem.CopyFlavors(flavors); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if _, err := ExportClipboard(&buf); err != nil {
+		t.Fatalf("ExportClipboard returned error: %v", err)
+	}
+	var exported Snapshot
+	if err := json.Unmarshal(buf.Bytes(), &exported); err != nil {
+		t.Fatal(err)
+	}
+	want := []SnapshotFlavor{
+		{Type: clipboard.PlainTextType},
+		{Type: "NSStringPboardType", SameAs: clipboard.PlainTextType},
+		{Type: "public.utf16-plain-text", SameAs: clipboard.PlainTextType, Transform: TransformUTF16LE},
+		{Type: "public.html"},
+	}
+	for i, w := range want {
+		got := exported.Flavors[i]
+		if got.Type != w.Type || got.SameAs != w.SameAs || got.Transform != w.Transform || (w.SameAs == "") != (len(got.Data) > 0) {
+			t.Errorf("flavor %d = {%s same_as=%q transform=%q %d bytes}, want {%s same_as=%q transform=%q}",
+				i, got.Type, got.SameAs, got.Transform, len(got.Data), w.Type, w.SameAs, w.Transform)
+		}
+	}
+
+	if err := mem.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ImportClipboard(bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatalf("ImportClipboard returned error: %v", err)
+	}
+	for _, flavor := range flavors {
+		if got, _ := mem.GetClipboardDataForType(flavor.Type); !bytes.Equal(got, flavor.Data) {
+			t.Errorf("%s after import has %d bytes, want %d", flavor.Type, len(got), len(flavor.Data))
+		}
+	}
+}
+
+func TestImportClipboardVersion1(t *testing.T) {
+	mem := useMemoryClipboard(t)
+	doc := `{"format": "clippy-clipboard", "version": 1, "flavors": [{"type": "public.utf8-plain-text", "data": "SGk="}]}`
+	if _, err := ImportClipboard(strings.NewReader(doc)); err != nil {
+		t.Fatalf("ImportClipboard returned error: %v", err)
+	}
+	if got, _ := mem.GetClipboardDataForType(clipboard.PlainTextType); string(got) != "Hi" {
+		t.Errorf("text after import = %q, want %q", got, "Hi")
+	}
+}
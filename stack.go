@@ -0,0 +1,153 @@
+package clippy
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/neilberkman/clippy/internal/fsutil"
+)
+
+// ErrStackEmpty is returned by PopClipboard when nothing has been pushed
+var ErrStackEmpty = errors.New("the clipboard stack is empty")
+
+// MaxStackSize is how many clipboards the stack keeps; pushing more drops the oldest
+const MaxStackSize = 20
+
+// stackDir returns where pushed clipboards are kept; tests point it elsewhere
+var stackDir = func() string {
+	dir := stateDir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, "stack")
+}
+
+// StackEntry is one clipboard saved on the stack
+type StackEntry struct {
+	Path     string // Snapshot file holding the entry
+	Snapshot *Snapshot
+}
+
+// PushClipboard saves the current clipboard on top of the stack, in the
+// ExportClipboard format, and returns it. The clipboard itself is unchanged.
+func PushClipboard() (*Snapshot, error) {
+	dir := stackDir()
+	if dir == "" {
+		return nil, fmt.Errorf("could not find a state directory for the clipboard stack")
+	}
+	var buf bytes.Buffer
+	snapshot, err := ExportClipboard(&buf)
+	if err != nil {
+		return nil, err
+	}
+	// Names sort in push order, so the top of the stack is the last file
+	path := filepath.Join(dir, snapshot.Created.Format("20060102T150405.000000000")+".clip")
+	if skipForDryRun("push the clipboard to %s", path) {
+		return snapshot, nil
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("could not create clipboard stack: %w", err)
+	}
+	if err := fsutil.WriteFileAtomic(path, buf.Bytes(), 0600); err != nil {
+		return nil, fmt.Errorf("could not save clipboard to the stack: %w", err)
+	}
+
+	paths, err := stackFiles()
+	if err != nil {
+		return nil, err
+	}
+	for len(paths) > MaxStackSize {
+		_ = os.Remove(paths[0])
+		paths = paths[1:]
+	}
+	return snapshot, nil
+}
+
+// PopClipboard restores the clipboard on top of the stack and removes it from
+// the stack. The entry is kept if restoring fails.
+func PopClipboard() (*Snapshot, error) {
+	paths, err := stackFiles()
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, ErrStackEmpty
+	}
+	top := paths[len(paths)-1]
+	f, err := os.Open(top)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the clipboard stack: %w", err)
+	}
+	snapshot, err := ImportClipboard(f)
+	_ = f.Close()
+	if err != nil {
+		return nil, err
+	}
+	if !skipForDryRun("remove %s from the clipboard stack", top) {
+		if err := os.Remove(top); err != nil {
+			return snapshot, fmt.Errorf("restored the clipboard but could not remove it from the stack: %w", err)
+		}
+	}
+	return snapshot, nil
+}
+
+// ClipboardStack returns the stack, top first. Unreadable entries are skipped.
+func ClipboardStack() ([]StackEntry, error) {
+	paths, err := stackFiles()
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]StackEntry, 0, len(paths))
+	for i := len(paths) - 1; i >= 0; i-- {
+		snapshot, err := readSnapshotFile(paths[i])
+		if err != nil {
+			continue
+		}
+		entries = append(entries, StackEntry{Path: paths[i], Snapshot: snapshot})
+	}
+	return entries, nil
+}
+
+// stackFiles returns the stack's snapshot files, oldest first
+func stackFiles() ([]string, error) {
+	dir := stackDir()
+	if dir == "" {
+		return nil, nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("could not read the clipboard stack: %w", err)
+	}
+	var paths []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".clip") {
+			paths = append(paths, filepath.Join(dir, entry.Name()))
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// readSnapshotFile decodes a snapshot file without restoring it
+func readSnapshotFile(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	snapshot, err := decodeSnapshot(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if err := snapshot.expand(); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
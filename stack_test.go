@@ -0,0 +1,96 @@
+package clippy
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestClipboardStack(t *testing.T) {
+	mem := useMemoryClipboard(t)
+
+	if _, err := PopClipboard(); !errors.Is(err, ErrStackEmpty) {
+		t.Errorf("PopClipboard on an empty stack = %v, want ErrStackEmpty", err)
+	}
+
+	for _, text := range []string{"first", "second"} {
+		if err := mem.CopyText(text); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := PushClipboard(); err != nil {
+			t.Fatalf("PushClipboard returned error: %v", err)
+		}
+	}
+	if text, _ := mem.GetText(); text != "second" {
+		t.Errorf("PushClipboard changed the clipboard to %q", text)
+	}
+
+	entries, err := ClipboardStack()
+	if err != nil {
+		t.Fatalf("ClipboardStack returned error: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("ClipboardStack has %d entries, want 2", len(entries))
+	}
+	if text := string(entries[0].Snapshot.Flavors[0].Data); text != "second" {
+		t.Errorf("top of stack = %q, want %q", text, "second")
+	}
+
+	if err := mem.CopyText("something else"); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"second", "first"} {
+		if _, err := PopClipboard(); err != nil {
+			t.Fatalf("PopClipboard returned error: %v", err)
+		}
+		if text, _ := mem.GetText(); text != want {
+			t.Errorf("clipboard after pop = %q, want %q", text, want)
+		}
+	}
+	if entries, _ := ClipboardStack(); len(entries) != 0 {
+		t.Errorf("stack has %d entries after popping everything", len(entries))
+	}
+}
+
+func TestPushClipboardDropsOldest(t *testing.T) {
+	mem := useMemoryClipboard(t)
+	for i := 0; i < MaxStackSize+2; i++ {
+		if err := mem.CopyText(string(rune('a' + i))); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := PushClipboard(); err != nil {
+			t.Fatalf("PushClipboard returned error: %v", err)
+		}
+	}
+	entries, err := ClipboardStack()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != MaxStackSize {
+		t.Fatalf("stack has %d entries, want %d", len(entries), MaxStackSize)
+	}
+	if bottom := string(entries[len(entries)-1].Snapshot.Flavors[0].Data); bottom != "c" {
+		t.Errorf("bottom of stack = %q, want %q (the two oldest dropped)", bottom, "c")
+	}
+}
+
+func TestStateDir(t *testing.T) {
+	state := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", state)
+	if got, want := stateDir(), filepath.Join(state, "clippy"); got != want {
+		t.Errorf("stateDir() = %s, want %s", got, want)
+	}
+
+	// A relative XDG_STATE_HOME is invalid per the spec and ignored
+	t.Setenv("XDG_STATE_HOME", "relative")
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	if got := stateDir(); !strings.HasPrefix(got, home) || filepath.Base(got) != "clippy" {
+		t.Errorf("stateDir() = %s, want a clippy directory under %s", got, home)
+	}
+	if cache, err := os.UserCacheDir(); err == nil && strings.HasPrefix(stateDir(), cache) {
+		t.Errorf("stateDir() = %s is inside the purgeable cache directory %s", stateDir(), cache)
+	}
+}
@@ -0,0 +1,26 @@
+package clippy
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// stateDir returns the directory for data clippy keeps until the user deletes
+// it, such as the clipboard stack and named slots. Unlike the cache directory,
+// macOS and cleaner tools don't purge it. $XDG_STATE_HOME wins when set;
+// otherwise it is ~/Library/Application Support/clippy on macOS and
+// ~/.local/state/clippy elsewhere. Returns "" if there is no home directory.
+func stateDir() string {
+	if dir := os.Getenv("XDG_STATE_HOME"); filepath.IsAbs(dir) {
+		return filepath.Join(dir, "clippy")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	if runtime.GOOS == "darwin" {
+		return filepath.Join(home, "Library", "Application Support", "clippy")
+	}
+	return filepath.Join(home, ".local", "state", "clippy")
+}
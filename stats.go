@@ -0,0 +1,24 @@
+package clippy
+
+import "strings"
+
+// TextStats holds the line, word and byte counts of copied text
+type TextStats struct {
+	Lines int
+	Words int
+	Bytes int
+}
+
+// CountText returns the line, word and byte counts of text, like wc. A final
+// line without a trailing newline still counts as a line.
+func CountText(text string) TextStats {
+	lines := strings.Count(text, "\n")
+	if text != "" && !strings.HasSuffix(text, "\n") {
+		lines++
+	}
+	return TextStats{
+		Lines: lines,
+		Words: len(strings.Fields(text)),
+		Bytes: len(text),
+	}
+}
@@ -0,0 +1,22 @@
+package clippy
+
+import "testing"
+
+func TestCountText(t *testing.T) {
+	tests := []struct {
+		text string
+		want TextStats
+	}{
+		{"", TextStats{0, 0, 0}},
+		{"hello", TextStats{1, 1, 5}},
+		{"hello world\n", TextStats{1, 2, 12}},
+		{"one\ntwo three\n\nfour", TextStats{4, 4, 19}},
+		{"  spaced   out  ", TextStats{1, 2, 16}},
+		{"naïve café\n", TextStats{1, 2, 13}},
+	}
+	for _, tt := range tests {
+		if got := CountText(tt.text); got != tt.want {
+			t.Errorf("CountText(%q) = %+v, want %+v", tt.text, got, tt.want)
+		}
+	}
+}
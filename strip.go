@@ -0,0 +1,94 @@
+package clippy
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+// pngMetadataChunks are the PNG chunks that can carry camera, location or
+// editing details: EXIF, text comments and the modification time
+var pngMetadataChunks = map[string]bool{
+	"eXIf": true,
+	"tEXt": true,
+	"zTXt": true,
+	"iTXt": true,
+	"tIME": true,
+}
+
+// stripImageMetadata removes EXIF and similar metadata from encoded image data
+// without re-encoding the pixels. ext names the format: PNG and JPEG are
+// stripped, GIF has no EXIF and is returned unchanged, and anything else is an
+// error so unstripped data is never written by mistake.
+func stripImageMetadata(data []byte, ext string) ([]byte, error) {
+	switch strings.ToLower(ext) {
+	case ".png":
+		return stripPNGMetadata(data)
+	case ".jpg", ".jpeg":
+		return stripJPEGMetadata(data)
+	case ".gif":
+		return data, nil
+	default:
+		return nil, fmt.Errorf("can't strip metadata from %s images (PNG, JPEG and GIF are supported)", strings.TrimPrefix(ext, "."))
+	}
+}
+
+// stripPNGMetadata drops the chunks in pngMetadataChunks, keeping everything
+// else (including the color profile) byte for byte
+func stripPNGMetadata(data []byte) ([]byte, error) {
+	signature := []byte("\x89PNG\r\n\x1a\n")
+	if !bytes.HasPrefix(data, signature) {
+		return nil, fmt.Errorf("not a PNG image")
+	}
+
+	out := bytes.NewBuffer(make([]byte, 0, len(data)))
+	out.Write(signature)
+	for rest := data[len(signature):]; len(rest) > 0; {
+		// Each chunk is length, type, data and CRC
+		if len(rest) < 12 {
+			return nil, fmt.Errorf("truncated PNG chunk")
+		}
+		size := int(binary.BigEndian.Uint32(rest[:4]))
+		if size > len(rest)-12 {
+			return nil, fmt.Errorf("truncated PNG chunk")
+		}
+		chunk := rest[:12+size]
+		if !pngMetadataChunks[string(chunk[4:8])] {
+			out.Write(chunk)
+		}
+		rest = rest[12+size:]
+	}
+	return out.Bytes(), nil
+}
+
+// stripJPEGMetadata drops APP1 (EXIF, XMP), APP13 (IPTC) and comment segments
+// from the header. The compressed image data after the start of scan is copied
+// unchanged, so quality isn't affected.
+func stripJPEGMetadata(data []byte) ([]byte, error) {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return nil, fmt.Errorf("not a JPEG image")
+	}
+
+	out := bytes.NewBuffer(make([]byte, 0, len(data)))
+	out.Write(data[:2])
+	for i := 2; ; {
+		if i+4 > len(data) || data[i] != 0xFF {
+			return nil, fmt.Errorf("malformed JPEG header")
+		}
+		marker := data[i+1]
+		size := int(binary.BigEndian.Uint16(data[i+2 : i+4]))
+		if size < 2 || i+2+size > len(data) {
+			return nil, fmt.Errorf("malformed JPEG header")
+		}
+		// Start of scan: the rest is image data
+		if marker == 0xDA {
+			out.Write(data[i:])
+			return out.Bytes(), nil
+		}
+		if marker != 0xE1 && marker != 0xED && marker != 0xFE {
+			out.Write(data[i : i+2+size])
+		}
+		i += 2 + size
+	}
+}
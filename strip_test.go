@@ -0,0 +1,104 @@
+package clippy
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"testing"
+)
+
+// pngWithText returns a PNG carrying a tEXt chunk after the header
+func pngWithText(t *testing.T, text string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 2, 2))); err != nil {
+		t.Fatal(err)
+	}
+	encoded := buf.Bytes()
+
+	body := append([]byte("tEXt"), text...)
+	chunk := make([]byte, 4, 12+len(text))
+	binary.BigEndian.PutUint32(chunk, uint32(len(text)))
+	chunk = append(chunk, body...)
+	chunk = binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(body))
+
+	// Signature (8) plus the IHDR chunk (25)
+	headerEnd := 8 + 25
+	return append(append(append([]byte{}, encoded[:headerEnd]...), chunk...), encoded[headerEnd:]...)
+}
+
+// jpegWithExif returns a JPEG carrying an APP1 segment right after SOI
+func jpegWithExif(t *testing.T, payload string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 2, 2)), nil); err != nil {
+		t.Fatal(err)
+	}
+	encoded := buf.Bytes()
+
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(2+len(payload)))
+	segment = append(segment, payload...)
+	return append(append(append([]byte{}, encoded[:2]...), segment...), encoded[2:]...)
+}
+
+func TestStripImageMetadata(t *testing.T) {
+	const secret = "GPSLatitude 37.7749"
+
+	for _, tt := range []struct {
+		ext  string
+		data []byte
+	}{
+		{".png", pngWithText(t, "Comment\x00"+secret)},
+		{".jpg", jpegWithExif(t, "Exif\x00\x00"+secret)},
+	} {
+		stripped, err := stripImageMetadata(tt.data, tt.ext)
+		if err != nil {
+			t.Fatalf("stripImageMetadata(%s) returned error: %v", tt.ext, err)
+		}
+		if bytes.Contains(stripped, []byte(secret)) {
+			t.Errorf("%s still contains the metadata", tt.ext)
+		}
+		if _, _, err := image.Decode(bytes.NewReader(stripped)); err != nil {
+			t.Errorf("stripped %s doesn't decode: %v", tt.ext, err)
+		}
+	}
+
+	if _, err := stripImageMetadata([]byte("data"), ".heic"); err == nil {
+		t.Error("stripping HEIC succeeded, want an unsupported format error")
+	}
+	if _, err := stripImageMetadata([]byte("not a png"), ".png"); err == nil {
+		t.Error("stripping invalid PNG data succeeded, want error")
+	}
+}
+
+func TestPasteStripMetadata(t *testing.T) {
+	mem := useMemoryClipboard(t)
+	mem.SetData("public.png", pngWithText(t, "Comment\x00secret location"))
+	dir := t.TempDir()
+
+	result, err := PasteToFileWithOptions(dir, PasteOptions{StripMetadata: true})
+	if err != nil {
+		t.Fatalf("PasteToFileWithOptions returned error: %v", err)
+	}
+	data, err := os.ReadFile(result.Files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("secret location")) {
+		t.Error("pasted image still contains the metadata")
+	}
+
+	// Metadata is kept by default
+	result, err = PasteToFileWithOptions(dir, PasteOptions{})
+	if err != nil {
+		t.Fatalf("PasteToFileWithOptions returned error: %v", err)
+	}
+	if data, _ := os.ReadFile(result.Files[0]); !bytes.Contains(data, []byte("secret location")) {
+		t.Error("metadata was removed without StripMetadata")
+	}
+}
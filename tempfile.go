@@ -0,0 +1,112 @@
+package clippy
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/neilberkman/clippy/pkg/recent"
+)
+
+// tempFilePattern names every file and directory clippy leaves in the temp dir,
+// so CleanupTempFiles can find them again
+const tempFilePattern = "clippy-*"
+
+// namedDirPrefix starts the name of the directories that hold named temp files.
+// Only directories with this prefix are removed recursively by CleanupTempFiles;
+// any other clippy-* directory may belong to the user and is left alone.
+const namedDirPrefix = "clippy-named-"
+
+// createTempFile creates the file that receives piped or clipboard data. Without a
+// name it is clippy-*<ext> in tempDir. A named file keeps its name inside a fresh
+// clippy-named-* directory, so the name shows up when it's pasted or attached and cleanup
+// still finds it. ext is added to names that have no extension of their own.
+func createTempFile(tempDir string, name string, ext string) (*os.File, error) {
+	if name == "" {
+		return os.CreateTemp(tempDir, tempFilePattern+ext)
+	}
+
+	dir, err := os.MkdirTemp(tempDir, namedDirPrefix+"*")
+	if err != nil {
+		return nil, err
+	}
+	return os.OpenFile(filepath.Join(dir, tempFileName(name, ext)), os.O_RDWR|os.O_CREATE|os.O_EXCL, 0600)
+}
+
+// createTempAlias makes the file or folder at src available as name inside a fresh
+// clippy-named-* directory and returns the new path. It is a copy-on-write clone
+// where the volume supports one and a plain copy otherwise, never a link, so an
+// app that edits the alias in place can't change the original. Names without an
+// extension get src's.
+func createTempAlias(tempDir string, src string, name string) (string, error) {
+	dir, err := os.MkdirTemp(tempDir, namedDirPrefix+"*")
+	if err != nil {
+		return "", err
+	}
+	alias := filepath.Join(dir, tempFileName(name, filepath.Ext(src)))
+	if err := cloneFile(src, alias); err == nil {
+		return alias, nil
+	}
+	_ = os.RemoveAll(alias)
+	if err := recent.CopyFileToDestination(src, alias); err != nil {
+		_ = os.RemoveAll(dir)
+		return "", err
+	}
+	return alias, nil
+}
+
+// tempFileName returns the sanitized name, with ext appended if it has none
+func tempFileName(name string, ext string) string {
+	name = SanitizeFileName(name)
+	if filepath.Ext(name) == "" {
+		name += ext
+	}
+	return name
+}
+
+// describeTempFile is how dry-run mode names the file createTempFile would create
+func describeTempFile(name string, ext string) string {
+	if name == "" {
+		return tempFilePattern + ext
+	}
+	return filepath.Join(namedDirPrefix+"*", tempFileName(name, ext))
+}
+
+// SanitizeFileName turns a user-supplied name into a safe file name: directories
+// are dropped, path separators and control characters become "_", and leading
+// dots are removed so the file isn't hidden. Returns "clippy" if nothing is left.
+func SanitizeFileName(name string) string {
+	name = filepath.Base(strings.TrimSpace(name))
+	name = strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ':' || r < 0x20 || r == 0x7f {
+			return '_'
+		}
+		return r
+	}, name)
+	name = strings.TrimLeft(name, ".")
+	if name == "" {
+		return "clippy"
+	}
+	return name
+}
+
+// isTempFile reports whether path is a file clippy created with createTempFile
+func isTempFile(path string) bool {
+	return strings.HasPrefix(filepath.Base(path), "clippy-") || isNamedDir(filepath.Dir(path))
+}
+
+// isNamedDir reports whether path is a directory createTempFile or createTempAlias
+// made to hold a named file
+func isNamedDir(path string) bool {
+	return strings.HasPrefix(filepath.Base(path), namedDirPrefix)
+}
+
+// removeTempFile deletes a stale temp file. Named directories are removed with
+// their contents; anything else goes through os.Remove, which refuses non-empty
+// directories, so a clippy-* directory that isn't ours survives.
+func removeTempFile(path string) error {
+	if isNamedDir(path) {
+		return os.RemoveAll(path)
+	}
+	return os.Remove(path)
+}
@@ -0,0 +1,23 @@
+package clippy
+
+import "strings"
+
+// templateEscapes expands the escapes a wrap template may contain, so a
+// single-quoted shell argument like '```\n{}\n```' can span lines
+var templateEscapes = strings.NewReplacer(`\\`, `\`, `\n`, "\n", `\t`, "\t")
+
+// ApplyTemplate wraps text in template: {} is replaced by text and {filename} by
+// filename, and \n, \t and \\ escapes are expanded. Replacement happens in one
+// pass, so braces inside text are left alone.
+func ApplyTemplate(template string, text string, filename string) string {
+	template = templateEscapes.Replace(template)
+	return strings.NewReplacer("{filename}", filename, "{}", text).Replace(template)
+}
+
+// wrapText applies opts.Wrap to piped text, if set
+func wrapText(text string, opts CopyOptions) string {
+	if opts.Wrap == "" {
+		return text
+	}
+	return ApplyTemplate(opts.Wrap, text, opts.Name)
+}
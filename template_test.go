@@ -0,0 +1,61 @@
+package clippy
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestApplyTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		text     string
+		filename string
+		want     string
+	}{
+		{"code fence", "```\\n{}\\n```", "x := 1", "", "```\nx := 1\n```"},
+		{"prefix and suffix", "> {} <", "quote", "", "> quote <"},
+		{"filename", "// {filename}\\n{}", "body", "main.go", "// main.go\nbody"},
+		{"braces in text are kept", "[{}]", "{filename} {}", "a.txt", "[{filename} {}]"},
+		{"escaped backslash", `C:\\{}`, "dir", "", `C:\dir`},
+		{"repeated placeholder", "{}{}", "ab", "", "abab"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ApplyTemplate(tt.template, tt.text, tt.filename); got != tt.want {
+				t.Errorf("ApplyTemplate(%q, %q, %q) = %q, want %q", tt.template, tt.text, tt.filename, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCopyDataWithWrap(t *testing.T) {
+	mem := useMemoryClipboard(t)
+	if err := CopyDataWithOptions(strings.NewReader(`{"key": "value"}`), t.TempDir(), CopyOptions{Wrap: "```json\\n{}\\n```"}); err != nil {
+		t.Fatalf("CopyDataWithOptions returned error: %v", err)
+	}
+	want := "```json\n{\"key\": \"value\"}\n```"
+	if got, ok := mem.GetText(); !ok || got != want {
+		t.Errorf("clipboard text = %q, %v, want %q", got, ok, want)
+	}
+	// The fenced result is no longer JSON, so it mustn't keep the JSON type
+	if mem.ContainsType("public.json") {
+		t.Errorf("clipboard types = %v, want the wrapped text re-detected", mem.GetClipboardTypes())
+	}
+}
+
+func TestCopyFileAsTextWithWrap(t *testing.T) {
+	mem := useMemoryClipboard(t)
+	path := filepath.Join(t.TempDir(), "notes.txt")
+	if err := os.WriteFile(path, []byte("hello"), 0644); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+	if err := CopyFileAsTextWithOptions(path, CopyOptions{Wrap: "{filename}: {}"}); err != nil {
+		t.Fatalf("CopyFileAsTextWithOptions returned error: %v", err)
+	}
+	if got, ok := mem.GetText(); !ok || got != "notes.txt: hello" {
+		t.Errorf("clipboard text = %q, %v, want %q", got, ok, "notes.txt: hello")
+	}
+}
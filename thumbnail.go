@@ -0,0 +1,49 @@
+package clippy
+
+import (
+	"fmt"
+	"image"
+	"net/url"
+	"os"
+
+	"github.com/neilberkman/clippy/pkg/clipboard"
+)
+
+// ThumbnailSize is the longest side, in pixels, of thumbnails made by
+// CopyFileWithThumbnail
+const ThumbnailSize = 256
+
+// CopyFileWithThumbnail copies a reference to an image file together with a
+// small PNG preview of it, as one clipboard item. The file reference comes first
+// so it stays the primary representation; targets that render image data for a
+// preview can use the thumbnail without loading the full image. PNG, JPEG, GIF
+// and TIFF files are supported.
+func CopyFileWithThumbnail(path string) error {
+	absPath, err := existingPath(path)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(absPath)
+	if err != nil {
+		return fmt.Errorf("could not open %s: %w", absPath, err)
+	}
+	img, _, err := image.Decode(f)
+	_ = f.Close()
+	if err != nil {
+		return fmt.Errorf("could not make a thumbnail of %s (PNG, JPEG, GIF and TIFF are supported): %w", absPath, err)
+	}
+	thumbnail, err := encodeImage(scaleToFit(img, ThumbnailSize, ThumbnailSize), ".png")
+	if err != nil {
+		return fmt.Errorf("could not make a thumbnail of %s: %w", absPath, err)
+	}
+
+	fileURL := (&url.URL{Scheme: "file", Path: absPath}).String()
+	if err := writeClipboardFlavors([]clipboard.Flavor{
+		{Type: "public.file-url", Data: []byte(fileURL)},
+		{Type: "public.png", Data: thumbnail},
+	}); err != nil {
+		return fmt.Errorf("could not copy file to clipboard: %w", err)
+	}
+	return nil
+}
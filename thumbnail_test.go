@@ -0,0 +1,47 @@
+package clippy
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCopyFileWithThumbnail(t *testing.T) {
+	mem := useMemoryClipboard(t)
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 1024, 512))); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "wide image.png")
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := CopyFileWithThumbnail(path); err != nil {
+		t.Fatalf("CopyFileWithThumbnail returned error: %v", err)
+	}
+	if files := mem.GetFiles(); len(files) != 1 || files[0] != path {
+		t.Errorf("clipboard files = %v, want [%s]", files, path)
+	}
+	if types := mem.GetClipboardTypes(); len(types) != 2 || types[0] != "public.file-url" {
+		t.Errorf("clipboard types = %v, want the file reference first", types)
+	}
+	data, ok := mem.GetClipboardDataForType("public.png")
+	if !ok {
+		t.Fatal("no thumbnail on the clipboard")
+	}
+	config, err := png.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Width != ThumbnailSize || config.Height != ThumbnailSize/2 {
+		t.Errorf("thumbnail is %dx%d, want %dx%d", config.Width, config.Height, ThumbnailSize, ThumbnailSize/2)
+	}
+
+	if err := CopyFileWithThumbnail("test-files/sample.txt"); err == nil {
+		t.Error("CopyFileWithThumbnail on a text file succeeded, want error")
+	}
+}
@@ -0,0 +1,49 @@
+package clippy
+
+import "strings"
+
+// TypeOverrides extends clippy's built-in type tables, for formats it doesn't
+// know. Keys and types are MIME types; they're matched case-insensitively.
+type TypeOverrides struct {
+	// UTIs maps MIME types to the UTI text of that type is copied as. It is
+	// consulted before the built-in mapping, so it can also replace an entry.
+	UTIs map[string]string
+
+	// TextTypes lists extra MIME types whose content is text, so piped data of
+	// that type (see CopyOptions.MimeType) is copied as text, not a temp file.
+	TextTypes []string
+}
+
+var typeOverrides TypeOverrides
+
+// SetTypeOverrides replaces the user type table and returns the previous one.
+// Pass TypeOverrides{} to go back to the built-in tables only.
+func SetTypeOverrides(overrides TypeOverrides) TypeOverrides {
+	previous := typeOverrides
+	normalized := TypeOverrides{UTIs: make(map[string]string, len(overrides.UTIs))}
+	for mime, uti := range overrides.UTIs {
+		normalized.UTIs[strings.ToLower(strings.TrimSpace(mime))] = strings.TrimSpace(uti)
+	}
+	for _, mime := range overrides.TextTypes {
+		normalized.TextTypes = append(normalized.TextTypes, strings.ToLower(strings.TrimSpace(mime)))
+	}
+	typeOverrides = normalized
+	return previous
+}
+
+// overrideUTI returns the user's UTI for a MIME type, if there is one
+func overrideUTI(mime string) (string, bool) {
+	uti, ok := typeOverrides.UTIs[strings.ToLower(mime)]
+	return uti, ok
+}
+
+// overrideIsText reports whether the user listed a MIME type as text
+func overrideIsText(mime string) bool {
+	mime = strings.ToLower(mime)
+	for _, t := range typeOverrides.TextTypes {
+		if t == mime {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package clippy
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+)
+
+func TestSetTypeOverrides(t *testing.T) {
+	mem := useMemoryClipboard(t)
+	defer SetTypeOverrides(TypeOverrides{})
+
+	if isTextualMimeType("application/x-myformat") {
+		t.Fatal("application/x-myformat is textual before any override")
+	}
+
+	previous := SetTypeOverrides(TypeOverrides{
+		UTIs:      map[string]string{"Application/X-MyFormat": "com.me.myformat", "application/json": "com.me.json"},
+		TextTypes: []string{"APPLICATION/X-MYFORMAT"},
+	})
+	if previous.UTIs != nil || previous.TextTypes != nil {
+		t.Errorf("previous overrides = %+v, want empty", previous)
+	}
+
+	if !isTextualMimeType("application/x-myformat") {
+		t.Error("application/x-myformat is not textual after the override")
+	}
+	if got := mimeToUTI("application/x-myformat"); got != "com.me.myformat" {
+		t.Errorf("mimeToUTI(application/x-myformat) = %q, want com.me.myformat", got)
+	}
+	if got := mimeToUTI("application/json"); got != "com.me.json" {
+		t.Errorf("mimeToUTI(application/json) = %q, want the override com.me.json", got)
+	}
+	if got := mimeToUTI("text/html"); got != "public.html" {
+		t.Errorf("mimeToUTI(text/html) = %q, want the built-in public.html", got)
+	}
+
+	// Piped data of the custom type is now copied as text of the custom UTI
+	if err := CopyDataWithOptions(bytes.NewReader([]byte("key: value")), t.TempDir(), CopyOptions{MimeType: "application/x-myformat"}); err != nil {
+		t.Fatalf("CopyDataWithOptions() error = %v", err)
+	}
+	if types := mem.GetClipboardTypes(); !slices.Contains(types, "com.me.myformat") {
+		t.Errorf("clipboard types = %v, want com.me.myformat", types)
+	}
+}
@@ -0,0 +1,149 @@
+package clippy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/neilberkman/clippy/pkg/clipboard"
+)
+
+// DefaultURLTimeout is the default time allowed for fetching a URL
+const DefaultURLTimeout = 30 * time.Second
+
+// URLOptions configures how a URL is fetched and copied
+type URLOptions struct {
+	Timeout  time.Duration // Total time allowed for the request (0 = DefaultURLTimeout)
+	TempDir  string        // Directory for temp files when the body is binary
+	MimeType string        // Overrides the server's Content-Type when set
+}
+
+// CopyURL fetches a URL and copies the response body to clipboard.
+// Text bodies become clipboard text, binary bodies become a file reference.
+func CopyURL(rawURL string) error {
+	_, err := CopyURLWithOptions(rawURL, URLOptions{})
+	return err
+}
+
+// CopyURLWithOptions is like CopyURL but allows a custom timeout, temp directory and MIME type.
+// Returns the size of the fetched body in bytes.
+func CopyURLWithOptions(rawURL string, opts URLOptions) (int64, error) {
+	data, contentType, err := fetchURL(rawURL, opts.Timeout)
+	if err != nil {
+		return 0, err
+	}
+
+	mimeHint := contentType
+	if opts.MimeType != "" {
+		mimeHint = opts.MimeType
+	}
+
+	if err := CopyDataWithMimeHint(bytes.NewReader(data), opts.TempDir, mimeHint); err != nil {
+		return 0, err
+	}
+	return int64(len(data)), nil
+}
+
+// fetchURL downloads the body of an http(s) URL, following redirects.
+// Returns the body and the media type from the Content-Type header (without parameters).
+func fetchURL(rawURL string, timeout time.Duration) ([]byte, string, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid URL %s: %w", rawURL, err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return nil, "", fmt.Errorf("unsupported URL scheme %q: only http and https are supported", parsed.Scheme)
+	}
+
+	if timeout <= 0 {
+		timeout = DefaultURLTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("could not create request for %s: %w", rawURL, err)
+	}
+
+	// The default client follows up to 10 redirects
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, "", fmt.Errorf("request to %s timed out after %v", rawURL, timeout)
+		}
+		return nil, "", fmt.Errorf("could not fetch %s: %w", rawURL, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, "", fmt.Errorf("could not fetch %s: server returned %s", rawURL, resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, "", fmt.Errorf("request to %s timed out after %v", rawURL, timeout)
+		}
+		return nil, "", fmt.Errorf("could not read response from %s: %w", rawURL, err)
+	}
+
+	contentType := ""
+	if header := resp.Header.Get("Content-Type"); header != "" {
+		if mediaType, _, err := mime.ParseMediaType(header); err == nil {
+			contentType = strings.ToLower(mediaType)
+		}
+	}
+
+	return data, contentType, nil
+}
+
+// ErrNoURL is returned when ClipboardURL finds no URL on the clipboard
+var ErrNoURL = errors.New("no URL found on clipboard")
+
+// urlPattern matches scheme://... URLs and mailto: links in free text
+var urlPattern = regexp.MustCompile(`(?i)\b(?:[a-z][a-z0-9+.-]*://|mailto:)[^\s<>"'\x60]+`)
+
+// ClipboardURL returns the URL on the clipboard: the public.url representation
+// browsers add when a link is copied, or else the first URL in the text
+func ClipboardURL() (string, error) {
+	if data, ok := clipboard.GetClipboardDataForType("public.url"); ok {
+		if u := strings.TrimSpace(string(data)); u != "" {
+			return u, nil
+		}
+	}
+	if text, ok := GetText(); ok {
+		if u := FindURL(text); u != "" {
+			return u, nil
+		}
+	}
+	return "", ErrNoURL
+}
+
+// FindURL returns the first URL in text, or "" if there is none. Punctuation
+// that ends a sentence, and a closing parenthesis the URL didn't open, is not
+// considered part of it.
+func FindURL(text string) string {
+	u := urlPattern.FindString(text)
+	for u != "" {
+		trimmed := strings.TrimRight(u, ".,;:!?")
+		if strings.HasSuffix(trimmed, ")") && strings.Count(trimmed, "(") < strings.Count(trimmed, ")") {
+			trimmed = strings.TrimSuffix(trimmed, ")")
+		}
+		if trimmed == u {
+			break
+		}
+		u = trimmed
+	}
+	return u
+}
@@ -0,0 +1,147 @@
+package clippy
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neilberkman/clippy/pkg/clipboard"
+)
+
+func TestFetchURL(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/data.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		_, _ = w.Write([]byte(`{"key": "value"}`))
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/data.json", http.StatusFound)
+	})
+	mux.HandleFunc("/missing", func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		_, _ = w.Write([]byte("too late"))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	t.Run("content type", func(t *testing.T) {
+		data, contentType, err := fetchURL(server.URL+"/data.json", time.Second)
+		if err != nil {
+			t.Fatalf("fetchURL returned error: %v", err)
+		}
+		if string(data) != `{"key": "value"}` {
+			t.Errorf("unexpected body: %q", string(data))
+		}
+		if contentType != "application/json" {
+			t.Errorf("contentType = %q, want %q", contentType, "application/json")
+		}
+	})
+
+	t.Run("follows redirects", func(t *testing.T) {
+		data, _, err := fetchURL(server.URL+"/redirect", time.Second)
+		if err != nil {
+			t.Fatalf("fetchURL returned error: %v", err)
+		}
+		if string(data) != `{"key": "value"}` {
+			t.Errorf("unexpected body after redirect: %q", string(data))
+		}
+	})
+
+	t.Run("non-200 status", func(t *testing.T) {
+		_, _, err := fetchURL(server.URL+"/missing", time.Second)
+		if err == nil || !strings.Contains(err.Error(), "404") {
+			t.Errorf("expected 404 error, got %v", err)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		_, _, err := fetchURL(server.URL+"/slow", 50*time.Millisecond)
+		if err == nil || !strings.Contains(err.Error(), "timed out") {
+			t.Errorf("expected timeout error, got %v", err)
+		}
+	})
+
+	t.Run("unsupported scheme", func(t *testing.T) {
+		_, _, err := fetchURL("ftp://example.com/file.txt", time.Second)
+		if err == nil {
+			t.Error("expected error for ftp URL")
+		}
+	})
+}
+
+func TestLookupMimeHint(t *testing.T) {
+	tests := []struct {
+		hint string
+		want string
+	}{
+		{"", ""},
+		{"application/octet-stream", ""},
+		{"application/x-not-a-real-type", ""},
+		{"image/png", "image/png"},
+		{"Text/HTML; charset=utf-8", "text/html"},
+	}
+
+	for _, tt := range tests {
+		got := lookupMimeHint(tt.hint)
+		gotStr := ""
+		if got != nil {
+			gotStr = got.String()
+		}
+		if !strings.HasPrefix(gotStr, tt.want) || (tt.want == "" && gotStr != "") {
+			t.Errorf("lookupMimeHint(%q) = %q, want %q", tt.hint, gotStr, tt.want)
+		}
+	}
+}
+
+func TestFindURL(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"https://example.com/page?q=1", "https://example.com/page?q=1"},
+		{"See https://example.com/docs.", "https://example.com/docs"},
+		{"(details at https://en.wikipedia.org/wiki/Go_(programming_language))", "https://en.wikipedia.org/wiki/Go_(programming_language)"},
+		{"(see https://example.com)", "https://example.com"},
+		{"first ftp://files.example.com/a then https://b.example.com", "ftp://files.example.com/a"},
+		{"mail mailto:me@example.com!", "mailto:me@example.com"},
+		{"<a href=\"https://example.com/x\">", "https://example.com/x"},
+		{"no links here", ""},
+	}
+	for _, tt := range tests {
+		if got := FindURL(tt.text); got != tt.want {
+			t.Errorf("FindURL(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestClipboardURL(t *testing.T) {
+	mem := useMemoryClipboard(t)
+
+	if _, err := ClipboardURL(); !errors.Is(err, ErrNoURL) {
+		t.Errorf("empty clipboard error = %v, want ErrNoURL", err)
+	}
+
+	if err := mem.CopyText("Read this: https://example.com/post"); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := ClipboardURL(); err != nil || got != "https://example.com/post" {
+		t.Errorf("ClipboardURL() = %q, %v, want the URL in the text", got, err)
+	}
+
+	// The public.url representation wins over the text (a link's title)
+	if err := mem.CopyFlavors([]clipboard.Flavor{
+		{Type: "public.url", Data: []byte("https://example.com/real")},
+		{Type: clipboard.PlainTextType, Data: []byte("Link title")},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := ClipboardURL(); err != nil || got != "https://example.com/real" {
+		t.Errorf("ClipboardURL() = %q, %v, want the public.url flavor", got, err)
+	}
+}
@@ -0,0 +1,85 @@
+package clippy
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/neilberkman/clippy/pkg/recent"
+)
+
+// copyAndVerify copies src to dst and checks the copy is byte-identical using
+// SHA-256. The source is hashed while it is copied, so only the destination is
+// read back. Directories are compared file by file after the copy.
+func copyAndVerify(src, dst string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+
+	if info.IsDir() {
+		if err := recent.CopyFileToDestination(src, dst); err != nil {
+			return err
+		}
+		return verifyTree(src, dst)
+	}
+
+	h := sha256.New()
+	if err := recent.CopyFileWithHash(src, dst, h); err != nil {
+		return err
+	}
+	return verifyHash(dst, h.Sum(nil))
+}
+
+// verifyTree checks every regular file under src has an identical copy under dst
+func verifyTree(src, dst string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		relPath, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		sum, err := hashFile(path)
+		if err != nil {
+			return err
+		}
+		return verifyHash(filepath.Join(dst, relPath), sum)
+	})
+}
+
+// verifyHash returns an error if the SHA-256 of path differs from want
+func verifyHash(path string, want []byte) error {
+	got, err := hashFile(path)
+	if err != nil {
+		return fmt.Errorf("could not verify %s: %w", path, err)
+	}
+	if !bytes.Equal(got, want) {
+		return fmt.Errorf("verification failed: %s does not match its source", path)
+	}
+	return nil
+}
+
+// hashFile returns the SHA-256 of a file's contents
+func hashFile(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
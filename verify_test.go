@@ -0,0 +1,46 @@
+package clippy
+
+import (
+	"crypto/sha256"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCopyAndVerify(t *testing.T) {
+	srcDir := t.TempDir()
+	srcFile := filepath.Join(srcDir, "data.bin")
+	if err := os.WriteFile(srcFile, []byte("important bytes"), 0644); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(srcDir, "nested"), 0755); err != nil {
+		t.Fatalf("Failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(srcDir, "nested", "inner.txt"), []byte("inner"), 0644); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+
+	destDir := t.TempDir()
+	if err := copyAndVerify(srcFile, filepath.Join(destDir, "data.bin")); err != nil {
+		t.Errorf("copyAndVerify(file) returned error: %v", err)
+	}
+	if err := copyAndVerify(srcDir, filepath.Join(destDir, "tree")); err != nil {
+		t.Errorf("copyAndVerify(dir) returned error: %v", err)
+	}
+}
+
+func TestVerifyHashMismatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "truncated.bin")
+	if err := os.WriteFile(path, []byte("important"), 0644); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+
+	want := sha256.Sum256([]byte("important bytes"))
+	if err := verifyHash(path, want[:]); err == nil {
+		t.Error("verifyHash succeeded for a truncated copy, want error")
+	}
+	want = sha256.Sum256([]byte("important"))
+	if err := verifyHash(path, want[:]); err != nil {
+		t.Errorf("verifyHash returned error for an identical copy: %v", err)
+	}
+}